@@ -16,3 +16,11 @@ func PrintNextSteps(out io.Writer, messages []string) {
 		_, _ = fmt.Fprintln(out, "   ", n)
 	}
 }
+
+// PrintWarnings prints the warnings returned by plugin hooks that are
+// invoked before a command is executed, each prefixed with "WARNING:".
+func PrintWarnings(out io.Writer, messages []string) {
+	for _, n := range messages {
+		_, _ = fmt.Fprintln(out, "WARNING:", n)
+	}
+}
@@ -0,0 +1,42 @@
+package hooks
+
+import (
+	"context"
+	"sync"
+)
+
+type recorderKey struct{}
+
+// Recorder records structured results of a command, which are passed
+// to hooks that are invoked after the command was executed.
+type Recorder struct {
+	mu          sync.Mutex
+	imageDigest string
+}
+
+// WithRecorder returns a copy of ctx with a new [Recorder] attached.
+func WithRecorder(ctx context.Context) (context.Context, *Recorder) {
+	r := &Recorder{}
+	return context.WithValue(ctx, recorderKey{}, r), r
+}
+
+// ImageDigest returns the digest of the image resolved by the command,
+// if any.
+func (r *Recorder) ImageDigest() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.imageDigest
+}
+
+// SetImageDigest records the digest of the image resolved by the command
+// (for example, the digest of the image pushed by "docker push"). It is
+// a no-op if no [Recorder] is attached to ctx.
+func SetImageDigest(ctx context.Context, digest string) {
+	r, ok := ctx.Value(recorderKey{}).(*Recorder)
+	if !ok {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.imageDigest = digest
+}
@@ -15,6 +15,13 @@
 
 const (
 	NextSteps = iota
+	// Warning is used by hooks that are invoked before a command is
+	// executed to print a warning. The command is executed as usual.
+	Warning
+	// Veto is used by hooks that are invoked before a command is
+	// executed to prevent the command from being executed. The hook's
+	// message is returned as an error.
+	Veto
 )
 
 // HookMessage represents a plugin hook response. Plugins
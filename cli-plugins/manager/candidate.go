@@ -1,6 +1,7 @@
 package manager
 
 import (
+	"os"
 	"os/exec"
 
 	"github.com/docker/cli/cli-plugins/metadata"
@@ -14,6 +15,22 @@ func (c *candidate) Path() string {
 	return c.path
 }
 
+// Metadata returns the plugin's metadata. Metadata is cached on disk to
+// prevent executing the plugin each time, and invalidated when the plugin
+// binary is modified.
 func (c *candidate) Metadata() ([]byte, error) {
-	return exec.Command(c.path, metadata.MetadataSubcommandName).Output() // #nosec G204 -- ignore "Subprocess launched with a potential tainted input or cmd arguments"
+	fi, err := os.Stat(c.path)
+	if err == nil {
+		if meta, ok := readCachedMetadata(c.path, fi); ok {
+			return meta, nil
+		}
+	}
+	meta, err := exec.Command(c.path, metadata.MetadataSubcommandName).Output() // #nosec G204 -- ignore "Subprocess launched with a potential tainted input or cmd arguments"
+	if err != nil {
+		return nil, err
+	}
+	if fi != nil {
+		writeCachedMetadata(c.path, fi, meta)
+	}
+	return meta, nil
 }
@@ -2,14 +2,19 @@
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"reflect"
+	"runtime"
 	"strings"
 	"testing"
 
 	"github.com/docker/cli/cli-plugins/metadata"
+	"github.com/docker/cli/cli/config"
 	"github.com/spf13/cobra"
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/skip"
 )
 
 type fakeCandidate struct {
@@ -148,3 +153,62 @@ func TestCandidatePath(t *testing.T) {
 	cand := &candidate{path: exp}
 	assert.Equal(t, exp, cand.Path())
 }
+
+func TestCandidateMetadataCache(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "requires a shell-script plugin")
+	config.SetDir(t.TempDir())
+
+	dir := t.TempDir()
+	counter := filepath.Join(dir, "invocations")
+	pluginPath := filepath.Join(dir, metadata.NamePrefix+"cached")
+	writePlugin := func(vendor string) {
+		t.Helper()
+		script := `#!/bin/sh
+echo x >> "` + counter + `"
+echo '{"SchemaVersion":"0.1.0","Vendor":"` + vendor + `"}'
+`
+		assert.NilError(t, os.WriteFile(pluginPath, []byte(script), 0o755))
+	}
+	invocations := func() int {
+		t.Helper()
+		data, err := os.ReadFile(counter)
+		assert.NilError(t, err)
+		return strings.Count(string(data), "x")
+	}
+
+	writePlugin("Example")
+	c := &candidate{path: pluginPath}
+	for i := 0; i < 2; i++ {
+		meta, err := c.Metadata()
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(strings.TrimSpace(string(meta)), `{"SchemaVersion":"0.1.0","Vendor":"Example"}`))
+	}
+	assert.Check(t, is.Equal(invocations(), 1), "expected metadata to be cached")
+
+	// Modifying the plugin binary invalidates the cache.
+	writePlugin("Another Vendor")
+	meta, err := c.Metadata()
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(strings.TrimSpace(string(meta)), `{"SchemaVersion":"0.1.0","Vendor":"Another Vendor"}`))
+	assert.Check(t, is.Equal(invocations(), 2))
+}
+
+func TestPruneMetadataCache(t *testing.T) {
+	config.SetDir(t.TempDir())
+
+	dir := t.TempDir()
+	installed := filepath.Join(dir, metadata.NamePrefix+"installed")
+	removed := filepath.Join(dir, metadata.NamePrefix+"removed")
+	for _, p := range []string{installed, removed} {
+		assert.NilError(t, os.WriteFile(p, []byte("#!/bin/sh\n"), 0o755))
+		fi, err := os.Stat(p)
+		assert.NilError(t, err)
+		writeCachedMetadata(p, fi, []byte(`{"SchemaVersion":"0.1.0"}`))
+	}
+
+	pruneMetadataCache(map[string][]string{"installed": {installed}})
+	_, err := os.Stat(metadataCachePath(installed))
+	assert.Check(t, err)
+	_, err = os.Stat(metadataCachePath(removed))
+	assert.Check(t, os.IsNotExist(err))
+}
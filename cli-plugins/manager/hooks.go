@@ -3,7 +3,12 @@
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/docker/cli/cli-plugins/hooks"
 	"github.com/docker/cli/cli/config"
@@ -13,9 +18,30 @@
 	"github.com/spf13/pflag"
 )
 
+// HookStage is the stage of the command execution that hooks are
+// invoked for.
+type HookStage string
+
+const (
+	// HookStagePre is the stage for hooks that are invoked before a
+	// command is executed. These hooks are configured through the
+	// "pre-hooks" option of a plugin in the CLI's configuration file.
+	HookStagePre HookStage = "pre"
+	// HookStagePost is the stage for hooks that are invoked after a
+	// command was executed. These hooks are configured through the
+	// "hooks" option of a plugin in the CLI's configuration file.
+	HookStagePost HookStage = "post"
+)
+
+// hooksTimeout is the default maximum amount of time to wait for plugin
+// hooks, which are invoked in parallel. It can be changed for each plugin
+// with the "hooks-timeout" option of the plugin in the CLI's configuration
+// file.
+const hooksTimeout = 2 * time.Second
+
 // HookPluginData is the type representing the information
 // that plugins declaring support for hooks get passed when
-// being invoked following a CLI command execution.
+// being invoked before or after a CLI command execution.
 type HookPluginData struct {
 	// RootCmd is a string representing the matching hook configuration
 	// which is currently being invoked. If a hook for `docker context` is
@@ -24,34 +50,169 @@ type HookPluginData struct {
 	RootCmd      string
 	Flags        map[string]string
 	CommandError string
+
+	// Stage is the stage of the command execution the hook is invoked
+	// for. It is empty for hooks invoked by older versions of the CLI,
+	// which only supported hooks invoked after a command was executed.
+	Stage HookStage `json:",omitempty"`
+	// Args contains the positional arguments passed to the command.
+	Args []string `json:",omitempty"`
+	// Result contains the result of the command. It is only set for hooks
+	// invoked after a command was executed.
+	Result *CommandResult `json:",omitempty"`
+}
+
+// CommandResult contains structured information about the result of a
+// command, which is passed to hooks invoked after the command was executed.
+type CommandResult struct {
+	ExitCode int
+	// Duration is the time it took to execute the command.
+	Duration time.Duration
+	// ImageDigest is the digest of the image resolved by the command (for
+	// example, the digest of the image pushed by "docker push"), if any.
+	ImageDigest string `json:",omitempty"`
+}
+
+// RunCLICommandPreHooks is the entrypoint into the hooks execution flow
+// before a main CLI command is executed. It calls the hook subcommand for
+// all CLI plugins that are configured with "pre-hooks" matching the command,
+// and prints their warnings. It returns an error if a plugin vetoes the
+// execution of the command.
+//
+// A warning is printed for plugins that fail, or do not respond in time,
+// and the command is executed. Plugins that are configured with
+// "pre-hooks-required" set to "true" block the command instead.
+func RunCLICommandPreHooks(ctx context.Context, dockerCLI config.Provider, rootCmd, subCommand *cobra.Command, args []string) error {
+	commandName := strings.TrimPrefix(subCommand.CommandPath(), rootCmd.Name()+" ")
+	flags := getCommandFlags(subCommand)
+
+	return runPreHooks(ctx, dockerCLI.ConfigFile(), rootCmd, subCommand, commandName, flags, args)
+}
+
+// RunPluginPreHooks is the entrypoint for the hooks execution flow
+// before a plugin command is executed by the CLI.
+func RunPluginPreHooks(ctx context.Context, dockerCLI config.Provider, rootCmd, subCommand *cobra.Command, args []string) error {
+	commandName := strings.Join(args, " ")
+	flags := getNaiveFlags(args)
+
+	return runPreHooks(ctx, dockerCLI.ConfigFile(), rootCmd, subCommand, commandName, flags, args)
 }
 
 // RunCLICommandHooks is the entrypoint into the hooks execution flow after
 // a main CLI command was executed. It calls the hook subcommand for all
 // present CLI plugins that declare support for hooks in their metadata and
 // parses/prints their responses.
-func RunCLICommandHooks(ctx context.Context, dockerCLI config.Provider, rootCmd, subCommand *cobra.Command, cmdErrorMessage string) {
+func RunCLICommandHooks(ctx context.Context, dockerCLI config.Provider, rootCmd, subCommand *cobra.Command, result CommandResult, cmdErrorMessage string) {
 	commandName := strings.TrimPrefix(subCommand.CommandPath(), rootCmd.Name()+" ")
 	flags := getCommandFlags(subCommand)
 
-	runHooks(ctx, dockerCLI.ConfigFile(), rootCmd, subCommand, commandName, flags, cmdErrorMessage)
+	runHooks(ctx, dockerCLI.ConfigFile(), rootCmd, subCommand, commandName, flags, subCommand.Flags().Args(), result, cmdErrorMessage)
 }
 
 // RunPluginHooks is the entrypoint for the hooks execution flow
 // after a plugin command was just executed by the CLI.
-func RunPluginHooks(ctx context.Context, dockerCLI config.Provider, rootCmd, subCommand *cobra.Command, args []string) {
+func RunPluginHooks(ctx context.Context, dockerCLI config.Provider, rootCmd, subCommand *cobra.Command, args []string, result CommandResult) {
 	commandName := strings.Join(args, " ")
 	flags := getNaiveFlags(args)
 
-	runHooks(ctx, dockerCLI.ConfigFile(), rootCmd, subCommand, commandName, flags, "")
+	runHooks(ctx, dockerCLI.ConfigFile(), rootCmd, subCommand, commandName, flags, args, result, "")
 }
 
-func runHooks(ctx context.Context, cfg *configfile.ConfigFile, rootCmd, subCommand *cobra.Command, invokedCommand string, flags map[string]string, cmdErrorMessage string) {
-	nextSteps := invokeAndCollectHooks(ctx, cfg, rootCmd, subCommand, invokedCommand, flags, cmdErrorMessage)
+func runPreHooks(ctx context.Context, cfg *configfile.ConfigFile, rootCmd, subCommand *cobra.Command, invokedCommand string, flags map[string]string, args []string) error {
+	responses := invokeHooks(ctx, cfg, rootCmd, subCommand, invokedCommand, HookPluginData{
+		Stage: HookStagePre,
+		Flags: flags,
+		Args:  args,
+	})
+
+	var warnings, vetoes []string
+	for _, r := range responses {
+		if r.err != nil {
+			if isTrue(cfg.Plugins[r.pluginName]["pre-hooks-required"]) {
+				vetoes = append(vetoes, r.pluginName+": plugin did not respond: "+r.err.Error())
+			} else {
+				warnings = append(warnings, "plugin "+r.pluginName+" did not respond to the pre-command hook: "+r.err.Error())
+			}
+			continue
+		}
+		switch r.hookType {
+		case hooks.Warning:
+			warnings = append(warnings, r.message...)
+		case hooks.Veto:
+			vetoes = append(vetoes, r.pluginName+": "+strings.Join(r.message, "\n"))
+		}
+	}
+	hooks.PrintWarnings(subCommand.ErrOrStderr(), warnings)
+	if len(vetoes) > 0 {
+		return errors.New("command was blocked by plugin " + strings.Join(vetoes, "; "))
+	}
+	return nil
+}
+
+func runHooks(ctx context.Context, cfg *configfile.ConfigFile, rootCmd, subCommand *cobra.Command, invokedCommand string, flags map[string]string, args []string, result CommandResult, cmdErrorMessage string) {
+	nextSteps := invokeAndCollectHooks(ctx, cfg, rootCmd, subCommand, invokedCommand, HookPluginData{
+		Stage:        HookStagePost,
+		Flags:        flags,
+		Args:         args,
+		Result:       &result,
+		CommandError: cmdErrorMessage,
+	})
 	hooks.PrintNextSteps(subCommand.ErrOrStderr(), nextSteps)
 }
 
-func invokeAndCollectHooks(ctx context.Context, cfg *configfile.ConfigFile, rootCmd, subCmd *cobra.Command, subCmdStr string, flags map[string]string, cmdErrorMessage string) []string {
+func invokeAndCollectHooks(ctx context.Context, cfg *configfile.ConfigFile, rootCmd, subCmd *cobra.Command, subCmdStr string, hookData HookPluginData) []string {
+	responses := invokeHooks(ctx, cfg, rootCmd, subCmd, subCmdStr, hookData)
+	nextSteps := make([]string, 0, len(responses))
+	for _, r := range responses {
+		// currently the only hook type for hooks invoked after a command.
+		if r.err != nil || r.hookType != hooks.NextSteps {
+			continue
+		}
+		var appended bool
+		nextSteps, appended = appendNextSteps(nextSteps, r.message)
+		if !appended {
+			logrus.Debugf("Plugin %s responded with an empty hook message. Ignoring.", r.pluginName)
+		}
+	}
+	return nextSteps
+}
+
+// hookResponse is the processed response of a plugin's hook.
+type hookResponse struct {
+	pluginName string
+	hookType   hooks.HookType
+	message    []string
+
+	// err is set if the plugin failed, or did not respond in time.
+	err error
+}
+
+// hookTimeout returns the maximum amount of time to wait for the hooks of a
+// plugin, as configured with the "hooks-timeout" option, or [hooksTimeout].
+func hookTimeout(pluginName string, pluginCfg map[string]string) time.Duration {
+	v, ok := pluginCfg["hooks-timeout"]
+	if !ok {
+		return hooksTimeout
+	}
+	timeout, err := time.ParseDuration(v)
+	if err != nil || timeout <= 0 {
+		logrus.Debugf("Plugin %s: invalid hooks-timeout %q, using the default of %s", pluginName, v, hooksTimeout)
+		return hooksTimeout
+	}
+	return timeout
+}
+
+func isTrue(v string) bool {
+	b, _ := strconv.ParseBool(v)
+	return b
+}
+
+// invokeHooks invokes the hook subcommand of all plugins that have hooks
+// configured for the given command and stage. Plugins are invoked in parallel,
+// and the response of plugins that fail, or do not respond within their
+// [hookTimeout], have err set. Responses that are not valid for the stage
+// are ignored. Responses are returned in order of plugin name.
+func invokeHooks(ctx context.Context, cfg *configfile.ConfigFile, rootCmd, subCmd *cobra.Command, subCmdStr string, hookData HookPluginData) []hookResponse {
 	// check if the context was cancelled before invoking hooks
 	select {
 	case <-ctx.Done():
@@ -64,52 +225,129 @@ func invokeAndCollectHooks(ctx context.Context, cfg *configfile.ConfigFile, root
 		return nil
 	}
 
+	configKey := "hooks"
+	if hookData.Stage == HookStagePre {
+		configKey = "pre-hooks"
+	}
+
+	pluginNames := make([]string, 0, len(pluginsCfg))
+	for pluginName := range pluginsCfg {
+		pluginNames = append(pluginNames, pluginName)
+	}
+	sort.Strings(pluginNames)
+
+	type indexedResponse struct {
+		index    int
+		response *hookResponse
+	}
+
+	// Each plugin is given its own timeout. The overall wait is bounded by
+	// the longest timeout, in case a plugin does not exit when its timeout
+	// expires.
+	var maxTimeout time.Duration
 	pluginDirs := getPluginDirs(cfg)
-	nextSteps := make([]string, 0, len(pluginsCfg))
-	for pluginName, pluginCfg := range pluginsCfg {
-		match, ok := pluginMatch(pluginCfg, subCmdStr)
+	ch := make(chan indexedResponse, len(pluginNames))
+	responses := make([]*hookResponse, len(pluginNames))
+	var pending int
+	for i, pluginName := range pluginNames {
+		match, ok := matchHook(pluginsCfg[pluginName], configKey, subCmdStr)
 		if !ok {
 			continue
 		}
+		data := hookData
+		data.RootCmd = match
 
-		p, err := getPlugin(pluginName, pluginDirs, rootCmd)
-		if err != nil {
-			continue
+		timeout := hookTimeout(pluginName, pluginsCfg[pluginName])
+		if timeout > maxTimeout {
+			maxTimeout = timeout
 		}
-
-		hookReturn, err := p.RunHook(ctx, HookPluginData{
-			RootCmd:      match,
-			Flags:        flags,
-			CommandError: cmdErrorMessage,
-		})
-		if err != nil {
-			// skip misbehaving plugins, but don't halt execution
-			continue
+		responses[i] = &hookResponse{
+			pluginName: pluginName,
+			err:        fmt.Errorf("timed out after %s", timeout),
 		}
+		pending++
+		go func(i int, pluginName string) {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			ch <- indexedResponse{index: i, response: invokeHook(ctx, pluginName, pluginDirs, rootCmd, subCmd, data)}
+		}(i, pluginName)
+	}
 
-		var hookMessageData hooks.HookMessage
-		err = json.Unmarshal(hookReturn, &hookMessageData)
-		if err != nil {
-			continue
+	timer := time.NewTimer(maxTimeout)
+	defer timer.Stop()
+collect:
+	for ; pending > 0; pending-- {
+		select {
+		case r := <-ch:
+			responses[r.index] = r.response
+		case <-timer.C:
+			logrus.Debugf("Timed out waiting for %d plugin hook(s)", pending)
+			break collect
+		case <-ctx.Done():
+			break collect
 		}
+	}
 
-		// currently the only hook type
-		if hookMessageData.Type != hooks.NextSteps {
-			continue
+	result := make([]hookResponse, 0, len(responses))
+	for _, r := range responses {
+		if r != nil {
+			result = append(result, *r)
 		}
+	}
+	return result
+}
 
-		processedHook, err := hooks.ParseTemplate(hookMessageData.Template, subCmd)
-		if err != nil {
-			continue
+// invokeHook invokes the hook subcommand of a single plugin, and returns
+// its processed response, or nil if the response is not valid for the
+// stage. The err field of the response is set if the plugin misbehaved.
+func invokeHook(ctx context.Context, pluginName string, pluginDirs []string, rootCmd, subCmd *cobra.Command, hookData HookPluginData) *hookResponse {
+	p, err := getPlugin(pluginName, pluginDirs, rootCmd)
+	if err != nil {
+		return &hookResponse{pluginName: pluginName, err: err}
+	}
+
+	start := time.Now()
+	hookReturn, err := p.RunHook(ctx, hookData)
+	if err != nil {
+		// skip misbehaving plugins, but don't halt execution
+		logrus.Debugf("Plugin %s %s-hook failed after %s: %v", pluginName, hookData.Stage, time.Since(start), err)
+		if ctx.Err() != nil {
+			err = fmt.Errorf("timed out after %s", time.Since(start).Round(time.Millisecond))
 		}
+		return &hookResponse{pluginName: pluginName, err: err}
+	}
+	logrus.Debugf("Plugin %s %s-hook completed in %s", pluginName, hookData.Stage, time.Since(start))
 
-		var appended bool
-		nextSteps, appended = appendNextSteps(nextSteps, processedHook)
-		if !appended {
-			logrus.Debugf("Plugin %s responded with an empty hook message %q. Ignoring.", pluginName, string(hookReturn))
+	var hookMessageData hooks.HookMessage
+	err = json.Unmarshal(hookReturn, &hookMessageData)
+	if err != nil {
+		return &hookResponse{pluginName: pluginName, err: fmt.Errorf("invalid response: %w", err)}
+	}
+
+	// Only accept hook types that are supported for the stage the hook
+	// is invoked for.
+	switch hookMessageData.Type {
+	case hooks.NextSteps:
+		if hookData.Stage == HookStagePre {
+			return nil
 		}
+	case hooks.Warning, hooks.Veto:
+		if hookData.Stage != HookStagePre {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	processedHook, err := hooks.ParseTemplate(hookMessageData.Template, subCmd)
+	if err != nil {
+		return &hookResponse{pluginName: pluginName, err: fmt.Errorf("invalid response: %w", err)}
+	}
+	return &hookResponse{
+		pluginName: pluginName,
+		hookType:   hookMessageData.Type,
+		message:    processedHook,
 	}
-	return nextSteps
 }
 
 // appendNextSteps appends the processed hook output to the nextSteps slice.
@@ -136,7 +374,13 @@ func appendNextSteps(nextSteps []string, processed []string) ([]string, bool) {
 // and, if the configuration includes a hook for the invoked command, returns
 // the configured hook string.
 func pluginMatch(pluginCfg map[string]string, subCmd string) (string, bool) {
-	configuredPluginHooks, ok := pluginCfg["hooks"]
+	return matchHook(pluginCfg, "hooks", subCmd)
+}
+
+// matchHook is the implementation of [pluginMatch] for the given
+// configuration key ("hooks" or "pre-hooks").
+func matchHook(pluginCfg map[string]string, configKey string, subCmd string) (string, bool) {
+	configuredPluginHooks, ok := pluginCfg[configKey]
 	if !ok || configuredPluginHooks == "" {
 		return "", false
 	}
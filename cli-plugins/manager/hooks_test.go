@@ -1,10 +1,21 @@
 package manager
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
+	"time"
 
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/spf13/cobra"
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/skip"
 )
 
 func TestGetNaiveFlags(t *testing.T) {
@@ -141,3 +152,157 @@ func TestAppendNextSteps(t *testing.T) {
 		})
 	}
 }
+
+// writeHookPlugin writes a shell-script plugin to dir that responds to
+// hook invocations with the given hook message, and writes the hook
+// data it was invoked with to a "<name>.json" file in dir.
+func writeHookPlugin(t *testing.T, dir, name, hookMessage string) {
+	t.Helper()
+	script := `#!/bin/sh
+if [ "$1" = "docker-cli-plugin-metadata" ]; then
+	echo '{"SchemaVersion":"0.1.0","Vendor":"Example"}'
+	exit 0
+fi
+printf '%s' "$3" > "` + filepath.Join(dir, name+".json") + `"
+echo '` + hookMessage + `'
+`
+	err := os.WriteFile(filepath.Join(dir, "docker-"+name), []byte(script), 0o755)
+	assert.NilError(t, err)
+}
+
+func TestRunPreHooks(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "requires shell-script plugins")
+	config.SetDir(t.TempDir())
+
+	dir := t.TempDir()
+	writeHookPlugin(t, dir, "warner", `{"Type":1,"Template":"pushing {{arg . 0}}"}`)
+	writeHookPlugin(t, dir, "blocker", `{"Type":2,"Template":"registry is not approved"}`)
+	writeHookPlugin(t, dir, "nexter", `{"Type":0,"Template":"ignored before the command"}`)
+	cfg := &configfile.ConfigFile{
+		CLIPluginsExtraDirs: []string{dir},
+		Plugins: map[string]map[string]string{
+			"warner":  {"pre-hooks": "push"},
+			"blocker": {"pre-hooks": "push", "hooks": "push"},
+			"nexter":  {"pre-hooks": "push"},
+		},
+	}
+
+	var stderr bytes.Buffer
+	root := &cobra.Command{Use: "docker"}
+	subCmd := &cobra.Command{Use: "push"}
+	root.AddCommand(subCmd)
+	subCmd.SetErr(&stderr)
+	assert.NilError(t, subCmd.ParseFlags([]string{"example.com/foo"}))
+
+	err := runPreHooks(context.Background(), cfg, root, subCmd, "push", map[string]string{}, []string{"example.com/foo"})
+	assert.Check(t, is.Error(err, "command was blocked by plugin blocker: registry is not approved"))
+	assert.Check(t, is.Equal(stderr.String(), "WARNING: pushing example.com/foo\n"))
+
+	raw, err := os.ReadFile(filepath.Join(dir, "warner.json"))
+	assert.NilError(t, err)
+	var hookData HookPluginData
+	assert.NilError(t, json.Unmarshal(raw, &hookData))
+	assert.Check(t, is.DeepEqual(hookData, HookPluginData{
+		RootCmd: "push",
+		Flags:   map[string]string{},
+		Stage:   HookStagePre,
+		Args:    []string{"example.com/foo"},
+	}))
+}
+
+func TestRunPreHooksNoResponse(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "requires shell-script plugins")
+	config.SetDir(t.TempDir())
+
+	dir := t.TempDir()
+	for name, body := range map[string]string{
+		"failer":  "exit 1",
+		"sleeper": "exec sleep 5",
+	} {
+		script := "#!/bin/sh\nif [ \"$1\" = \"docker-cli-plugin-metadata\" ]; then\n\techo '{\"SchemaVersion\":\"0.1.0\",\"Vendor\":\"Example\"}'\n\texit 0\nfi\n" + body + "\n"
+		assert.NilError(t, os.WriteFile(filepath.Join(dir, "docker-"+name), []byte(script), 0o755))
+	}
+
+	run := func(cfg *configfile.ConfigFile) (string, error) {
+		var stderr bytes.Buffer
+		root := &cobra.Command{Use: "docker"}
+		subCmd := &cobra.Command{Use: "push"}
+		root.AddCommand(subCmd)
+		subCmd.SetErr(&stderr)
+		err := runPreHooks(context.Background(), cfg, root, subCmd, "push", map[string]string{}, nil)
+		return stderr.String(), err
+	}
+
+	t.Run("warn", func(t *testing.T) {
+		stderr, err := run(&configfile.ConfigFile{
+			CLIPluginsExtraDirs: []string{dir},
+			Plugins: map[string]map[string]string{
+				"failer":  {"pre-hooks": "push"},
+				"sleeper": {"pre-hooks": "push", "hooks-timeout": "100ms"},
+			},
+		})
+		assert.Check(t, err)
+		assert.Check(t, is.Contains(stderr, "WARNING: plugin failer did not respond to the pre-command hook: "))
+		assert.Check(t, is.Contains(stderr, "WARNING: plugin sleeper did not respond to the pre-command hook: timed out after "))
+	})
+
+	t.Run("required", func(t *testing.T) {
+		start := time.Now()
+		stderr, err := run(&configfile.ConfigFile{
+			CLIPluginsExtraDirs: []string{dir},
+			Plugins: map[string]map[string]string{
+				"sleeper": {"pre-hooks": "push", "hooks-timeout": "100ms", "pre-hooks-required": "true"},
+			},
+		})
+		assert.Check(t, is.ErrorContains(err, "command was blocked by plugin sleeper: plugin did not respond: timed out after "))
+		assert.Check(t, is.Equal(stderr, ""))
+		assert.Check(t, time.Since(start) < 4*time.Second)
+	})
+}
+
+func TestHookTimeout(t *testing.T) {
+	assert.Check(t, is.Equal(hookTimeout("p", map[string]string{}), hooksTimeout))
+	assert.Check(t, is.Equal(hookTimeout("p", map[string]string{"hooks-timeout": "10s"}), 10*time.Second))
+	assert.Check(t, is.Equal(hookTimeout("p", map[string]string{"hooks-timeout": "soon"}), hooksTimeout))
+}
+
+func TestRunHooksWithResult(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "requires shell-script plugins")
+	config.SetDir(t.TempDir())
+
+	dir := t.TempDir()
+	writeHookPlugin(t, dir, "nexter", `{"Type":0,"Template":"Run docker scout"}`)
+	writeHookPlugin(t, dir, "blocker", `{"Type":2,"Template":"too late to veto"}`)
+	cfg := &configfile.ConfigFile{
+		CLIPluginsExtraDirs: []string{dir},
+		Plugins: map[string]map[string]string{
+			"nexter":  {"hooks": "image"},
+			"blocker": {"hooks": "image"},
+		},
+	}
+
+	root := &cobra.Command{Use: "docker"}
+	subCmd := &cobra.Command{Use: "push"}
+	root.AddCommand(subCmd)
+
+	result := CommandResult{ExitCode: 1, Duration: 3 * time.Second, ImageDigest: "sha256:abc"}
+	nextSteps := invokeAndCollectHooks(context.Background(), cfg, root, subCmd, "image push", HookPluginData{
+		Stage:        HookStagePost,
+		Flags:        map[string]string{},
+		Result:       &result,
+		CommandError: "something went wrong",
+	})
+	assert.Check(t, is.DeepEqual(nextSteps, []string{"Run docker scout"}))
+
+	raw, err := os.ReadFile(filepath.Join(dir, "nexter.json"))
+	assert.NilError(t, err)
+	var hookData HookPluginData
+	assert.NilError(t, json.Unmarshal(raw, &hookData))
+	assert.Check(t, is.DeepEqual(hookData, HookPluginData{
+		RootCmd:      "image",
+		Flags:        map[string]string{},
+		CommandError: "something went wrong",
+		Stage:        HookStagePost,
+		Result:       &result,
+	}))
+}
@@ -0,0 +1,108 @@
+package manager
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/moby/sys/atomicwriter"
+	"github.com/sirupsen/logrus"
+)
+
+// metadataCacheDir is the directory inside the CLI's [config.Dir] in which
+// plugin metadata is cached.
+const metadataCacheDir = "cli-plugins-cache"
+
+// cachedMetadata is the on-disk representation of a plugin's cached
+// metadata. The cache entry is only valid as long as the size and
+// modification time of the plugin binary are unchanged.
+type cachedMetadata struct {
+	Path     string
+	Size     int64
+	ModTime  time.Time
+	Metadata json.RawMessage
+}
+
+// metadataCachePath returns the location of the cache entry for the plugin
+// at the given path.
+func metadataCachePath(pluginPath string) string {
+	return filepath.Join(config.Dir(), metadataCacheDir, metadataCacheName(pluginPath))
+}
+
+func metadataCacheName(pluginPath string) string {
+	sum := sha256.Sum256([]byte(pluginPath))
+	return hex.EncodeToString(sum[:]) + ".json"
+}
+
+// pruneMetadataCache removes the cache entries of plugins that are no longer
+// installed, or were moved, so that the cache does not grow indefinitely.
+// candidates are the paths of all plugin candidates, keyed by plugin name.
+// Failures to remove entries are not fatal, and only logged.
+func pruneMetadataCache(candidates map[string][]string) {
+	dir := filepath.Join(config.Dir(), metadataCacheDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	current := make(map[string]struct{})
+	for _, paths := range candidates {
+		for _, p := range paths {
+			current[metadataCacheName(p)] = struct{}{}
+		}
+	}
+	for _, e := range entries {
+		if _, ok := current[e.Name()]; ok || e.IsDir() {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, e.Name())); err != nil {
+			logrus.Debugf("failed to remove plugin metadata cache entry %s: %v", e.Name(), err)
+		}
+	}
+}
+
+// readCachedMetadata returns the cached metadata for the plugin at the
+// given path, if present and the plugin binary was not modified since
+// the metadata was cached.
+func readCachedMetadata(pluginPath string, fi os.FileInfo) ([]byte, bool) {
+	data, err := os.ReadFile(metadataCachePath(pluginPath))
+	if err != nil {
+		return nil, false
+	}
+	var cached cachedMetadata
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil, false
+	}
+	if cached.Path != pluginPath || cached.Size != fi.Size() || !cached.ModTime.Equal(fi.ModTime()) {
+		return nil, false
+	}
+	return cached.Metadata, true
+}
+
+// writeCachedMetadata stores the metadata for the plugin at the given path.
+// Failures to write the cache are not fatal, and only logged.
+func writeCachedMetadata(pluginPath string, fi os.FileInfo, meta []byte) {
+	if !json.Valid(meta) {
+		return
+	}
+	data, err := json.Marshal(cachedMetadata{
+		Path:     pluginPath,
+		Size:     fi.Size(),
+		ModTime:  fi.ModTime(),
+		Metadata: meta,
+	})
+	if err != nil {
+		return
+	}
+	cachePath := metadataCachePath(pluginPath)
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0o700); err != nil {
+		logrus.Debugf("failed to create plugin metadata cache: %v", err)
+		return
+	}
+	if err := atomicwriter.WriteFile(cachePath, data, 0o600); err != nil {
+		logrus.Debugf("failed to write plugin metadata cache for %s: %v", pluginPath, err)
+	}
+}
@@ -0,0 +1,62 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+
+	"github.com/docker/cli/cli-plugins/metadata"
+	"github.com/moby/moby/api/types/filters"
+)
+
+// PrunePluginData is the type representing the information that plugins
+// declaring support for pruning get passed when being invoked as part of
+// "docker system prune".
+type PrunePluginData struct {
+	// Confirmed indicates whether pruning was confirmed by the user. If
+	// not set, the plugin must not remove any content, and only return a
+	// short description of what content will be pruned (for example,
+	// "all unused caches") in [PruneResult.Details].
+	Confirmed bool
+	// All indicates that all unused content must be removed, not only
+	// dangling content.
+	All     bool
+	Filters filters.Args
+}
+
+// PruneResult represents a plugin prune response. Plugins declaring
+// support for pruning need to print a json representation of this type
+// when their prune subcommand is invoked.
+type PruneResult struct {
+	// SpaceReclaimed is the amount of data removed (in bytes), if any.
+	SpaceReclaimed uint64 `json:",omitempty"`
+	// Deleted contains the IDs (or references) of the objects removed, if any.
+	Deleted []string `json:",omitempty"`
+	// Details is arbitrary information about the content pruned to be
+	// presented to the user, or the confirmation message when running
+	// in "dry-run" mode.
+	Details string `json:",omitempty"`
+}
+
+// RunPrune executes the plugin's prune command and returns its result.
+func (p *Plugin) RunPrune(ctx context.Context, pruneData PrunePluginData) (PruneResult, error) {
+	pDataBytes, err := json.Marshal(pruneData)
+	if err != nil {
+		return PruneResult{}, wrapAsPluginError(err, "failed to marshall prune data")
+	}
+
+	pCmd := exec.CommandContext(ctx, p.Path, p.Name, metadata.PruneSubcommandName, string(pDataBytes)) // #nosec G204 -- ignore "Subprocess launched with a potential tainted input or cmd arguments"
+	pCmd.Env = os.Environ()
+	pCmd.Env = append(pCmd.Env, metadata.ReexecEnvvar+"="+os.Args[0])
+	pruneCmdOutput, err := pCmd.Output()
+	if err != nil {
+		return PruneResult{}, wrapAsPluginError(err, "failed to execute plugin prune subcommand")
+	}
+
+	var result PruneResult
+	if err := json.Unmarshal(pruneCmdOutput, &result); err != nil {
+		return PruneResult{}, wrapAsPluginError(err, "invalid prune response")
+	}
+	return result, nil
+}
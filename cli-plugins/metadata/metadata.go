@@ -14,6 +14,11 @@
 	// for hooks in their metadata.
 	HookSubcommandName = "docker-cli-plugin-hooks"
 
+	// PruneSubcommandName is the name of the plugin subcommand
+	// which must be implemented by plugins declaring support
+	// for pruning in their metadata.
+	PruneSubcommandName = "docker-cli-plugin-prune"
+
 	// ReexecEnvvar is the name of an ennvar which is set to the command
 	// used to originally invoke the docker CLI when executing a
 	// plugin. Assuming $PATH and $CWD remain unchanged this should allow
@@ -33,4 +38,8 @@ type Metadata struct {
 	ShortDescription string `json:",omitempty"`
 	// URL is a pointer to the plugin's homepage.
 	URL string `json:",omitempty"`
+	// Prune indicates whether the plugin manages content that can be
+	// pruned as part of "docker system prune". Plugins setting this
+	// must implement the [PruneSubcommandName] subcommand.
+	Prune bool `json:",omitempty"`
 }
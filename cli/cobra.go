@@ -29,12 +29,14 @@ func setupCommonRootCommand(rootCmd *cobra.Command) (*cliflags.ClientOptions, *c
 	cobra.AddTemplateFunc("hasManagementSubCommands", hasManagementSubCommands)
 	cobra.AddTemplateFunc("hasSwarmSubCommands", hasSwarmSubCommands)
 	cobra.AddTemplateFunc("hasInvalidPlugins", hasInvalidPlugins)
+	cobra.AddTemplateFunc("hasUserAliases", hasUserAliases)
 	cobra.AddTemplateFunc("topCommands", topCommands)
 	cobra.AddTemplateFunc("commandAliases", commandAliases)
 	cobra.AddTemplateFunc("operationSubCommands", operationSubCommands)
 	cobra.AddTemplateFunc("managementSubCommands", managementSubCommands)
 	cobra.AddTemplateFunc("orchestratorSubCommands", orchestratorSubCommands)
 	cobra.AddTemplateFunc("invalidPlugins", invalidPlugins)
+	cobra.AddTemplateFunc("userAliases", userAliases)
 	cobra.AddTemplateFunc("wrappedFlagUsages", wrappedFlagUsages)
 	cobra.AddTemplateFunc("vendorAndVersion", vendorAndVersion)
 	cobra.AddTemplateFunc("invalidPluginReason", invalidPluginReason)
@@ -215,6 +217,10 @@ func isPlugin(cmd *cobra.Command) bool {
 	return cmd.Annotations[metadata.CommandAnnotationPlugin] == "true"
 }
 
+func isUserAlias(cmd *cobra.Command) bool {
+	return cmd.Annotations["user-alias"] != ""
+}
+
 func hasAliases(cmd *cobra.Command) bool {
 	return len(cmd.Aliases) > 0 || cmd.Annotations["aliases"] != ""
 }
@@ -235,6 +241,10 @@ func hasInvalidPlugins(cmd *cobra.Command) bool {
 	return len(invalidPlugins(cmd)) > 0
 }
 
+func hasUserAliases(cmd *cobra.Command) bool {
+	return len(userAliases(cmd)) > 0
+}
+
 func hasTopCommands(cmd *cobra.Command) bool {
 	return len(topCommands(cmd)) > 0
 }
@@ -281,7 +291,7 @@ func topCommands(cmd *cobra.Command) []*cobra.Command {
 func operationSubCommands(cmd *cobra.Command) []*cobra.Command {
 	cmds := []*cobra.Command{}
 	for _, sub := range cmd.Commands() {
-		if isPlugin(sub) {
+		if isPlugin(sub) || isUserAlias(sub) {
 			continue
 		}
 		if _, ok := sub.Annotations["category-top"]; ok {
@@ -376,6 +386,17 @@ func invalidPlugins(cmd *cobra.Command) []*cobra.Command {
 	return cmds
 }
 
+// userAliases returns the command stubs for user-defined aliases.
+func userAliases(cmd *cobra.Command) []*cobra.Command {
+	cmds := []*cobra.Command{}
+	for _, sub := range cmd.Commands() {
+		if isUserAlias(sub) && sub.IsAvailableCommand() {
+			cmds = append(cmds, sub)
+		}
+	}
+	return cmds
+}
+
 func invalidPluginReason(cmd *cobra.Command) string {
 	return cmd.Annotations[metadata.CommandAnnotationPluginInvalid]
 }
@@ -450,6 +471,15 @@ func invalidPluginReason(cmd *cobra.Command) string {
 {{- end}}
 {{- end}}
 
+{{- if hasUserAliases . }}
+
+User Aliases:
+
+{{- range userAliases . }}
+  {{rpad .Name .NamePadding }} {{.Short}}
+{{- end}}
+
+{{- end}}
 {{- if hasInvalidPlugins . }}
 
 Invalid Plugins:
@@ -29,6 +29,7 @@ type pruneOptions struct {
 	all           bool
 	filter        opts.FilterOpt
 	reservedSpace opts.MemBytes
+	onDeleted     func(id string)
 }
 
 // newPruneCommand returns a new cobra prune command for images
@@ -102,6 +103,9 @@ func runPrune(ctx context.Context, dockerCli command.Cli, options pruneOptions)
 		for _, id := range report.CachesDeleted {
 			sb.WriteString(id)
 			sb.WriteByte('\n')
+			if options.onDeleted != nil {
+				options.onDeleted(id)
+			}
 		}
 		output = sb.String()
 	}
@@ -135,8 +139,9 @@ func pruneFn(ctx context.Context, dockerCLI command.Cli, options pruner.PruneOpt
 		return 0, confirmMsg, cancelledErr{errors.New("builder prune has been cancelled")}
 	}
 	return runPrune(ctx, dockerCLI, pruneOptions{
-		force:  true,
-		all:    options.All,
-		filter: options.Filter,
+		force:     true,
+		all:       options.All,
+		filter:    options.Filter,
+		onDeleted: options.OnDeleted,
 	})
 }
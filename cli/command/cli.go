@@ -72,6 +72,7 @@ type DockerCli struct {
 	contentTrust       bool
 	contextStore       store.Store
 	currentContext     string
+	contextSource      ContextSource
 	init               sync.Once
 	initErr            error
 	dockerEndpoint     docker.Endpoint
@@ -264,7 +265,7 @@ func (cli *DockerCli) Initialize(opts *cliflags.ClientOptions, ops ...CLIOption)
 
 	cli.options = opts
 	cli.configFile = config.LoadDefaultConfigFile(cli.err)
-	cli.currentContext = resolveContextName(cli.options, cli.configFile)
+	cli.currentContext, cli.contextSource = resolveContext(cli.options, cli.configFile)
 	cli.contextStore = &ContextStoreWithDefault{
 		Store: store.New(config.ContextStoreDir(), *cli.contextStoreConfig),
 		Resolver: func() (*DefaultContext, error) {
@@ -423,9 +424,11 @@ func (cli *DockerCli) ContextStore() store.Store {
 //
 //  1. The "--context" command-line option.
 //  2. The "DOCKER_CONTEXT" environment variable ([EnvOverrideContext]).
-//  3. The current context as configured through the in "currentContext"
+//  3. A ".dockercontext" file ([ContextFileName]) in the current working
+//     directory, or the closest of its parent directories.
+//  4. The current context as configured through the in "currentContext"
 //     field in the CLI configuration file ("~/.docker/config.json").
-//  4. If no context is configured, use the "default" context.
+//  5. If no context is configured, use the "default" context.
 //
 // # Fallbacks for backward-compatibility
 //
@@ -448,6 +451,13 @@ func (cli *DockerCli) CurrentContext() string {
 	return cli.currentContext
 }
 
+// CurrentContextSource returns how the current context was selected.
+//
+// Refer to [DockerCli.CurrentContext] above for details.
+func (cli *DockerCli) CurrentContextSource() ContextSource {
+	return cli.contextSource
+}
+
 // CurrentContext returns the current context name, based on flags,
 // environment variables and the cli configuration file. It does not
 // validate if the given context exists or if it's valid; errors may
@@ -455,23 +465,35 @@ func (cli *DockerCli) CurrentContext() string {
 //
 // Refer to [DockerCli.CurrentContext] above for further details.
 func resolveContextName(opts *cliflags.ClientOptions, cfg *configfile.ConfigFile) string {
+	name, _ := resolveContext(opts, cfg)
+	return name
+}
+
+// resolveContext returns the current context name, and the source that
+// selected it. Refer to [DockerCli.CurrentContext] for details.
+func resolveContext(opts *cliflags.ClientOptions, cfg *configfile.ConfigFile) (string, ContextSource) {
 	if opts != nil && opts.Context != "" {
-		return opts.Context
+		return opts.Context, ContextSource{Kind: ContextSourceFlag}
 	}
 	if opts != nil && len(opts.Hosts) > 0 {
-		return DefaultContextName
+		return DefaultContextName, ContextSource{Kind: ContextSourceHostFlag}
 	}
 	if os.Getenv(client.EnvOverrideHost) != "" {
-		return DefaultContextName
+		return DefaultContextName, ContextSource{Kind: ContextSourceHostEnv}
 	}
 	if ctxName := os.Getenv(EnvOverrideContext); ctxName != "" {
-		return ctxName
+		return ctxName, ContextSource{Kind: ContextSourceEnv}
+	}
+	if wd, err := os.Getwd(); err == nil {
+		if ctxName, path := findContextFile(wd); ctxName != "" {
+			return ctxName, ContextSource{Kind: ContextSourceFile, Path: path}
+		}
 	}
 	if cfg != nil && cfg.CurrentContext != "" {
 		// We don't validate if this context exists: errors may occur when trying to use it.
-		return cfg.CurrentContext
+		return cfg.CurrentContext, ContextSource{Kind: ContextSourceConfig, Path: cfg.Filename}
 	}
-	return DefaultContextName
+	return DefaultContextName, ContextSource{Kind: ContextSourceDefault}
 }
 
 // DockerEndpoint returns the current docker endpoint
@@ -1,6 +1,7 @@
 package completion
 
 import (
+	"context"
 	"os"
 	"strings"
 
@@ -43,9 +44,7 @@ func ImageNames(dockerCLI APIClientProvider, limit int) cobra.CompletionFunc {
 // Set DOCKER_COMPLETION_SHOW_CONTAINER_IDS=yes to also complete IDs.
 func ContainerNames(dockerCLI APIClientProvider, all bool, filters ...func(container.Summary) bool) cobra.CompletionFunc {
 	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		list, err := dockerCLI.Client().ContainerList(cmd.Context(), client.ContainerListOptions{
-			All: all,
-		})
+		list, err := Containers(cmd.Context(), dockerCLI, all, filters...)
 		if err != nil {
 			return nil, cobra.ShellCompDirectiveError
 		}
@@ -54,16 +53,6 @@ func ContainerNames(dockerCLI APIClientProvider, all bool, filters ...func(conta
 
 		var names []string
 		for _, ctr := range list {
-			skip := false
-			for _, fn := range filters {
-				if fn != nil && !fn(ctr) {
-					skip = true
-					break
-				}
-			}
-			if skip {
-				continue
-			}
 			if showContainerIDs {
 				names = append(names, ctr.ID)
 			}
@@ -73,6 +62,32 @@ func ContainerNames(dockerCLI APIClientProvider, all bool, filters ...func(conta
 	}
 }
 
+// Containers returns the containers that are offered by [ContainerNames]
+// for the given options.
+func Containers(ctx context.Context, dockerCLI APIClientProvider, all bool, filters ...func(container.Summary) bool) ([]container.Summary, error) {
+	list, err := dockerCLI.Client().ContainerList(ctx, client.ContainerListOptions{
+		All: all,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var containers []container.Summary
+	for _, ctr := range list {
+		skip := false
+		for _, fn := range filters {
+			if fn != nil && !fn(ctr) {
+				skip = true
+				break
+			}
+		}
+		if !skip {
+			containers = append(containers, ctr)
+		}
+	}
+	return containers, nil
+}
+
 // VolumeNames offers completion for volumes
 func VolumeNames(dockerCLI APIClientProvider) cobra.CompletionFunc {
 	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -44,20 +44,31 @@ func inspectContainerAndCheckState(ctx context.Context, apiClient client.APIClie
 func newAttachCommand(dockerCLI command.Cli) *cobra.Command {
 	var opts AttachOptions
 
+	notPaused := func(ctr container.Summary) bool {
+		return ctr.State != container.StatePaused
+	}
+
 	cmd := &cobra.Command{
 		Use:   "attach [OPTIONS] CONTAINER",
 		Short: "Attach local standard input, output, and error streams to a running container",
-		Args:  cli.ExactArgs(1),
+		Args:  argsOrPicker(dockerCLI, cli.ExactArgs(1)),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				var err error
+				if args, err = pickContainers(cmd.Context(), dockerCLI, pickOptions{
+					action:  "attach to",
+					filters: []func(container.Summary) bool{notPaused},
+				}); err != nil {
+					return err
+				}
+			}
 			containerID := args[0]
 			return RunAttach(cmd.Context(), dockerCLI, containerID, &opts)
 		},
 		Annotations: map[string]string{
 			"aliases": "docker container attach, docker attach",
 		},
-		ValidArgsFunction: completion.ContainerNames(dockerCLI, false, func(ctr container.Summary) bool {
-			return ctr.State != container.StatePaused
-		}),
+		ValidArgsFunction:     completion.ContainerNames(dockerCLI, false, notPaused),
 		DisableFlagsInUseLine: true,
 	}
 
@@ -2,10 +2,13 @@
 
 import (
 	"context"
+	"errors"
 	"io"
 
 	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/events"
 	"github.com/moby/moby/api/types/filters"
+	"github.com/moby/moby/api/types/image"
 	"github.com/moby/moby/api/types/network"
 	"github.com/moby/moby/api/types/system"
 	"github.com/moby/moby/client"
@@ -17,6 +20,7 @@ type fakeClient struct {
 	inspectFunc         func(string) (container.InspectResponse, error)
 	execInspectFunc     func(execID string) (container.ExecInspect, error)
 	execCreateFunc      func(containerID string, options container.ExecOptions) (container.ExecCreateResponse, error)
+	execAttachFunc      func(execID string, options container.ExecAttachOptions) (client.HijackedResponse, error)
 	createContainerFunc func(config *container.Config,
 		hostConfig *container.HostConfig,
 		networkingConfig *network.NetworkingConfig,
@@ -27,6 +31,7 @@ type fakeClient struct {
 	infoFunc                func() (system.Info, error)
 	containerStatPathFunc   func(containerID, path string) (container.PathStat, error)
 	containerCopyFromFunc   func(containerID, srcPath string) (io.ReadCloser, container.PathStat, error)
+	containerCopyToFunc     func(containerID, dstPath string, content io.Reader, options client.CopyToContainerOptions) error
 	logFunc                 func(string, client.ContainerLogsOptions) (io.ReadCloser, error)
 	waitFunc                func(string) (<-chan container.WaitResponse, <-chan error)
 	containerListFunc       func(client.ContainerListOptions) ([]container.Summary, error)
@@ -42,6 +47,9 @@ type fakeClient struct {
 	containerRenameFunc     func(ctx context.Context, oldName, newName string) error
 	containerCommitFunc     func(ctx context.Context, container string, options client.ContainerCommitOptions) (container.CommitResponse, error)
 	containerPauseFunc      func(ctx context.Context, container string) error
+	eventsFunc              func(options client.EventsListOptions) (<-chan events.Message, <-chan error)
+	containerStatsFunc      func(ctx context.Context, containerID string, stream bool) (client.StatsResponseReader, error)
+	imageInspectFunc        func(img string) (image.InspectResponse, error)
 	Version                 string
 }
 
@@ -52,6 +60,20 @@ func (f *fakeClient) ContainerList(_ context.Context, options client.ContainerLi
 	return []container.Summary{}, nil
 }
 
+func (f *fakeClient) ContainerStats(ctx context.Context, containerID string, stream bool) (client.StatsResponseReader, error) {
+	if f.containerStatsFunc != nil {
+		return f.containerStatsFunc(ctx, containerID, stream)
+	}
+	return client.StatsResponseReader{}, nil
+}
+
+func (f *fakeClient) Events(_ context.Context, options client.EventsListOptions) (<-chan events.Message, <-chan error) {
+	if f.eventsFunc != nil {
+		return f.eventsFunc(options)
+	}
+	return nil, nil
+}
+
 func (f *fakeClient) ContainerInspect(_ context.Context, containerID string) (container.InspectResponse, error) {
 	if f.inspectFunc != nil {
 		return f.inspectFunc(containerID)
@@ -73,6 +95,13 @@ func (f *fakeClient) ContainerExecInspect(_ context.Context, execID string) (con
 	return container.ExecInspect{}, nil
 }
 
+func (f *fakeClient) ContainerExecAttach(_ context.Context, execID string, options container.ExecAttachOptions) (client.HijackedResponse, error) {
+	if f.execAttachFunc != nil {
+		return f.execAttachFunc(execID, options)
+	}
+	return client.HijackedResponse{}, errors.New("exec attach is not implemented")
+}
+
 func (*fakeClient) ContainerExecStart(context.Context, string, container.ExecStartOptions) error {
 	return nil
 }
@@ -126,6 +155,13 @@ func (f *fakeClient) CopyFromContainer(_ context.Context, containerID, srcPath s
 	return nil, container.PathStat{}, nil
 }
 
+func (f *fakeClient) CopyToContainer(_ context.Context, containerID, dstPath string, content io.Reader, options client.CopyToContainerOptions) error {
+	if f.containerCopyToFunc != nil {
+		return f.containerCopyToFunc(containerID, dstPath, content, options)
+	}
+	return nil
+}
+
 func (f *fakeClient) ContainerLogs(_ context.Context, containerID string, options client.ContainerLogsOptions) (io.ReadCloser, error) {
 	if f.logFunc != nil {
 		return f.logFunc(containerID, options)
@@ -223,6 +259,13 @@ func (f *fakeClient) ContainerCommit(ctx context.Context, containerID string, op
 	return container.CommitResponse{}, nil
 }
 
+func (f *fakeClient) ImageInspect(_ context.Context, img string, _ ...client.ImageInspectOption) (image.InspectResponse, error) {
+	if f.imageInspectFunc != nil {
+		return f.imageInspectFunc(img)
+	}
+	return image.InspectResponse{}, nil
+}
+
 func (f *fakeClient) ContainerPause(ctx context.Context, containerID string) error {
 	if f.containerPauseFunc != nil {
 		return f.containerPauseFunc(ctx, containerID)
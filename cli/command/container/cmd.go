@@ -70,6 +70,7 @@ func newContainerCommand(dockerCLI command.Cli) *cobra.Command {
 		newListCommand(dockerCLI),
 		newInspectCommand(dockerCLI),
 		newPruneCommand(dockerCLI),
+		newProfileCommand(dockerCLI),
 	)
 	return cmd
 }
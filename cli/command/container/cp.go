@@ -29,6 +29,12 @@ type copyOptions struct {
 	followLink  bool
 	copyUIDGID  bool
 	quiet       bool
+	sync        bool
+	delete      bool
+	dryRun      bool
+	checksum    bool
+	watch       bool
+	excludes    []string
 }
 
 type copyDirection int
@@ -43,6 +49,13 @@ type cpConfig struct {
 	followLink bool
 	copyUIDGID bool
 	quiet      bool
+	sync       bool
+	delete     bool
+	dryRun     bool
+	checksum   bool
+	watch      bool
+	excludes   []string
+	debounce   time.Duration
 	sourcePath string
 	destPath   string
 	container  string
@@ -58,6 +71,7 @@ type copyProgressPrinter struct {
 const (
 	copyToContainerHeader       = "Copying to container - "
 	copyFromContainerHeader     = "Copying from container - "
+	copyAcrossContainersHeader  = "Copying between containers - "
 	copyProgressUpdateThreshold = 75 * time.Millisecond
 )
 
@@ -67,7 +81,9 @@ func (pt *copyProgressPrinter) Read(p []byte) (int, error) {
 	return n, err
 }
 
-func copyProgress(ctx context.Context, dst io.Writer, header string, total *int64) (func(), <-chan struct{}) {
+// copyProgress prints the number of bytes copied, and the number of files
+// copied and skipped if stats is set, until the context is cancelled.
+func copyProgress(ctx context.Context, dst io.Writer, header string, total *int64, stats *syncStats) (func(), <-chan struct{}) {
 	done := make(chan struct{})
 	if !streams.NewOut(dst).IsTerminal() {
 		close(done)
@@ -91,8 +107,15 @@ func copyProgress(ctx context.Context, dst io.Writer, header string, total *int6
 		fmt.Fprint(dst, aec.EraseLine(aec.EraseModes.All))
 		fmt.Fprint(dst, header)
 
-		var last int64
-		fmt.Fprint(dst, progressHumanSize(last))
+		status := func() string {
+			s := progressHumanSize(atomic.LoadInt64(total))
+			if stats != nil {
+				s += stats.progress()
+			}
+			return s
+		}
+		last := status()
+		fmt.Fprint(dst, last)
 
 		buf := bytes.NewBuffer(nil)
 		ticker := time.NewTicker(copyProgressUpdateThreshold)
@@ -101,8 +124,8 @@ func copyProgress(ctx context.Context, dst io.Writer, header string, total *int6
 			case <-ctx.Done():
 				return
 			case <-ticker.C:
-				n := atomic.LoadInt64(total)
-				if n == last {
+				s := status()
+				if s == last {
 					// Don't write to the terminal, if we don't need to.
 					continue
 				}
@@ -110,11 +133,11 @@ func copyProgress(ctx context.Context, dst io.Writer, header string, total *int6
 				// Write to the buffer first to avoid flickering and context switching
 				fmt.Fprint(buf, aec.Column(uint(len(header)+1)))
 				fmt.Fprint(buf, aec.EraseLine(aec.EraseModes.Tail))
-				fmt.Fprint(buf, progressHumanSize(n))
+				fmt.Fprint(buf, s)
 
 				buf.WriteTo(dst)
 				buf.Reset()
-				last += n
+				last = s
 			}
 		}
 	}()
@@ -127,14 +150,17 @@ func newCopyCommand(dockerCLI command.Cli) *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use: `cp [OPTIONS] CONTAINER:SRC_PATH DEST_PATH|-
-	docker cp [OPTIONS] SRC_PATH|- CONTAINER:DEST_PATH`,
-		Short: "Copy files/folders between a container and the local filesystem",
-		Long: `Copy files/folders between a container and the local filesystem
+	docker cp [OPTIONS] SRC_PATH|- CONTAINER:DEST_PATH
+	docker cp [OPTIONS] CONTAINER:SRC_PATH CONTAINER:DEST_PATH`,
+		Short: "Copy files/folders between containers and the local filesystem",
+		Long: `Copy files/folders between containers and the local filesystem
 
 Use '-' as the source to read a tar archive from stdin
 and extract it to a directory destination in a container.
 Use '-' as the destination to stream a tar archive of a
-container source to stdout.`,
+container source to stdout.
+Specify a container for both the source and destination
+to copy between containers without using the local filesystem.`,
 		Args: cli.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if args[0] == "" {
@@ -161,6 +187,12 @@ func newCopyCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.BoolVarP(&opts.followLink, "follow-link", "L", false, "Always follow symbol link in SRC_PATH")
 	flags.BoolVarP(&opts.copyUIDGID, "archive", "a", false, "Archive mode (copy all uid/gid information)")
 	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Suppress progress output during copy. Progress output is automatically suppressed if no terminal is attached")
+	flags.BoolVar(&opts.sync, "sync", false, "Only copy files that are new or changed between the source and destination directories")
+	flags.BoolVar(&opts.delete, "delete", false, "Delete files in the destination that are not in the source (requires --sync or --watch)")
+	flags.BoolVar(&opts.dryRun, "dry-run", false, "List the changes to make without making them (requires --sync)")
+	flags.BoolVar(&opts.checksum, "checksum", false, "Compare the content of files instead of their modification time (requires --sync or --watch)")
+	flags.BoolVar(&opts.watch, "watch", false, "Watch SRC_PATH for changes, and copy them to the container until interrupted")
+	flags.StringSliceVar(&opts.excludes, "exclude", []string{}, "Exclude files matching a pattern (requires --sync or --watch)")
 	return cmd
 }
 
@@ -172,10 +204,39 @@ func runCopy(ctx context.Context, dockerCli command.Cli, opts copyOptions) error
 	srcContainer, srcPath := splitCpArg(opts.source)
 	destContainer, destPath := splitCpArg(opts.destination)
 
+	if opts.sync || opts.watch {
+		switch {
+		case opts.watch && opts.dryRun:
+			return errors.New("the --dry-run option cannot be used with --watch")
+		case opts.watch && (srcPath == "-" || destPath == "-"):
+			return errors.New(`the --watch option cannot be used with "-" as source or destination`)
+		case srcPath == "-" || destPath == "-":
+			return errors.New(`the --sync option cannot be used with "-" as source or destination`)
+		}
+	} else {
+		switch {
+		case opts.delete:
+			return errors.New("the --delete option requires --sync or --watch")
+		case opts.dryRun:
+			return errors.New("the --dry-run option requires --sync")
+		case opts.checksum:
+			return errors.New("the --checksum option requires --sync or --watch")
+		case len(opts.excludes) > 0:
+			return errors.New("the --exclude option requires --sync or --watch")
+		}
+	}
+
 	copyConfig := cpConfig{
 		followLink: opts.followLink,
 		copyUIDGID: opts.copyUIDGID,
 		quiet:      opts.quiet,
+		sync:       opts.sync,
+		delete:     opts.delete,
+		dryRun:     opts.dryRun,
+		checksum:   opts.checksum,
+		watch:      opts.watch,
+		excludes:   opts.excludes,
+		debounce:   watchDebounce,
 		sourcePath: srcPath,
 		destPath:   destPath,
 	}
@@ -192,11 +253,33 @@ func runCopy(ctx context.Context, dockerCli command.Cli, opts copyOptions) error
 
 	switch direction {
 	case fromContainer:
+		switch {
+		case copyConfig.watch:
+			return errors.New("the --watch option requires a container as destination")
+		case len(copyConfig.excludes) > 0:
+			return errors.New("the --exclude option is only supported when copying to a container")
+		}
+		if copyConfig.sync {
+			return syncFromContainer(ctx, dockerCli, copyConfig)
+		}
 		return copyFromContainer(ctx, dockerCli, copyConfig)
 	case toContainer:
+		if copyConfig.watch {
+			return watchToContainer(ctx, dockerCli, copyConfig)
+		}
+		if copyConfig.sync {
+			return syncToContainer(ctx, dockerCli, copyConfig)
+		}
 		return copyToContainer(ctx, dockerCli, copyConfig)
 	case acrossContainers:
-		return errors.New("copying between containers is not supported")
+		if copyConfig.watch {
+			return errors.New("the --watch option requires a local source")
+		}
+		if copyConfig.sync {
+			return errors.New("the --sync option is not supported when copying between containers")
+		}
+		copyConfig.container = srcContainer
+		return copyAcrossContainers(ctx, dockerCli, copyConfig, destContainer)
 	default:
 		return errors.New("must specify at least one container source")
 	}
@@ -227,24 +310,7 @@ func copyFromContainer(ctx context.Context, dockerCLI command.Cli, copyConfig cp
 	}
 
 	apiClient := dockerCLI.Client()
-	// if client requests to follow symbol link, then must decide target file to be copied
-	var rebaseName string
-	if copyConfig.followLink {
-		srcStat, err := apiClient.ContainerStatPath(ctx, copyConfig.container, srcPath)
-
-		// If the destination is a symbolic link, we should follow it.
-		if err == nil && srcStat.Mode&os.ModeSymlink != 0 {
-			linkTarget := srcStat.LinkTarget
-			if !isAbs(linkTarget) {
-				// Join with the parent directory.
-				srcParent, _ := archive.SplitPathDirEntry(srcPath)
-				linkTarget = filepath.Join(srcParent, linkTarget)
-			}
-
-			linkTarget, rebaseName = archive.GetRebaseName(srcPath, linkTarget)
-			srcPath = linkTarget
-		}
-	}
+	srcPath, rebaseName := containerCopySource(ctx, apiClient, copyConfig.container, srcPath, copyConfig.followLink)
 
 	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
 	defer cancel()
@@ -285,7 +351,7 @@ func copyFromContainer(ctx context.Context, dockerCLI command.Cli, copyConfig cp
 		return archive.CopyTo(preArchive, srcInfo, dstPath)
 	}
 
-	restore, done := copyProgress(ctx, dockerCLI.Err(), copyFromContainerHeader, &copiedSize)
+	restore, done := copyProgress(ctx, dockerCLI.Err(), copyFromContainerHeader, &copiedSize, nil)
 	res := archive.CopyTo(preArchive, srcInfo, dstPath)
 	cancel()
 	<-done
@@ -313,36 +379,9 @@ func copyToContainer(ctx context.Context, dockerCLI command.Cli, copyConfig cpCo
 	}
 
 	apiClient := dockerCLI.Client()
-	// Prepare destination copy info by stat-ing the container path.
-	dstInfo := archive.CopyInfo{Path: dstPath}
-	if dstStat, err := apiClient.ContainerStatPath(ctx, copyConfig.container, dstPath); err == nil {
-		// If the destination is a symbolic link, we should evaluate it.
-		if dstStat.Mode&os.ModeSymlink != 0 {
-			linkTarget := dstStat.LinkTarget
-			if !isAbs(linkTarget) {
-				// Join with the parent directory.
-				dstParent, _ := archive.SplitPathDirEntry(dstPath)
-				linkTarget = filepath.Join(dstParent, linkTarget)
-			}
-
-			dstInfo.Path = linkTarget
-			dstStat, err = apiClient.ContainerStatPath(ctx, copyConfig.container, linkTarget)
-		}
-		// Validate the destination path
-		if err == nil {
-			if err := command.ValidateOutputPathFileMode(dstStat.Mode); err != nil {
-				return fmt.Errorf(`destination "%s:%s" must be a directory or a regular file: %w`, copyConfig.container, dstPath, err)
-			}
-			dstInfo.Exists, dstInfo.IsDir = true, dstStat.Mode.IsDir()
-		}
-
-		// Ignore any error and assume that the parent directory of the destination
-		// path exists, in which case the copy may still succeed. If there is any
-		// type of conflict (e.g., non-directory overwriting an existing directory
-		// or vice versa) the extraction will fail. If the destination simply did
-		// not exist, but the parent directory does, the extraction will still
-		// succeed.
-		_ = err // Intentionally ignore stat errors (see above)
+	dstInfo, err := containerCopyDestination(ctx, apiClient, copyConfig.container, dstPath)
+	if err != nil {
+		return err
 	}
 
 	var (
@@ -407,7 +446,7 @@ func copyToContainer(ctx context.Context, dockerCLI command.Cli, copyConfig cpCo
 	}
 
 	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
-	restore, done := copyProgress(ctx, dockerCLI.Err(), copyToContainerHeader, &copiedSize)
+	restore, done := copyProgress(ctx, dockerCLI.Err(), copyToContainerHeader, &copiedSize, nil)
 	res := apiClient.CopyToContainer(ctx, copyConfig.container, resolvedDstPath, content, options)
 	cancel()
 	<-done
@@ -417,6 +456,133 @@ func copyToContainer(ctx context.Context, dockerCLI command.Cli, copyConfig cpCo
 	return res
 }
 
+// copyAcrossContainers copies from the container in copyConfig to dstContainer.
+// The archive of the source is streamed directly to the destination container,
+// altered for the desired copy behavior in the same way as when copying from
+// the local filesystem (see copyToContainer).
+func copyAcrossContainers(ctx context.Context, dockerCLI command.Cli, copyConfig cpConfig, dstContainer string) error {
+	apiClient := dockerCLI.Client()
+	srcPath, rebaseName := containerCopySource(ctx, apiClient, copyConfig.container, copyConfig.sourcePath, copyConfig.followLink)
+
+	dstInfo, err := containerCopyDestination(ctx, apiClient, dstContainer, copyConfig.destPath)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+	defer cancel()
+
+	content, stat, err := apiClient.CopyFromContainer(ctx, copyConfig.container, srcPath)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	srcInfo := archive.CopyInfo{
+		Path:       srcPath,
+		Exists:     true,
+		IsDir:      stat.Mode.IsDir(),
+		RebaseName: rebaseName,
+	}
+
+	var copiedSize int64
+	if !copyConfig.quiet {
+		content = &copyProgressPrinter{
+			ReadCloser: content,
+			total:      &copiedSize,
+		}
+	}
+
+	preArchive := content
+	if len(srcInfo.RebaseName) != 0 {
+		_, srcBase := archive.SplitPathDirEntry(srcInfo.Path)
+		preArchive = archive.RebaseArchiveEntries(content, srcBase, srcInfo.RebaseName)
+	}
+
+	dstDir, preparedArchive, err := archive.PrepareArchiveCopy(preArchive, srcInfo, dstInfo)
+	if err != nil {
+		return err
+	}
+	defer preparedArchive.Close()
+
+	options := client.CopyToContainerOptions{
+		CopyUIDGID: copyConfig.copyUIDGID,
+	}
+
+	if copyConfig.quiet {
+		return apiClient.CopyToContainer(ctx, dstContainer, dstDir, preparedArchive, options)
+	}
+
+	restore, done := copyProgress(ctx, dockerCLI.Err(), copyAcrossContainersHeader, &copiedSize, nil)
+	res := apiClient.CopyToContainer(ctx, dstContainer, dstDir, preparedArchive, options)
+	cancel()
+	<-done
+	restore()
+	_, _ = fmt.Fprintln(dockerCLI.Err(), "Successfully copied", progressHumanSize(copiedSize), "from", copyConfig.container+":"+srcPath, "to", dstContainer+":"+dstInfo.Path)
+
+	return res
+}
+
+// containerCopySource returns the path to copy from a container. If followLink
+// is set, and the path is a symbolic link, the path of the link target is
+// returned, and the name to rebase the archive entries of the target to.
+func containerCopySource(ctx context.Context, apiClient client.ContainerAPIClient, ctr, srcPath string, followLink bool) (_ string, rebaseName string) {
+	// if client requests to follow symbol link, then must decide target file to be copied
+	if followLink {
+		srcStat, err := apiClient.ContainerStatPath(ctx, ctr, srcPath)
+
+		// If the destination is a symbolic link, we should follow it.
+		if err == nil && srcStat.Mode&os.ModeSymlink != 0 {
+			linkTarget := srcStat.LinkTarget
+			if !isAbs(linkTarget) {
+				// Join with the parent directory.
+				srcParent, _ := archive.SplitPathDirEntry(srcPath)
+				linkTarget = filepath.Join(srcParent, linkTarget)
+			}
+
+			linkTarget, rebaseName = archive.GetRebaseName(srcPath, linkTarget)
+			srcPath = linkTarget
+		}
+	}
+	return srcPath, rebaseName
+}
+
+// containerCopyDestination prepares the destination copy info by stat-ing
+// the container path.
+func containerCopyDestination(ctx context.Context, apiClient client.ContainerAPIClient, ctr, dstPath string) (archive.CopyInfo, error) {
+	dstInfo := archive.CopyInfo{Path: dstPath}
+	if dstStat, err := apiClient.ContainerStatPath(ctx, ctr, dstPath); err == nil {
+		// If the destination is a symbolic link, we should evaluate it.
+		if dstStat.Mode&os.ModeSymlink != 0 {
+			linkTarget := dstStat.LinkTarget
+			if !isAbs(linkTarget) {
+				// Join with the parent directory.
+				dstParent, _ := archive.SplitPathDirEntry(dstPath)
+				linkTarget = filepath.Join(dstParent, linkTarget)
+			}
+
+			dstInfo.Path = linkTarget
+			dstStat, err = apiClient.ContainerStatPath(ctx, ctr, linkTarget)
+		}
+		// Validate the destination path
+		if err == nil {
+			if err := command.ValidateOutputPathFileMode(dstStat.Mode); err != nil {
+				return dstInfo, fmt.Errorf(`destination "%s:%s" must be a directory or a regular file: %w`, ctr, dstPath, err)
+			}
+			dstInfo.Exists, dstInfo.IsDir = true, dstStat.Mode.IsDir()
+		}
+
+		// Ignore any error and assume that the parent directory of the destination
+		// path exists, in which case the copy may still succeed. If there is any
+		// type of conflict (e.g., non-directory overwriting an existing directory
+		// or vice versa) the extraction will fail. If the destination simply did
+		// not exist, but the parent directory does, the extraction will still
+		// succeed.
+		_ = err // Intentionally ignore stat errors (see above)
+	}
+	return dstInfo, nil
+}
+
 // We use `:` as a delimiter between CONTAINER and PATH, but `:` could also be
 // in a valid LOCALPATH, like `file:name.txt`. We can resolve this ambiguity by
 // requiring a LOCALPATH with a `:` to be made explicit with a relative or
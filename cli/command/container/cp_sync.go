@@ -0,0 +1,1177 @@
+package container
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/image/build"
+	"github.com/moby/go-archive"
+	"github.com/moby/moby/api/pkg/stdcopy"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+	"github.com/moby/patternmatcher"
+	"github.com/sirupsen/logrus"
+)
+
+// syncOp is the change made to a file in the destination of a sync.
+type syncOp byte
+
+const (
+	syncAdd    syncOp = 'A'
+	syncUpdate syncOp = 'M'
+	syncDelete syncOp = 'D'
+)
+
+// execBatchSize is the maximum number of paths to pass to a command that is
+// executed in a container, such as "rm".
+const execBatchSize = 500
+
+// errNotDirectory is returned if the source or destination of a sync is not
+// a directory.
+var errNotDirectory = errors.New("not a directory")
+
+// syncAction is a change to make to a file in the destination of a sync.
+type syncAction struct {
+	op    syncOp
+	path  string
+	isDir bool
+}
+
+func (a syncAction) String() string {
+	if a.isDir {
+		return string(a.op) + " " + a.path + "/"
+	}
+	return string(a.op) + " " + a.path
+}
+
+// syncStats counts the files and bytes that are copied, skipped because they
+// are unchanged, and deleted by a sync. It is updated atomically as the sync
+// progresses.
+type syncStats struct {
+	copiedFiles  int64
+	copiedBytes  int64
+	skippedFiles int64
+	skippedBytes int64
+	deletedFiles int64
+}
+
+func (s *syncStats) copied(size int64) {
+	atomic.AddInt64(&s.copiedFiles, 1)
+	atomic.AddInt64(&s.copiedBytes, size)
+}
+
+func (s *syncStats) skipped(size int64) {
+	atomic.AddInt64(&s.skippedFiles, 1)
+	atomic.AddInt64(&s.skippedBytes, size)
+}
+
+func (s *syncStats) deleted() {
+	atomic.AddInt64(&s.deletedFiles, 1)
+}
+
+// progress returns the progress of the sync, for printing after the number
+// of bytes transferred.
+func (s *syncStats) progress() string {
+	return fmt.Sprintf(" (%d files copied, %d unchanged)", atomic.LoadInt64(&s.copiedFiles), atomic.LoadInt64(&s.skippedFiles))
+}
+
+// summary returns a summary of the changes made, or to be made if dryRun
+// is set.
+func (s *syncStats) summary(dryRun bool) string {
+	if dryRun {
+		return fmt.Sprintf("%d files (%s) to copy, %d unchanged files (%s) to skip, %d files to delete",
+			s.copiedFiles, progressHumanSize(s.copiedBytes), s.skippedFiles, progressHumanSize(s.skippedBytes), s.deletedFiles)
+	}
+	return fmt.Sprintf("copied %d files (%s), skipped %d unchanged files (%s), deleted %d files",
+		s.copiedFiles, progressHumanSize(s.copiedBytes), s.skippedFiles, progressHumanSize(s.skippedBytes), s.deletedFiles)
+}
+
+// syncEntry describes a file in the source or destination of a sync.
+type syncEntry struct {
+	// typeflag is the tar type of the file; only regular files, directories,
+	// and symbolic links are compared.
+	typeflag byte
+	size     int64
+	modTime  time.Time
+	linkname string
+	// hash is the SHA-256 digest of the content of regular files, and only
+	// set when comparing checksums.
+	hash string
+}
+
+func tarSyncEntry(hdr *tar.Header) syncEntry {
+	e := syncEntry{
+		typeflag: hdr.Typeflag,
+		size:     hdr.Size,
+		modTime:  hdr.ModTime.Truncate(time.Second),
+		linkname: hdr.Linkname,
+	}
+	//nolint:staticcheck // ignore SA1019: TypeRegA is deprecated but we may still receive it from older archives.
+	if e.typeflag == tar.TypeRegA {
+		e.typeflag = tar.TypeReg
+	}
+	return e
+}
+
+func localSyncEntry(p string, fi os.FileInfo, checksum bool) (syncEntry, error) {
+	e := syncEntry{modTime: fi.ModTime().Truncate(time.Second)}
+	var err error
+	switch {
+	case fi.Mode().IsRegular():
+		e.typeflag, e.size = tar.TypeReg, fi.Size()
+		if checksum {
+			e.hash, err = hashFile(p)
+		}
+	case fi.IsDir():
+		e.typeflag = tar.TypeDir
+	case fi.Mode()&os.ModeSymlink != 0:
+		e.typeflag = tar.TypeSymlink
+		e.linkname, err = os.Readlink(p)
+	}
+	return e, err
+}
+
+// unchanged returns whether other is the same as e. Regular files are the
+// same if they have the same size, and either the same modification time,
+// or the same content if checksums are compared.
+func (e syncEntry) unchanged(other syncEntry, checksum bool) bool {
+	if e.typeflag != other.typeflag {
+		return false
+	}
+	switch e.typeflag {
+	case tar.TypeDir:
+		return true
+	case tar.TypeSymlink:
+		return e.linkname == other.linkname
+	case tar.TypeReg:
+		if e.size != other.size {
+			return false
+		}
+		if checksum {
+			return e.hash == other.hash
+		}
+		return e.modTime.Equal(other.modTime)
+	default:
+		return false
+	}
+}
+
+func hashFile(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return hashReader(f)
+}
+
+func hashReader(r io.Reader) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// syncRelPath returns the path of a file in an archive of a directory in a
+// container, relative to that directory. Archives of directories contain
+// the directory itself as first path element, for which false is returned.
+func syncRelPath(name string) (string, bool, error) {
+	_, rel, ok := strings.Cut(strings.Trim(name, "/"), "/")
+	if !ok || rel == "" {
+		return "", false, nil
+	}
+	rel = path.Clean(rel)
+	if !filepath.IsLocal(filepath.FromSlash(rel)) {
+		return "", false, fmt.Errorf("invalid path in archive: %q", name)
+	}
+	return rel, true, nil
+}
+
+// listLocalTree returns the files in a local directory, keyed by their
+// slash-separated path relative to the directory, and the list of paths in
+// lexical order. If sub is set, only the files in that subdirectory (and
+// the subdirectory itself) are returned. Excluded files are omitted.
+func listLocalTree(root, sub string, checksum bool, excl *syncExcluder) (map[string]syncEntry, []string, error) {
+	entries := make(map[string]syncEntry)
+	var paths []string
+	err := filepath.WalkDir(filepath.Join(root, filepath.FromSlash(sub)), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if excl.excluded(rel, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		e, err := localSyncEntry(p, fi, checksum)
+		if err != nil {
+			return err
+		}
+		if e.typeflag == 0 {
+			// sockets, devices, and other special files are not synced.
+			return nil
+		}
+		entries[rel] = e
+		paths = append(paths, rel)
+		return nil
+	})
+	return entries, paths, err
+}
+
+// syncExcluder matches paths, relative to the source of a sync, against
+// exclude patterns in the format of a .dockerignore file.
+type syncExcluder struct {
+	// mu protects pm, which is not safe to use concurrently.
+	mu sync.Mutex
+	pm *patternmatcher.PatternMatcher
+}
+
+// newSyncExcluder returns an excluder for the patterns in the .dockerignore
+// file in the root of the source, if any, and the given patterns. It returns
+// nil if there are no patterns.
+func newSyncExcluder(root string, patterns []string) (*syncExcluder, error) {
+	ignored, err := build.ReadDockerignore(root)
+	if err != nil {
+		return nil, err
+	}
+	patterns = append(ignored, patterns...)
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	pm, err := patternmatcher.New(patterns)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exclude pattern: %w", err)
+	}
+	return &syncExcluder{pm: pm}, nil
+}
+
+// excluded returns whether a path is excluded. Directories are not excluded
+// if exclusion patterns (such as "!dir/keep") may include files in them.
+func (e *syncExcluder) excluded(rel string, isDir bool) bool {
+	if e == nil {
+		return false
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	matched, err := e.pm.MatchesOrParentMatches(rel)
+	if err != nil || !matched {
+		return false
+	}
+	return !isDir || !e.pm.Exclusions()
+}
+
+// findStatCmd lists the files in the working directory with their mode (in
+// hexadecimal), size, and modification time. Both the GNU and BusyBox
+// versions of "find" and "stat" support these options.
+var findStatCmd = []string{"find", ".", "-mindepth", "1", "-exec", "stat", "-c", "%f %s %Y %n", "{}", "+"}
+
+// File types in the mode printed by "stat".
+const (
+	statTypeMask    = 0o170000
+	statTypeDir     = 0o040000
+	statTypeReg     = 0o100000
+	statTypeSymlink = 0o120000
+)
+
+// listContainerTree returns the files in a directory in a container, keyed by
+// their slash-separated path relative to the directory. If checksum is set,
+// the content of the regular files that have the same size as the file with
+// the same path in local is hashed.
+//
+// The API has no endpoint to list files, so the files are listed by running
+// "find" and "stat" in the container, which doesn't transfer their content.
+// If that fails, for example because the container is not running, or has no
+// "find" or "stat" utility, the files are listed by reading an archive of
+// the directory instead.
+func listContainerTree(ctx context.Context, apiClient client.ContainerAPIClient, ctr, dir string, local map[string]syncEntry, checksum bool) (map[string]syncEntry, error) {
+	entries, err := execListContainerTree(ctx, apiClient, ctr, dir)
+	if err == nil && checksum {
+		err = hashContainerFiles(ctx, apiClient, ctr, dir, entries, local)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		logrus.Debugf("failed to list files in %s:%s, reading an archive instead: %v", ctr, dir, err)
+		return archiveListContainerTree(ctx, apiClient, ctr, dir, checksum)
+	}
+	return entries, nil
+}
+
+// execListContainerTree lists the files in a directory in a container by
+// running "find" and "stat" in the container. The targets of symbolic links
+// are read from an archive of each link, which only contains its header.
+func execListContainerTree(ctx context.Context, apiClient client.ContainerAPIClient, ctr, dir string) (map[string]syncEntry, error) {
+	out, err := execOutput(ctx, apiClient, ctr, dir, findStatCmd)
+	if err != nil {
+		return nil, err
+	}
+	entries := make(map[string]syncEntry)
+	for _, line := range strings.Split(string(out), "\n") {
+		if line == "" {
+			continue
+		}
+		rel, e, err := parseStatLine(line)
+		if err != nil {
+			return nil, err
+		}
+		switch e.typeflag {
+		case 0:
+			// sockets, devices, and other special files are not synced.
+			continue
+		case tar.TypeSymlink:
+			if e.linkname, err = readContainerLink(ctx, apiClient, ctr, path.Join(dir, rel)); err != nil {
+				return nil, err
+			}
+		}
+		entries[rel] = e
+	}
+	return entries, nil
+}
+
+// parseStatLine parses a line printed by findStatCmd.
+func parseStatLine(line string) (string, syncEntry, error) {
+	invalid := fmt.Errorf("unexpected output of stat: %q", line)
+	fields := strings.SplitN(line, " ", 4)
+	if len(fields) != 4 || !strings.HasPrefix(fields[3], "./") {
+		return "", syncEntry{}, invalid
+	}
+	mode, err := strconv.ParseUint(fields[0], 16, 32)
+	if err != nil {
+		return "", syncEntry{}, invalid
+	}
+	size, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return "", syncEntry{}, invalid
+	}
+	mtime, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return "", syncEntry{}, invalid
+	}
+	rel := path.Clean(fields[3])
+	if !filepath.IsLocal(filepath.FromSlash(rel)) {
+		return "", syncEntry{}, fmt.Errorf("invalid path: %q", fields[3])
+	}
+
+	e := syncEntry{modTime: time.Unix(mtime, 0)}
+	switch mode & statTypeMask {
+	case statTypeReg:
+		e.typeflag, e.size = tar.TypeReg, size
+	case statTypeDir:
+		e.typeflag = tar.TypeDir
+	case statTypeSymlink:
+		e.typeflag = tar.TypeSymlink
+	}
+	return rel, e, nil
+}
+
+// readContainerLink returns the target of a symbolic link in a container.
+func readContainerLink(ctx context.Context, apiClient client.ContainerAPIClient, ctr, p string) (string, error) {
+	content, _, err := apiClient.CopyFromContainer(ctx, ctr, p)
+	if err != nil {
+		return "", err
+	}
+	defer content.Close()
+	hdr, err := tar.NewReader(content).Next()
+	if err != nil {
+		return "", err
+	}
+	return hdr.Linkname, nil
+}
+
+// hashContainerFiles sets the SHA-256 digest of the regular files in entries
+// that have the same size as the local file with the same path, by running
+// "sha256sum" in the container. The digest of other files is not needed to
+// compare them.
+func hashContainerFiles(ctx context.Context, apiClient client.ContainerAPIClient, ctr, dir string, entries, local map[string]syncEntry) error {
+	var paths []string
+	for p, e := range entries {
+		if l, ok := local[p]; ok && e.typeflag == tar.TypeReg && l.typeflag == tar.TypeReg && e.size == l.size {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+	for len(paths) > 0 {
+		batch := paths
+		if len(batch) > execBatchSize {
+			batch = batch[:execBatchSize]
+		}
+		paths = paths[len(batch):]
+
+		cmd := []string{"sha256sum"}
+		for _, p := range batch {
+			cmd = append(cmd, "./"+p)
+		}
+		out, err := execOutput(ctx, apiClient, ctr, dir, cmd)
+		if err != nil {
+			return err
+		}
+		// Lines are formatted as "<digest>  ./<path>". Lines of files with
+		// special characters in their name are escaped, and not matched;
+		// those files are considered changed.
+		for _, line := range strings.Split(string(out), "\n") {
+			digest, name, ok := strings.Cut(line, "  ./")
+			if !ok || len(digest) != sha256.Size*2 {
+				continue
+			}
+			if e, ok := entries[name]; ok {
+				e.hash = digest
+				entries[name] = e
+			}
+		}
+	}
+	return nil
+}
+
+// archiveListContainerTree lists the files in a directory in a container by
+// reading an archive of the directory, which contains the content of all
+// files.
+func archiveListContainerTree(ctx context.Context, apiClient client.ContainerAPIClient, ctr, dir string, checksum bool) (map[string]syncEntry, error) {
+	content, _, err := apiClient.CopyFromContainer(ctx, ctr, dir)
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+
+	entries := make(map[string]syncEntry)
+	tr := tar.NewReader(content)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		rel, ok, err := syncRelPath(hdr.Name)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		e := tarSyncEntry(hdr)
+		if checksum && e.typeflag == tar.TypeReg {
+			if e.hash, err = hashReader(tr); err != nil {
+				return nil, err
+			}
+		}
+		entries[rel] = e
+	}
+	return entries, nil
+}
+
+// statContainerDir returns whether a directory exists in a container. It
+// returns an error if the path exists, but is not a directory.
+func statContainerDir(ctx context.Context, apiClient client.ContainerAPIClient, ctr, dir string) (bool, error) {
+	stat, err := apiClient.ContainerStatPath(ctx, ctr, dir)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if !stat.Mode.IsDir() {
+		return true, errNotDirectory
+	}
+	return true, nil
+}
+
+// planSync returns the changes to make to the destination to make it the
+// same as the source, adding the unchanged files to stats. Files in the
+// destination that are not in the source are deleted if del is set.
+func planSync(paths []string, src, dst map[string]syncEntry, checksum, del bool, stats *syncStats) []syncAction {
+	var actions []syncAction
+	for _, p := range paths {
+		s := src[p]
+		d, ok := dst[p]
+		switch {
+		case !ok:
+			actions = append(actions, syncAction{op: syncAdd, path: p, isDir: s.typeflag == tar.TypeDir})
+		case s.unchanged(d, checksum):
+			if s.typeflag == tar.TypeReg {
+				stats.skipped(s.size)
+			}
+		default:
+			actions = append(actions, syncAction{op: syncUpdate, path: p, isDir: s.typeflag == tar.TypeDir})
+		}
+	}
+	if del {
+		var deleted []string
+		for p := range dst {
+			if _, ok := src[p]; !ok {
+				deleted = append(deleted, p)
+			}
+		}
+		sort.Strings(deleted)
+		var deletedPaths []string
+		for _, p := range deleted {
+			// Only delete the top-most paths, as deleting a directory
+			// deletes its contents.
+			if hasParent(p, deletedPaths) {
+				continue
+			}
+			deletedPaths = append(deletedPaths, p)
+			actions = append(actions, syncAction{op: syncDelete, path: p, isDir: dst[p].typeflag == tar.TypeDir})
+			stats.deleted()
+		}
+	}
+	return actions
+}
+
+// containerSyncPath returns the cleaned, absolute path of a path in a
+// container. Relative paths are relative to the root of the container.
+func containerSyncPath(p string) string {
+	return path.Clean("/" + filepath.ToSlash(p))
+}
+
+// resolveSyncSource returns the absolute path of the local directory that is
+// the source of a sync, with symbolic links resolved.
+func resolveSyncSource(sourcePath string) (string, error) {
+	srcPath, err := resolveLocalPath(sourcePath)
+	if err != nil {
+		return "", err
+	}
+	if srcPath, err = filepath.EvalSymlinks(srcPath); err != nil {
+		return "", err
+	}
+	if fi, err := os.Stat(srcPath); err != nil {
+		return "", err
+	} else if !fi.IsDir() {
+		return "", fmt.Errorf("source %q must be a directory", sourcePath)
+	}
+	return srcPath, nil
+}
+
+// syncToContainer copies the files in a local directory that are new or
+// changed compared to a directory in a container.
+func syncToContainer(ctx context.Context, dockerCLI command.Cli, copyConfig cpConfig) error {
+	srcPath, err := resolveSyncSource(copyConfig.sourcePath)
+	if err != nil {
+		return err
+	}
+	excl, err := newSyncExcluder(srcPath, copyConfig.excludes)
+	if err != nil {
+		return err
+	}
+	_, err = syncTreeToContainer(ctx, dockerCLI, copyConfig, srcPath, excl)
+	return err
+}
+
+// syncTreeToContainer syncs a local directory to a directory in a container,
+// and returns the files in the destination after the sync. Files that are
+// excluded are not copied, nor deleted from the destination.
+func syncTreeToContainer(ctx context.Context, dockerCLI command.Cli, copyConfig cpConfig, srcPath string, excl *syncExcluder) (map[string]syncEntry, error) {
+	dstPath := containerSyncPath(copyConfig.destPath)
+
+	src, paths, err := listLocalTree(srcPath, "", copyConfig.checksum, excl)
+	if err != nil {
+		return nil, err
+	}
+
+	apiClient := dockerCLI.Client()
+	exists, err := statContainerDir(ctx, apiClient, copyConfig.container, dstPath)
+	if err != nil {
+		if errors.Is(err, errNotDirectory) {
+			return nil, fmt.Errorf(`destination "%s:%s" must be a directory`, copyConfig.container, dstPath)
+		}
+		return nil, err
+	}
+	dst := make(map[string]syncEntry)
+	if exists {
+		dst, err = listContainerTree(ctx, apiClient, copyConfig.container, dstPath, src, copyConfig.checksum)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for p, e := range dst {
+		if excl.excluded(p, e.typeflag == tar.TypeDir) {
+			delete(dst, p)
+		}
+	}
+
+	stats := &syncStats{}
+	actions := planSync(paths, src, dst, copyConfig.checksum, copyConfig.delete, stats)
+
+	var sendPaths, deletePaths []string
+	for _, a := range actions {
+		switch a.op {
+		case syncDelete:
+			deletePaths = append(deletePaths, a.path)
+		default:
+			sendPaths = append(sendPaths, a.path)
+		}
+	}
+
+	if copyConfig.dryRun {
+		for _, a := range actions {
+			if a.op != syncDelete && src[a.path].typeflag == tar.TypeReg {
+				stats.copied(src[a.path].size)
+			}
+			_, _ = fmt.Fprintln(dockerCLI.Out(), a)
+		}
+		if !copyConfig.quiet {
+			_, _ = fmt.Fprintln(dockerCLI.Err(), "Dry run:", stats.summary(true))
+		}
+		return src, nil
+	}
+
+	// Remove files before sending new ones, as files may be replaced by
+	// directories of the same name.
+	if err := removeInContainer(ctx, apiClient, copyConfig.container, dstPath, deletePaths); err != nil {
+		return nil, err
+	}
+
+	if len(sendPaths) > 0 || !exists {
+		// Archives are extracted in the parent directory of the destination,
+		// so that the destination is created if it does not exist.
+		extractDir, prefix := path.Split(dstPath)
+		if prefix == "" {
+			extractDir = "/"
+		}
+		var copiedSize int64
+		var content io.ReadCloser = newSyncArchive(srcPath, prefix, sendPaths, !exists, src, stats)
+		defer content.Close()
+
+		options := client.CopyToContainerOptions{
+			CopyUIDGID: copyConfig.copyUIDGID,
+		}
+		if copyConfig.quiet {
+			if err := apiClient.CopyToContainer(ctx, copyConfig.container, extractDir, content, options); err != nil {
+				return nil, err
+			}
+		} else {
+			content = &copyProgressPrinter{
+				ReadCloser: content,
+				total:      &copiedSize,
+			}
+			ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+			restore, done := copyProgress(ctx, dockerCLI.Err(), copyToContainerHeader, &copiedSize, stats)
+			err := apiClient.CopyToContainer(ctx, copyConfig.container, extractDir, content, options)
+			cancel()
+			<-done
+			restore()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if !copyConfig.quiet {
+		_, _ = fmt.Fprintf(dockerCLI.Err(), "Successfully synced %s:%s: %s\n", copyConfig.container, dstPath, stats.summary(false))
+	}
+	synced := make(map[string]syncEntry, len(src))
+	for p, e := range src {
+		synced[p] = e
+	}
+	if !copyConfig.delete {
+		for p, e := range dst {
+			if _, ok := synced[p]; !ok {
+				synced[p] = e
+			}
+		}
+	}
+	return synced, nil
+}
+
+// newSyncArchive returns a tar archive of the given paths in a local
+// directory, with names prefixed with prefix. The directory itself is
+// included if includeRoot is set.
+func newSyncArchive(root, prefix string, paths []string, includeRoot bool, entries map[string]syncEntry, stats *syncStats) io.ReadCloser {
+	r, w := io.Pipe()
+	go func() {
+		_ = w.CloseWithError(writeSyncArchive(w, root, prefix, paths, includeRoot, entries, stats))
+	}()
+	return r
+}
+
+func writeSyncArchive(w io.Writer, root, prefix string, paths []string, includeRoot bool, entries map[string]syncEntry, stats *syncStats) error {
+	tw := tar.NewWriter(w)
+	if includeRoot && prefix != "" {
+		paths = append([]string{""}, paths...)
+	}
+	for _, p := range paths {
+		full := filepath.Join(root, filepath.FromSlash(p))
+		fi, err := os.Lstat(full)
+		if err != nil {
+			return err
+		}
+		hdr, err := archive.FileInfoHeader(path.Join(prefix, p), fi, entries[p].linkname)
+		if err != nil {
+			return err
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		f, err := os.Open(full)
+		if err != nil {
+			return err
+		}
+		_, err = io.CopyN(tw, f, hdr.Size)
+		_ = f.Close()
+		if err != nil {
+			return err
+		}
+		stats.copied(hdr.Size)
+	}
+	return tw.Close()
+}
+
+// removeInContainer removes paths, relative to a directory, in a container. The
+// API has no endpoint to remove files, so "rm" is executed in the container,
+// which must be running.
+func removeInContainer(ctx context.Context, apiClient client.ContainerAPIClient, ctr, dir string, paths []string) error {
+	remove := paths
+	for len(remove) > 0 {
+		batch := remove
+		if len(batch) > execBatchSize {
+			batch = batch[:execBatchSize]
+		}
+		remove = remove[len(batch):]
+
+		cmd := []string{"rm", "-rf", "--"}
+		for _, p := range batch {
+			cmd = append(cmd, path.Join(dir, p))
+		}
+		if err := execInContainer(ctx, apiClient, ctr, cmd); err != nil {
+			return fmt.Errorf("failed to delete files in container: %w", err)
+		}
+	}
+	return nil
+}
+
+func execInContainer(ctx context.Context, apiClient client.ContainerAPIClient, ctr string, cmd []string) error {
+	resp, err := apiClient.ContainerExecCreate(ctx, ctr, container.ExecOptions{Cmd: cmd})
+	if err != nil {
+		return err
+	}
+	if err := apiClient.ContainerExecStart(ctx, resp.ID, container.ExecStartOptions{Detach: true}); err != nil {
+		return err
+	}
+	return waitExec(ctx, apiClient, resp.ID, cmd[0], nil)
+}
+
+// execOutput executes a command in a directory in a container, and returns
+// its standard output.
+func execOutput(ctx context.Context, apiClient client.ContainerAPIClient, ctr, dir string, cmd []string) ([]byte, error) {
+	resp, err := apiClient.ContainerExecCreate(ctx, ctr, container.ExecOptions{
+		Cmd:          cmd,
+		WorkingDir:   dir,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	attach, err := apiClient.ContainerExecAttach(ctx, resp.ID, container.ExecAttachOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer attach.Close()
+	var stdout, stderr bytes.Buffer
+	if _, err := stdcopy.StdCopy(&stdout, &stderr, attach.Reader); err != nil {
+		return nil, err
+	}
+	if err := waitExec(ctx, apiClient, resp.ID, cmd[0], &stderr); err != nil {
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+// waitExec waits for an exec to exit, and returns an error if it exited with
+// a non-zero status, including the error output if stderr is set.
+func waitExec(ctx context.Context, apiClient client.ContainerAPIClient, execID, name string, stderr *bytes.Buffer) error {
+	for {
+		res, err := apiClient.ContainerExecInspect(ctx, execID)
+		if err != nil {
+			return err
+		}
+		if !res.Running {
+			if res.ExitCode == 0 {
+				return nil
+			}
+			if stderr != nil && stderr.Len() > 0 {
+				return fmt.Errorf("%s exited with status %d: %s", name, res.ExitCode, strings.TrimSpace(stderr.String()))
+			}
+			return fmt.Errorf("%s exited with status %d", name, res.ExitCode)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// syncFromContainer copies the files in a directory in a container that are
+// new or changed compared to a local directory. Only the new and changed
+// files are copied from the container.
+func syncFromContainer(ctx context.Context, dockerCLI command.Cli, copyConfig cpConfig) error {
+	dstPath, err := resolveLocalPath(copyConfig.destPath)
+	if err != nil {
+		return err
+	}
+	dstPath = filepath.Clean(dstPath)
+	dstExists := true
+	if fi, err := os.Stat(dstPath); err == nil && !fi.IsDir() {
+		return fmt.Errorf("destination %q must be a directory", copyConfig.destPath)
+	} else if os.IsNotExist(err) {
+		dstExists = false
+	} else if err != nil {
+		return err
+	}
+	srcPath := containerSyncPath(copyConfig.sourcePath)
+
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+	defer cancel()
+
+	apiClient := dockerCLI.Client()
+	if exists, err := statContainerDir(ctx, apiClient, copyConfig.container, srcPath); errors.Is(err, errNotDirectory) {
+		return fmt.Errorf(`source "%s:%s" must be a directory`, copyConfig.container, srcPath)
+	} else if err != nil {
+		return err
+	} else if !exists {
+		return errdefs.ErrNotFound.WithMessage(fmt.Sprintf("no such directory in container: %s:%s", copyConfig.container, srcPath))
+	}
+
+	dst := make(map[string]syncEntry)
+	if dstExists {
+		if dst, _, err = listLocalTree(dstPath, "", copyConfig.checksum, nil); err != nil {
+			return err
+		}
+	}
+	src, err := listContainerTree(ctx, apiClient, copyConfig.container, srcPath, dst, copyConfig.checksum)
+	if err != nil {
+		return err
+	}
+	paths := make([]string, 0, len(src))
+	for p := range src {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	stats := &syncStats{}
+	actions := planSync(paths, src, dst, copyConfig.checksum, copyConfig.delete, stats)
+
+	if copyConfig.dryRun {
+		for _, a := range actions {
+			if a.op != syncDelete && src[a.path].typeflag == tar.TypeReg {
+				stats.copied(src[a.path].size)
+			}
+			_, _ = fmt.Fprintln(dockerCLI.Out(), a)
+		}
+		if !copyConfig.quiet {
+			_, _ = fmt.Fprintln(dockerCLI.Err(), "Dry run:", stats.summary(true))
+		}
+		return nil
+	}
+
+	s := &localSyncer{
+		root:       dstPath,
+		copyUIDGID: copyConfig.copyUIDGID,
+		stats:      stats,
+	}
+	if err := s.init(); err != nil {
+		return err
+	}
+	for _, a := range actions {
+		if a.op == syncDelete {
+			if err := s.remove(a.path); err != nil {
+				return err
+			}
+		}
+	}
+
+	fetch := func() error {
+		for _, p := range fetchPaths(actions, src, dstExists) {
+			if err := s.fetch(ctx, apiClient, copyConfig.container, srcPath, p); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if copyConfig.quiet {
+		err = fetch()
+	} else {
+		var copiedSize int64
+		s.progress = &copiedSize
+		restore, done := copyProgress(ctx, dockerCLI.Err(), copyFromContainerHeader, &copiedSize, stats)
+		err = fetch()
+		cancel()
+		<-done
+		restore()
+	}
+	if err != nil {
+		return err
+	}
+	if !copyConfig.quiet {
+		_, _ = fmt.Fprintf(dockerCLI.Err(), "Successfully synced %s: %s\n", dstPath, stats.summary(false))
+	}
+	return nil
+}
+
+// fetchPaths returns the paths to copy from a container to make the changes
+// to add and update files. Directories that are added are copied with their
+// content, and the whole tree is copied if the destination doesn't exist.
+func fetchPaths(actions []syncAction, src map[string]syncEntry, dstExists bool) []string {
+	if !dstExists {
+		return []string{""}
+	}
+	var paths, dirs []string
+	for _, a := range actions {
+		if a.op == syncDelete || hasParent(a.path, dirs) {
+			continue
+		}
+		paths = append(paths, a.path)
+		if src[a.path].typeflag == tar.TypeDir {
+			dirs = append(dirs, a.path)
+		}
+	}
+	return paths
+}
+
+// hasParent returns whether one of dirs is a parent directory of p.
+func hasParent(p string, dirs []string) bool {
+	for _, d := range dirs {
+		if strings.HasPrefix(p, d+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// localSyncer writes the files copied from a directory in a container to a
+// local directory.
+type localSyncer struct {
+	root       string
+	copyUIDGID bool
+	stats      *syncStats
+	// progress, if set, is updated with the number of bytes copied.
+	progress *int64
+
+	// resolvedRoot is the root with symbolic links evaluated, which is used
+	// to verify files are not written outside the root.
+	resolvedRoot string
+	// prefix is the path, relative to the root, of the file or directory
+	// that is being copied.
+	prefix string
+}
+
+func (s *localSyncer) init() error {
+	if err := os.MkdirAll(s.root, 0o755); err != nil {
+		return err
+	}
+	var err error
+	s.resolvedRoot, err = filepath.EvalSymlinks(s.root)
+	return err
+}
+
+// fetch copies a file or directory, relative to the root of the sync, from
+// a directory in a container.
+func (s *localSyncer) fetch(ctx context.Context, apiClient client.ContainerAPIClient, ctr, dir, rel string) error {
+	content, _, err := apiClient.CopyFromContainer(ctx, ctr, path.Join(dir, rel))
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+	if s.progress != nil {
+		content = &copyProgressPrinter{
+			ReadCloser: content,
+			total:      s.progress,
+		}
+	}
+	s.prefix = rel
+
+	tr := tar.NewReader(content)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		rel, ok, err := s.relPath(hdr.Name)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if err := s.syncEntry(tr, hdr, rel); err != nil {
+			return err
+		}
+	}
+}
+
+// relPath returns the path of a file in the archive that is being copied,
+// relative to the root. It returns false for the root itself.
+func (s *localSyncer) relPath(name string) (string, bool, error) {
+	rel, ok, err := syncRelPath(name)
+	if err != nil {
+		return "", false, err
+	}
+	if !ok {
+		// The archive contains the copied file or directory itself as
+		// first path element.
+		return s.prefix, s.prefix != "", nil
+	}
+	return path.Join(s.prefix, rel), true, nil
+}
+
+func (s *localSyncer) syncEntry(tr io.Reader, hdr *tar.Header, rel string) error {
+	target := filepath.Join(s.root, filepath.FromSlash(rel))
+	if err := s.checkTarget(target); err != nil {
+		return err
+	}
+	remote := tarSyncEntry(hdr)
+	switch remote.typeflag {
+	case tar.TypeReg, tar.TypeDir, tar.TypeSymlink, tar.TypeLink:
+	default:
+		// devices, and other special files are not synced.
+		return nil
+	}
+	if remote.typeflag == tar.TypeReg {
+		s.stats.copied(remote.size)
+	}
+
+	// Regular files are replaced by renaming a temporary file, and existing
+	// directories are kept; other files are removed first.
+	if fi, err := os.Lstat(target); err == nil {
+		keep := (fi.Mode().IsRegular() && remote.typeflag == tar.TypeReg) || (fi.IsDir() && remote.typeflag == tar.TypeDir)
+		if !keep {
+			if err := os.RemoveAll(target); err != nil {
+				return err
+			}
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	switch remote.typeflag {
+	case tar.TypeDir:
+		if err := os.Mkdir(target, hdr.FileInfo().Mode().Perm()); err != nil && !os.IsExist(err) {
+			return err
+		}
+	case tar.TypeSymlink:
+		if err := os.Symlink(hdr.Linkname, target); err != nil {
+			return err
+		}
+	case tar.TypeLink:
+		linked, err := s.linkTarget(hdr)
+		if err != nil {
+			return err
+		}
+		return os.Link(linked, target)
+	default:
+		tmp, err := s.writeTemp(tr, hdr, target)
+		if err != nil {
+			return err
+		}
+		return s.commitTemp(tmp, hdr, target)
+	}
+	return s.chown(hdr, target)
+}
+
+// remove removes a file or directory, relative to the root.
+func (s *localSyncer) remove(rel string) error {
+	target := filepath.Join(s.root, filepath.FromSlash(rel))
+	if err := s.checkTarget(target); err != nil {
+		return err
+	}
+	return os.RemoveAll(target)
+}
+
+// linkTarget returns the local path of the target of a hard link.
+func (s *localSyncer) linkTarget(hdr *tar.Header) (string, error) {
+	rel, _, err := s.relPath(hdr.Linkname)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.root, filepath.FromSlash(rel)), nil
+}
+
+// checkTarget verifies that the parent directory of target, with symbolic
+// links evaluated, is inside the root.
+func (s *localSyncer) checkTarget(target string) error {
+	parent, err := filepath.EvalSymlinks(filepath.Dir(target))
+	if err != nil {
+		return err
+	}
+	if parent != s.resolvedRoot && !strings.HasPrefix(parent, s.resolvedRoot+string(filepath.Separator)) {
+		return fmt.Errorf("invalid path %q: outside of destination %q", target, s.root)
+	}
+	return nil
+}
+
+func (s *localSyncer) writeTemp(r io.Reader, hdr *tar.Header, target string) (string, error) {
+	f, err := os.CreateTemp(filepath.Dir(target), ".docker-cp-*")
+	if err != nil {
+		return "", err
+	}
+	_, err = io.Copy(f, r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(f.Name(), hdr.FileInfo().Mode().Perm())
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+func (s *localSyncer) commitTemp(tmp string, hdr *tar.Header, target string) error {
+	if err := os.Rename(tmp, target); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := os.Chtimes(target, hdr.ModTime, hdr.ModTime); err != nil {
+		return err
+	}
+	return s.chown(hdr, target)
+}
+
+func (s *localSyncer) chown(hdr *tar.Header, target string) error {
+	if !s.copyUIDGID {
+		return nil
+	}
+	return os.Lchown(target, hdr.Uid, hdr.Gid)
+}
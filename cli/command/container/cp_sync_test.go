@@ -0,0 +1,624 @@
+package container
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/docker/cli/internal/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/moby/moby/api/pkg/stdcopy"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
+)
+
+var syncTestTime = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+type syncTestFile struct {
+	name    string
+	content string
+	dir     bool
+	modTime time.Time
+}
+
+// syncTestArchive returns an archive of a directory in a container, as
+// returned by the API, which contains the directory itself.
+func syncTestArchive(t *testing.T, base string, files ...syncTestFile) io.ReadCloser {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	writeSyncTestFile(t, tw, base, syncTestFile{dir: true, modTime: syncTestTime})
+	for _, f := range files {
+		writeSyncTestFile(t, tw, base+"/"+f.name, f)
+	}
+	assert.NilError(t, tw.Close())
+	return io.NopCloser(&buf)
+}
+
+func writeSyncTestFile(t *testing.T, tw *tar.Writer, name string, f syncTestFile) {
+	t.Helper()
+	hdr := &tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(f.content)), ModTime: f.modTime}
+	if f.dir {
+		hdr = &tar.Header{Name: name + "/", Typeflag: tar.TypeDir, Mode: 0o755, ModTime: f.modTime}
+	}
+	assert.NilError(t, tw.WriteHeader(hdr))
+	if !f.dir {
+		_, err := tw.Write([]byte(f.content))
+		assert.NilError(t, err)
+	}
+}
+
+// syncTestContainer emulates a directory in a container, in which "find",
+// "stat", "sha256sum", and "rm" are executed, and from which files are
+// copied as archives.
+type syncTestContainer struct {
+	t     *testing.T
+	dir   string
+	files []syncTestFile
+
+	// execs are the commands executed in the container, by exec ID.
+	execs map[string][]string
+	// copied are the paths copied from the container.
+	copied []string
+	// removed are the paths removed from the container.
+	removed []string
+}
+
+func newSyncTestContainer(t *testing.T, dir string, files ...syncTestFile) *syncTestContainer {
+	t.Helper()
+	return &syncTestContainer{t: t, dir: dir, files: files, execs: make(map[string][]string)}
+}
+
+func (c *syncTestContainer) install(f *fakeClient) *fakeClient {
+	f.containerStatPathFunc = func(ctr, p string) (container.PathStat, error) {
+		if p == c.dir {
+			return container.PathStat{Mode: os.ModeDir | 0o755}, nil
+		}
+		return container.PathStat{}, errdefs.ErrNotFound
+	}
+	f.execCreateFunc = func(ctr string, options container.ExecOptions) (container.ExecCreateResponse, error) {
+		id := strconv.Itoa(len(c.execs))
+		c.execs[id] = options.Cmd
+		if options.Cmd[0] == "rm" {
+			c.removed = append(c.removed, options.Cmd[3:]...)
+		} else {
+			assert.Check(c.t, is.Equal(options.WorkingDir, c.dir))
+		}
+		return container.ExecCreateResponse{ID: id}, nil
+	}
+	f.execAttachFunc = func(execID string, _ container.ExecAttachOptions) (client.HijackedResponse, error) {
+		out := c.execOutput(c.execs[execID])
+		server, conn := net.Pipe()
+		go func() {
+			_, _ = stdcopy.NewStdWriter(server, stdcopy.Stdout).Write(out)
+			_ = server.Close()
+		}()
+		return client.NewHijackedResponse(conn, ""), nil
+	}
+	f.containerCopyFromFunc = func(ctr, p string) (io.ReadCloser, container.PathStat, error) {
+		c.copied = append(c.copied, p)
+		return c.archive(p), container.PathStat{}, nil
+	}
+	return f
+}
+
+func (c *syncTestContainer) execOutput(cmd []string) []byte {
+	var buf bytes.Buffer
+	switch cmd[0] {
+	case "find":
+		for _, f := range c.files {
+			mode, size := "81a4", len(f.content)
+			if f.dir {
+				mode, size = "41ed", 4096
+			}
+			_, _ = fmt.Fprintf(&buf, "%s %d %d ./%s\n", mode, size, f.modTime.Unix(), f.name)
+		}
+	case "sha256sum":
+		for _, arg := range cmd[1:] {
+			for _, f := range c.files {
+				if "./"+f.name == arg {
+					_, _ = fmt.Fprintf(&buf, "%x  %s\n", sha256.Sum256([]byte(f.content)), arg)
+				}
+			}
+		}
+	}
+	return buf.Bytes()
+}
+
+// archive returns an archive of a file or directory in the container, as
+// returned by the API.
+func (c *syncTestContainer) archive(p string) io.ReadCloser {
+	if p == c.dir {
+		return syncTestArchive(c.t, path.Base(c.dir), c.files...)
+	}
+	rel := strings.TrimPrefix(p, c.dir+"/")
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, f := range c.files {
+		if f.name == rel || strings.HasPrefix(f.name, rel+"/") {
+			writeSyncTestFile(c.t, tw, path.Base(rel)+strings.TrimPrefix(f.name, rel), f)
+		}
+	}
+	assert.NilError(c.t, tw.Close())
+	return io.NopCloser(&buf)
+}
+
+func withSyncTestTime() fs.PathOp {
+	return fs.WithTimestamps(syncTestTime, syncTestTime)
+}
+
+func TestRunCopySyncInvalidOptions(t *testing.T) {
+	testcases := []struct {
+		doc         string
+		options     copyOptions
+		expectedErr string
+	}{
+		{
+			doc:         "delete without sync",
+			options:     copyOptions{source: "ctr:/path", destination: "/dest", delete: true},
+			expectedErr: "the --delete option requires --sync or --watch",
+		},
+		{
+			doc:         "dry-run without sync",
+			options:     copyOptions{source: "ctr:/path", destination: "/dest", dryRun: true},
+			expectedErr: "the --dry-run option requires --sync",
+		},
+		{
+			doc:         "checksum without sync",
+			options:     copyOptions{source: "ctr:/path", destination: "/dest", checksum: true},
+			expectedErr: "the --checksum option requires --sync or --watch",
+		},
+		{
+			doc:         "sync to stdout",
+			options:     copyOptions{source: "ctr:/path", destination: "-", sync: true},
+			expectedErr: `the --sync option cannot be used with "-" as source or destination`,
+		},
+		{
+			doc:         "exclude without sync",
+			options:     copyOptions{source: "/path", destination: "ctr:/dest", excludes: []string{"*.log"}},
+			expectedErr: "the --exclude option requires --sync or --watch",
+		},
+		{
+			doc:         "exclude from container",
+			options:     copyOptions{source: "ctr:/path", destination: "/dest", sync: true, excludes: []string{"*.log"}},
+			expectedErr: "the --exclude option is only supported when copying to a container",
+		},
+		{
+			doc:         "watch with dry-run",
+			options:     copyOptions{source: "/path", destination: "ctr:/dest", watch: true, dryRun: true},
+			expectedErr: "the --dry-run option cannot be used with --watch",
+		},
+		{
+			doc:         "watch from stdin",
+			options:     copyOptions{source: "-", destination: "ctr:/dest", watch: true},
+			expectedErr: `the --watch option cannot be used with "-" as source or destination`,
+		},
+		{
+			doc:         "watch from container",
+			options:     copyOptions{source: "ctr:/path", destination: "/dest", watch: true},
+			expectedErr: "the --watch option requires a container as destination",
+		},
+		{
+			doc:         "watch between containers",
+			options:     copyOptions{source: "ctr:/path", destination: "ctr2:/dest", watch: true},
+			expectedErr: "the --watch option requires a local source",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.doc, func(t *testing.T) {
+			err := runCopy(context.TODO(), test.NewFakeCli(&fakeClient{}), tc.options)
+			assert.Check(t, is.Error(err, tc.expectedErr))
+		})
+	}
+}
+
+func TestRunCopySyncToContainer(t *testing.T) {
+	srcDir := fs.NewDir(t, "cp-sync",
+		fs.WithFile("same", "same", withSyncTestTime()),
+		fs.WithFile("changed", "new content", withSyncTestTime()),
+		fs.WithFile("added", "added"),
+		fs.WithDir("dir", fs.WithFile("nested", "nested")),
+	)
+	ctr := newSyncTestContainer(t, "/data",
+		syncTestFile{name: "same", content: "same", modTime: syncTestTime},
+		syncTestFile{name: "changed", content: "old", modTime: syncTestTime},
+		syncTestFile{name: "removed", dir: true, modTime: syncTestTime},
+		syncTestFile{name: "removed/file", content: "removed", modTime: syncTestTime},
+	)
+	var sent []string
+	fakeCLI := test.NewFakeCli(ctr.install(&fakeClient{
+		containerCopyToFunc: func(ctr, dstPath string, content io.Reader, _ client.CopyToContainerOptions) error {
+			assert.Check(t, is.Equal(dstPath, "/"))
+			tr := tar.NewReader(content)
+			for {
+				hdr, err := tr.Next()
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				assert.NilError(t, err)
+				sent = append(sent, hdr.Name)
+			}
+		},
+	}))
+
+	t.Run("dry-run", func(t *testing.T) {
+		err := runCopy(context.TODO(), fakeCLI, copyOptions{
+			source:      srcDir.Path(),
+			destination: "ctr:/data",
+			sync:        true,
+			delete:      true,
+			dryRun:      true,
+		})
+		assert.NilError(t, err)
+		expected := `A added
+M changed
+A dir/
+A dir/nested
+D removed/
+`
+		assert.Check(t, is.Equal(fakeCLI.OutBuffer().String(), expected))
+		assert.Check(t, is.Equal(fakeCLI.ErrBuffer().String(), "Dry run: 3 files (22B) to copy, 1 unchanged files (4B) to skip, 1 files to delete\n"))
+		assert.Check(t, is.Len(sent, 0))
+		assert.Check(t, is.Len(ctr.removed, 0))
+		// files are listed without copying them from the container.
+		assert.Check(t, is.Len(ctr.copied, 0))
+	})
+
+	t.Run("sync", func(t *testing.T) {
+		fakeCLI.ResetOutputBuffers()
+		err := runCopy(context.TODO(), fakeCLI, copyOptions{
+			source:      srcDir.Path(),
+			destination: "ctr:/data",
+			sync:        true,
+			delete:      true,
+			quiet:       true,
+		})
+		assert.NilError(t, err)
+		assert.Check(t, is.DeepEqual(sent, []string{"data/added", "data/changed", "data/dir/", "data/dir/nested"}))
+		assert.Check(t, is.DeepEqual(ctr.removed, []string{"/data/removed"}))
+		assert.Check(t, is.Len(ctr.copied, 0))
+	})
+}
+
+func TestRunCopySyncToContainerArchiveFallback(t *testing.T) {
+	srcDir := fs.NewDir(t, "cp-sync",
+		fs.WithFile("same", "same", withSyncTestTime()),
+		fs.WithFile("changed", "new content", withSyncTestTime()),
+	)
+	var copied []string
+	fakeCLI := test.NewFakeCli(&fakeClient{
+		containerStatPathFunc: func(ctr, path string) (container.PathStat, error) {
+			return container.PathStat{Mode: os.ModeDir | 0o755}, nil
+		},
+		execCreateFunc: func(ctr string, options container.ExecOptions) (container.ExecCreateResponse, error) {
+			return container.ExecCreateResponse{}, errdefs.ErrConflict.WithMessage("container is not running")
+		},
+		containerCopyFromFunc: func(ctr, srcPath string) (io.ReadCloser, container.PathStat, error) {
+			copied = append(copied, srcPath)
+			return syncTestArchive(t, "data",
+				syncTestFile{name: "same", content: "same", modTime: syncTestTime},
+				syncTestFile{name: "changed", content: "old content", modTime: syncTestTime},
+			), container.PathStat{Mode: os.ModeDir | 0o755}, nil
+		},
+	})
+	err := runCopy(context.TODO(), fakeCLI, copyOptions{
+		source:      srcDir.Path(),
+		destination: "ctr:/data",
+		sync:        true,
+		dryRun:      true,
+		checksum:    true,
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(copied, []string{"/data"}))
+	assert.Check(t, is.Equal(fakeCLI.OutBuffer().String(), "M changed\n"))
+}
+
+func TestPlanSyncDeletesTopMostPaths(t *testing.T) {
+	dir := syncEntry{typeflag: tar.TypeDir}
+	file := syncEntry{typeflag: tar.TypeReg}
+	dst := map[string]syncEntry{
+		"a":       dir,
+		"a-b":     file,
+		"a.txt":   file,
+		"a/c":     file,
+		"a/d":     dir,
+		"a/d/e":   file,
+		"keep":    file,
+		"keep-me": file,
+	}
+	src := map[string]syncEntry{"keep": file}
+
+	var stats syncStats
+	actions := planSync([]string{"keep"}, src, dst, false, true, &stats)
+	assert.Check(t, is.DeepEqual(actions, []syncAction{
+		{op: syncDelete, path: "a", isDir: true},
+		{op: syncDelete, path: "a-b"},
+		{op: syncDelete, path: "a.txt"},
+		{op: syncDelete, path: "keep-me"},
+	}, cmp.AllowUnexported(syncAction{})))
+	assert.Check(t, is.Equal(stats.deletedFiles, int64(4)))
+}
+
+func TestParseStatLine(t *testing.T) {
+	testcases := []struct {
+		line        string
+		expectedRel string
+		expected    syncEntry
+		expectedErr string
+	}{
+		{
+			line:        "81a4 5 1704164645 ./dir/file name",
+			expectedRel: "dir/file name",
+			expected:    syncEntry{typeflag: tar.TypeReg, size: 5, modTime: syncTestTime},
+		},
+		{
+			line:        "41ed 4096 1704164645 ./dir",
+			expectedRel: "dir",
+			expected:    syncEntry{typeflag: tar.TypeDir, modTime: syncTestTime},
+		},
+		{
+			line:        "a1ff 6 1704164645 ./link",
+			expectedRel: "link",
+			expected:    syncEntry{typeflag: tar.TypeSymlink, modTime: syncTestTime},
+		},
+		{
+			line:        "c1ed 0 1704164645 ./socket",
+			expectedRel: "socket",
+			expected:    syncEntry{modTime: syncTestTime},
+		},
+		{
+			line:        "81a4 5 ./file",
+			expectedErr: `unexpected output of stat: "81a4 5 ./file"`,
+		},
+		{
+			line:        "81a4 5 1704164645 ./../file",
+			expectedErr: `invalid path: "./../file"`,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.line, func(t *testing.T) {
+			rel, e, err := parseStatLine(tc.line)
+			if tc.expectedErr != "" {
+				assert.Check(t, is.Error(err, tc.expectedErr))
+				return
+			}
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(rel, tc.expectedRel))
+			assert.Check(t, is.Equal(e.typeflag, tc.expected.typeflag))
+			assert.Check(t, is.Equal(e.size, tc.expected.size))
+			assert.Check(t, e.modTime.Equal(tc.expected.modTime))
+		})
+	}
+}
+
+func TestRunCopySyncToContainerMissingDestination(t *testing.T) {
+	srcDir := fs.NewDir(t, "cp-sync", fs.WithFile("file", "content"))
+
+	var sent []string
+	fakeCLI := test.NewFakeCli(&fakeClient{
+		containerStatPathFunc: func(ctr, path string) (container.PathStat, error) {
+			return container.PathStat{}, errdefs.ErrNotFound
+		},
+		containerCopyToFunc: func(ctr, dstPath string, content io.Reader, _ client.CopyToContainerOptions) error {
+			assert.Check(t, is.Equal(dstPath, "/srv/"))
+			tr := tar.NewReader(content)
+			for {
+				hdr, err := tr.Next()
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				assert.NilError(t, err)
+				sent = append(sent, hdr.Name)
+			}
+		},
+	})
+	err := runCopy(context.TODO(), fakeCLI, copyOptions{
+		source:      srcDir.Path(),
+		destination: "ctr:srv/data/",
+		sync:        true,
+		quiet:       true,
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(sent, []string{"data/", "data/file"}))
+}
+
+func TestRunCopySyncToContainerExcludes(t *testing.T) {
+	srcDir := fs.NewDir(t, "cp-sync",
+		fs.WithFile(".dockerignore", "*.log\n"),
+		fs.WithFile("app.log", "log"),
+		fs.WithFile("main.go", "package main"),
+		fs.WithDir("build", fs.WithFile("out", "binary")),
+	)
+
+	ctr := newSyncTestContainer(t, "/data",
+		syncTestFile{name: "server.log", content: "log", modTime: syncTestTime},
+		syncTestFile{name: "stale", content: "stale", modTime: syncTestTime},
+	)
+	var sent []string
+	fakeCLI := test.NewFakeCli(ctr.install(&fakeClient{
+		containerCopyToFunc: func(ctr, dstPath string, content io.Reader, _ client.CopyToContainerOptions) error {
+			tr := tar.NewReader(content)
+			for {
+				hdr, err := tr.Next()
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				assert.NilError(t, err)
+				sent = append(sent, hdr.Name)
+			}
+		},
+	}))
+	err := runCopy(context.TODO(), fakeCLI, copyOptions{
+		source:      srcDir.Path(),
+		destination: "ctr:/data",
+		sync:        true,
+		delete:      true,
+		quiet:       true,
+		excludes:    []string{"build"},
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(sent, []string{"data/.dockerignore", "data/main.go"}))
+	// excluded files in the destination are not deleted.
+	assert.Check(t, is.DeepEqual(ctr.removed, []string{"/data/stale"}))
+}
+
+func TestSyncExcluder(t *testing.T) {
+	var excl *syncExcluder
+	assert.Check(t, !excl.excluded("anything", false))
+
+	excl, err := newSyncExcluder(t.TempDir(), []string{"node_modules", "!node_modules/keep", "*.tmp"})
+	assert.NilError(t, err)
+	assert.Check(t, excl.excluded("file.tmp", false))
+	assert.Check(t, !excl.excluded("dir/file.tmp", false))
+	assert.Check(t, excl.excluded("node_modules/pkg", false))
+	assert.Check(t, !excl.excluded("node_modules/keep", false))
+	// directories with exclusions in them must still be walked.
+	assert.Check(t, !excl.excluded("node_modules", true))
+
+	_, err = newSyncExcluder(t.TempDir(), []string{"[invalid"})
+	assert.Check(t, is.ErrorContains(err, "invalid exclude pattern"))
+}
+
+func TestRunCopySyncFromContainer(t *testing.T) {
+	dstDir := fs.NewDir(t, "cp-sync",
+		fs.WithFile("same", "same", withSyncTestTime()),
+		fs.WithFile("same-size", "aaaa", withSyncTestTime()),
+		fs.WithFile("changed", "old", withSyncTestTime()),
+		fs.WithFile("extra", "extra"),
+		fs.WithDir("extra-dir", fs.WithFile("file", "file")),
+	)
+	ctr := newSyncTestContainer(t, "/data",
+		syncTestFile{name: "changed", content: "new content", modTime: syncTestTime},
+		syncTestFile{name: "dir", dir: true, modTime: syncTestTime},
+		syncTestFile{name: "dir/added", content: "added", modTime: syncTestTime},
+		syncTestFile{name: "same", content: "same", modTime: syncTestTime},
+		syncTestFile{name: "same-size", content: "bbbb", modTime: syncTestTime},
+	)
+	fakeCLI := test.NewFakeCli(ctr.install(&fakeClient{}))
+
+	t.Run("dry-run", func(t *testing.T) {
+		err := runCopy(context.TODO(), fakeCLI, copyOptions{
+			source:      "ctr:/data",
+			destination: dstDir.Path(),
+			sync:        true,
+			delete:      true,
+			dryRun:      true,
+			checksum:    true,
+		})
+		assert.NilError(t, err)
+		expected := `M changed
+A dir/
+A dir/added
+M same-size
+D extra
+D extra-dir/
+`
+		assert.Check(t, is.Equal(fakeCLI.OutBuffer().String(), expected))
+		assert.Check(t, is.Equal(fakeCLI.ErrBuffer().String(), "Dry run: 3 files (20B) to copy, 1 unchanged files (4B) to skip, 2 files to delete\n"))
+		assertDirContents(t, dstDir.Path(), []string{"changed", "extra", "extra-dir", "extra-dir/file", "same", "same-size"})
+		assert.Check(t, is.Len(ctr.copied, 0))
+	})
+
+	t.Run("sync", func(t *testing.T) {
+		fakeCLI.ResetOutputBuffers()
+		err := runCopy(context.TODO(), fakeCLI, copyOptions{
+			source:      "ctr:/data",
+			destination: dstDir.Path(),
+			sync:        true,
+			delete:      true,
+			quiet:       true,
+		})
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(fakeCLI.OutBuffer().String(), ""))
+		assertDirContents(t, dstDir.Path(), []string{"changed", "dir", "dir/added", "same", "same-size"})
+		assertFileContent(t, dstDir.Join("changed"), "new content")
+		assertFileContent(t, dstDir.Join("dir", "added"), "added")
+		// the file has the same size and modification time; it's only
+		// updated when comparing checksums.
+		assertFileContent(t, dstDir.Join("same-size"), "aaaa")
+		// only the changed file, and the added directory are copied.
+		assert.Check(t, is.DeepEqual(ctr.copied, []string{"/data/changed", "/data/dir"}))
+
+		fi, err := os.Stat(dstDir.Join("changed"))
+		assert.NilError(t, err)
+		assert.Check(t, fi.ModTime().Equal(syncTestTime))
+	})
+
+	t.Run("checksum", func(t *testing.T) {
+		ctr.copied = nil
+		err := runCopy(context.TODO(), fakeCLI, copyOptions{
+			source:      "ctr:/data",
+			destination: dstDir.Path(),
+			sync:        true,
+			checksum:    true,
+			quiet:       true,
+		})
+		assert.NilError(t, err)
+		assertFileContent(t, dstDir.Join("same-size"), "bbbb")
+		assert.Check(t, is.DeepEqual(ctr.copied, []string{"/data/same-size"}))
+		assertDirContents(t, dstDir.Path(), []string{"changed", "dir", "dir/added", "same", "same-size"})
+	})
+}
+
+func TestRunCopySyncFromContainerOutsideDestination(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symlinks requires elevated privileges on Windows")
+	}
+	outside := t.TempDir()
+	dstDir := fs.NewDir(t, "cp-sync")
+	assert.NilError(t, os.Symlink(outside, dstDir.Join("link")))
+	ctr := newSyncTestContainer(t, "/data",
+		syncTestFile{name: "link/escaped", content: "escaped", modTime: syncTestTime},
+	)
+	fakeCLI := test.NewFakeCli(ctr.install(&fakeClient{}))
+	err := runCopy(context.TODO(), fakeCLI, copyOptions{
+		source:      "ctr:/data",
+		destination: dstDir.Path(),
+		sync:        true,
+		quiet:       true,
+	})
+	assert.Check(t, is.ErrorContains(err, "outside of destination"))
+	_, err = os.Stat(filepath.Join(outside, "escaped"))
+	assert.Check(t, os.IsNotExist(err))
+}
+
+func assertDirContents(t *testing.T, dir string, expected []string) {
+	t.Helper()
+	var actual []string
+	err := filepath.Walk(dir, func(p string, _ os.FileInfo, err error) error {
+		if err != nil || p == dir {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		actual = append(actual, filepath.ToSlash(rel))
+		return err
+	})
+	assert.NilError(t, err)
+	sort.Strings(actual)
+	assert.Check(t, is.DeepEqual(actual, expected))
+}
+
+func assertFileContent(t *testing.T, file, expected string) {
+	t.Helper()
+	content, err := os.ReadFile(file)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(content), expected))
+}
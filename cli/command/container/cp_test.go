@@ -1,7 +1,9 @@
 package container
 
 import (
+	"archive/tar"
 	"context"
+	"errors"
 	"io"
 	"os"
 	"runtime"
@@ -12,6 +14,7 @@
 	"github.com/moby/go-archive"
 	"github.com/moby/go-archive/compression"
 	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
 	"gotest.tools/v3/fs"
@@ -24,12 +27,13 @@ func TestRunCopyWithInvalidArguments(t *testing.T) {
 		expectedErr string
 	}{
 		{
-			doc: "copy between container",
+			doc: "sync between containers",
 			options: copyOptions{
 				source:      "first:/path",
 				destination: "second:/path",
+				sync:        true,
 			},
-			expectedErr: "copying between containers is not supported",
+			expectedErr: "the --sync option is not supported when copying between containers",
 		},
 		{
 			doc: "copy without a container",
@@ -48,6 +52,50 @@ func TestRunCopyWithInvalidArguments(t *testing.T) {
 	}
 }
 
+func TestRunCopyAcrossContainers(t *testing.T) {
+	srcDir := fs.NewDir(t, "cp-test",
+		fs.WithDir("data", fs.WithFile("file1", "content\n")))
+
+	var copied []string
+	cli := test.NewFakeCli(&fakeClient{
+		containerCopyFromFunc: func(ctr, srcPath string) (io.ReadCloser, container.PathStat, error) {
+			assert.Check(t, is.Equal("first", ctr))
+			assert.Check(t, is.Equal("/data", srcPath))
+			readCloser, err := archive.TarWithOptions(srcDir.Path(), &archive.TarOptions{IncludeFiles: []string{"data"}})
+			return readCloser, container.PathStat{Name: "data", Mode: os.ModeDir | 0o755}, err
+		},
+		containerStatPathFunc: func(ctr, path string) (container.PathStat, error) {
+			assert.Check(t, is.Equal("second", ctr))
+			assert.Check(t, is.Equal("/backup", path))
+			return container.PathStat{Name: "backup", Mode: os.ModeDir | 0o755}, nil
+		},
+		containerCopyToFunc: func(ctr, dstPath string, content io.Reader, options client.CopyToContainerOptions) error {
+			assert.Check(t, is.Equal("second", ctr))
+			assert.Check(t, is.Equal("/backup", dstPath))
+			assert.Check(t, options.CopyUIDGID)
+			tr := tar.NewReader(content)
+			for {
+				hdr, err := tr.Next()
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				assert.NilError(t, err)
+				copied = append(copied, hdr.Name)
+			}
+		},
+	})
+	err := runCopy(context.TODO(), cli, copyOptions{
+		source:      "first:/data",
+		destination: "second:/backup",
+		copyUIDGID:  true,
+		quiet:       true,
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(copied, []string{"data/", "data/file1"}))
+	assert.Check(t, is.Equal("", cli.OutBuffer().String()))
+	assert.Check(t, is.Equal("", cli.ErrBuffer().String()))
+}
+
 func TestRunCopyFromContainerToStdout(t *testing.T) {
 	tarContent := "the tar content"
 
@@ -0,0 +1,225 @@
+package container
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/internal/fswatch"
+	"github.com/moby/moby/client"
+)
+
+// watchDebounce is the time to wait for changes to settle before copying
+// them to the container, so that a burst of changes (such as those made
+// when switching branches) are copied at once.
+const watchDebounce = 500 * time.Millisecond
+
+// watchToContainer syncs a local directory to a directory in a container, and
+// then copies changes in the local directory to the container as they are
+// made, until the context is cancelled.
+func watchToContainer(ctx context.Context, dockerCLI command.Cli, copyConfig cpConfig) error {
+	srcPath, err := resolveSyncSource(copyConfig.sourcePath)
+	if err != nil {
+		return err
+	}
+	excl, err := newSyncExcluder(srcPath, copyConfig.excludes)
+	if err != nil {
+		return err
+	}
+
+	// Start watching before the initial sync, so that changes made during
+	// the sync are not missed.
+	watcher, err := fswatch.New(srcPath, excl.excluded)
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	known, err := syncTreeToContainer(ctx, dockerCLI, copyConfig, srcPath, excl)
+	if err != nil {
+		return err
+	}
+	if !copyConfig.quiet {
+		_, _ = fmt.Fprintf(dockerCLI.Err(), "Watching %s for changes; press Ctrl-C to stop\n", copyConfig.sourcePath)
+	}
+
+	w := &containerWatcher{
+		dockerCLI:  dockerCLI,
+		copyConfig: copyConfig,
+		srcPath:    srcPath,
+		dstPath:    containerSyncPath(copyConfig.destPath),
+		excl:       excl,
+		known:      known,
+	}
+	pending := make(map[string]struct{})
+	var timer *time.Timer
+	var timerC <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-watcher.Errors():
+			return err
+		case p := <-watcher.Events():
+			pending[p] = struct{}{}
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.NewTimer(copyConfig.debounce)
+			timerC = timer.C
+		case <-timerC:
+			timerC = nil
+			changed := make([]string, 0, len(pending))
+			for p := range pending {
+				changed = append(changed, p)
+			}
+			pending = make(map[string]struct{})
+			if err := w.apply(ctx, changed); err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				_, _ = fmt.Fprintln(dockerCLI.Err(), "Failed to copy changes:", err)
+			}
+		}
+	}
+}
+
+// containerWatcher copies changes in a local directory to a directory in a
+// container.
+type containerWatcher struct {
+	dockerCLI  command.Cli
+	copyConfig cpConfig
+	srcPath    string
+	dstPath    string
+	excl       *syncExcluder
+
+	// known are the files in the destination, which is used to determine
+	// if files are added or updated, and which files to delete.
+	known map[string]syncEntry
+}
+
+// apply copies the changed paths to the container. New directories are
+// copied with their content, and paths that no longer exist are deleted
+// from the container if the --delete option is set.
+func (w *containerWatcher) apply(ctx context.Context, changed []string) error {
+	sort.Strings(changed)
+	if len(changed) > 0 && changed[0] == fswatch.Root {
+		// Changes may have been missed; sync the whole directory.
+		known, err := syncTreeToContainer(ctx, w.dockerCLI, w.copyConfig, w.srcPath, w.excl)
+		if err != nil {
+			return err
+		}
+		w.known = known
+		return nil
+	}
+
+	var actions []syncAction
+	var sendPaths, deletePaths []string
+	entries := make(map[string]syncEntry)
+	for _, p := range changed {
+		if _, ok := entries[p]; ok {
+			// Already added as part of a new directory.
+			continue
+		}
+		full := filepath.Join(w.srcPath, filepath.FromSlash(p))
+		fi, err := os.Lstat(full)
+		if errors.Is(err, fs.ErrNotExist) {
+			if _, ok := w.known[p]; ok && w.copyConfig.delete {
+				// Only delete the top-most paths, as deleting a directory
+				// deletes its contents.
+				if !hasParent(p, deletePaths) {
+					deletePaths = append(deletePaths, p)
+					actions = append(actions, syncAction{op: syncDelete, path: p, isDir: w.known[p].typeflag == tar.TypeDir})
+				}
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		e, err := localSyncEntry(full, fi, false)
+		if err != nil {
+			return err
+		}
+		if e.typeflag == 0 {
+			continue
+		}
+
+		prev, exists := w.known[p]
+		if exists && prev.typeflag != e.typeflag {
+			// A file is replaced by a directory, or the reverse.
+			deletePaths = append(deletePaths, p)
+			exists = false
+		}
+		op := syncUpdate
+		if !exists {
+			op = syncAdd
+		}
+		actions = append(actions, syncAction{op: op, path: p, isDir: e.typeflag == tar.TypeDir})
+		if e.typeflag != tar.TypeDir || exists {
+			// Changes to the content of existing directories are reported
+			// separately, so only the directory itself is copied.
+			entries[p] = e
+			sendPaths = append(sendPaths, p)
+			continue
+		}
+		sub, subPaths, err := listLocalTree(w.srcPath, p, false, w.excl)
+		if err != nil {
+			return err
+		}
+		for _, sp := range subPaths {
+			entries[sp] = sub[sp]
+			sendPaths = append(sendPaths, sp)
+		}
+	}
+
+	apiClient := w.dockerCLI.Client()
+	if err := removeInContainer(ctx, apiClient, w.copyConfig.container, w.dstPath, deletePaths); err != nil {
+		return err
+	}
+	for _, p := range deletePaths {
+		w.forget(p)
+	}
+	if len(sendPaths) > 0 {
+		extractDir, prefix := path.Split(w.dstPath)
+		if prefix == "" {
+			extractDir = "/"
+		}
+		content := newSyncArchive(w.srcPath, prefix, sendPaths, false, entries, &syncStats{})
+		err := apiClient.CopyToContainer(ctx, w.copyConfig.container, extractDir, content, client.CopyToContainerOptions{
+			CopyUIDGID: w.copyConfig.copyUIDGID,
+		})
+		_ = content.Close()
+		if err != nil {
+			return err
+		}
+		for _, p := range sendPaths {
+			w.known[p] = entries[p]
+		}
+	}
+	if !w.copyConfig.quiet {
+		// Changes are printed as progress, like the summary of a sync.
+		for _, a := range actions {
+			_, _ = fmt.Fprintln(w.dockerCLI.Err(), a)
+		}
+	}
+	return nil
+}
+
+// forget removes a path, and the paths in it, from the known files.
+func (w *containerWatcher) forget(p string) {
+	for k := range w.known {
+		if k == p || strings.HasPrefix(k, p+"/") {
+			delete(w.known, k)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package container
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
+)
+
+// readArchiveNames returns the names of the files in a tar archive.
+func readArchiveNames(t *testing.T, content io.Reader) []string {
+	t.Helper()
+	var names []string
+	tr := tar.NewReader(content)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return names
+		}
+		assert.NilError(t, err)
+		names = append(names, hdr.Name)
+	}
+}
+
+func TestContainerWatcherApply(t *testing.T) {
+	srcDir := fs.NewDir(t, "cp-watch",
+		fs.WithFile("changed", "new content"),
+		fs.WithDir("existing", fs.WithFile("file", "file")),
+		fs.WithDir("added", fs.WithFile("nested", "nested"), fs.WithFile("debug.log", "log")),
+		fs.WithDir("replaced"),
+	)
+
+	var (
+		sent    []string
+		removed []string
+	)
+	fakeCLI := test.NewFakeCli(&fakeClient{
+		containerCopyToFunc: func(ctr, dstPath string, content io.Reader, _ client.CopyToContainerOptions) error {
+			assert.Check(t, is.Equal(ctr, "ctr"))
+			assert.Check(t, is.Equal(dstPath, "/"))
+			sent = append(sent, readArchiveNames(t, content)...)
+			return nil
+		},
+		execCreateFunc: func(ctr string, options container.ExecOptions) (container.ExecCreateResponse, error) {
+			removed = append(removed, options.Cmd...)
+			return container.ExecCreateResponse{ID: "exec-id"}, nil
+		},
+	})
+	excl, err := newSyncExcluder(srcDir.Path(), []string{"*/*.log"})
+	assert.NilError(t, err)
+	w := &containerWatcher{
+		dockerCLI:  fakeCLI,
+		copyConfig: cpConfig{container: "ctr", delete: true},
+		srcPath:    srcDir.Path(),
+		dstPath:    "/data",
+		excl:       excl,
+		known: map[string]syncEntry{
+			"changed":        {typeflag: tar.TypeReg},
+			"existing":       {typeflag: tar.TypeDir},
+			"existing/file":  {typeflag: tar.TypeReg},
+			"removed":        {typeflag: tar.TypeDir},
+			"removed/file":   {typeflag: tar.TypeReg},
+			"removed-x":      {typeflag: tar.TypeReg},
+			"replaced":       {typeflag: tar.TypeReg},
+			"unknown/parent": {typeflag: tar.TypeReg},
+		},
+	}
+
+	err = w.apply(context.Background(), []string{"removed/file", "removed-x", "changed", "added/nested", "added", "removed", "replaced", "existing", "never-synced"})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(sent, []string{"data/added/", "data/added/nested", "data/changed", "data/existing/", "data/replaced/"}))
+	assert.Check(t, is.DeepEqual(removed, []string{"rm", "-rf", "--", "/data/removed", "/data/removed-x", "/data/replaced"}))
+	expected := `A added/
+M changed
+M existing/
+D removed/
+D removed-x
+A replaced/
+`
+	assert.Check(t, is.Equal(fakeCLI.ErrBuffer().String(), expected))
+
+	_, ok := w.known["removed/file"]
+	assert.Check(t, !ok)
+	assert.Check(t, is.Equal(w.known["replaced"].typeflag, byte(tar.TypeDir)))
+	assert.Check(t, is.Equal(w.known["added/nested"].typeflag, byte(tar.TypeReg)))
+}
+
+func TestRunCopyWatch(t *testing.T) {
+	srcDir := fs.NewDir(t, "cp-watch", fs.WithFile("file", "content"))
+
+	sent := make(chan []string, 10)
+	ctr := newSyncTestContainer(t, "/data")
+	fakeCLI := test.NewFakeCli(ctr.install(&fakeClient{
+		containerCopyToFunc: func(ctr, dstPath string, content io.Reader, _ client.CopyToContainerOptions) error {
+			sent <- readArchiveNames(t, content)
+			return nil
+		},
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- runCopy(ctx, fakeCLI, copyOptions{
+			source:      srcDir.Path(),
+			destination: "ctr:/data",
+			watch:       true,
+			quiet:       true,
+		})
+	}()
+
+	waitSent := func() []string {
+		t.Helper()
+		select {
+		case names := <-sent:
+			return names
+		case err := <-done:
+			t.Fatalf("unexpected exit: %v", err)
+		case <-time.After(10 * time.Second):
+			t.Fatal("timeout waiting for files to be copied")
+		}
+		return nil
+	}
+	assert.Check(t, is.DeepEqual(waitSent(), []string{"data/file"}))
+
+	assert.NilError(t, os.WriteFile(filepath.Join(srcDir.Path(), "new"), []byte("new"), 0o644))
+	assert.Check(t, is.DeepEqual(waitSent(), []string{"data/new"}))
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NilError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for watch to stop")
+	}
+	// the destination is listed without copying files from the container.
+	assert.Check(t, is.Len(ctr.copied, 0))
+}
@@ -10,7 +10,9 @@
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/completion"
 	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/internal/prompt"
 	"github.com/docker/cli/opts"
+	"github.com/google/shlex"
 	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/client"
 	"github.com/sirupsen/logrus"
@@ -43,18 +45,39 @@ func NewExecOptions() ExecOptions {
 func newExecCommand(dockerCLI command.Cli) *cobra.Command {
 	options := NewExecOptions()
 
+	notPaused := func(ctr container.Summary) bool {
+		return ctr.State != container.StatePaused
+	}
+
 	cmd := &cobra.Command{
 		Use:   "exec [OPTIONS] CONTAINER COMMAND [ARG...]",
 		Short: "Execute a command in a running container",
-		Args:  cli.RequiresMinArgs(2),
+		Args:  argsOrPicker(dockerCLI, cli.RequiresMinArgs(2)),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				var (
+					shell bool
+					err   error
+				)
+				if args, shell, err = pickExecArgs(cmd.Context(), dockerCLI, notPaused); err != nil {
+					return err
+				}
+				if shell {
+					// The default command is an interactive shell, which
+					// needs stdin, and a TTY unless the output is redirected.
+					if !cmd.Flags().Changed("interactive") {
+						options.Interactive = true
+					}
+					if !cmd.Flags().Changed("tty") {
+						options.TTY = dockerCLI.Out().IsTerminal()
+					}
+				}
+			}
 			containerIDorName := args[0]
 			options.Command = args[1:]
 			return RunExec(cmd.Context(), dockerCLI, containerIDorName, options)
 		},
-		ValidArgsFunction: completion.ContainerNames(dockerCLI, false, func(ctr container.Summary) bool {
-			return ctr.State != container.StatePaused
-		}),
+		ValidArgsFunction: completion.ContainerNames(dockerCLI, false, notPaused),
 		Annotations: map[string]string{
 			"category-top": "2",
 			"aliases":      "docker container exec, docker exec",
@@ -84,6 +107,35 @@ func newExecCommand(dockerCLI command.Cli) *cobra.Command {
 	return cmd
 }
 
+// defaultExecCommand is the command that is run if a container is selected
+// interactively, and no command is entered.
+const defaultExecCommand = "sh"
+
+// pickExecArgs requests the user to select a container, and the command to
+// run in it. It returns true if no command is entered, and the default
+// command is used.
+func pickExecArgs(ctx context.Context, dockerCLI command.Cli, filter func(container.Summary) bool) ([]string, bool, error) {
+	names, err := pickContainers(ctx, dockerCLI, pickOptions{
+		action:  "run a command in",
+		filters: []func(container.Summary) bool{filter},
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	input, err := prompt.ReadInput(ctx, dockerCLI.In(), dockerCLI.Err(), "Command to run ["+defaultExecCommand+"]: ")
+	if err != nil {
+		return nil, false, err
+	}
+	cmdArgs, err := shlex.Split(input)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid command: %w", err)
+	}
+	if len(cmdArgs) == 0 {
+		return append(names, defaultExecCommand), true, nil
+	}
+	return append(names, cmdArgs...), false, nil
+}
+
 // RunExec executes an `exec` command
 func RunExec(ctx context.Context, dockerCLI command.Cli, containerIDorName string, options ExecOptions) error {
 	execOptions, err := parseExec(options, dockerCLI.ConfigFile())
@@ -1,7 +1,9 @@
 package container
 
 import (
+	"math"
 	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/docker/cli/cli/command/formatter"
@@ -21,6 +23,12 @@
 	winMemUseHeader = "PRIV WORKING SET"  // Used only on Windows
 	memUseHeader    = "MEM USAGE / LIMIT" // Used only on Linux
 	pidsHeader      = "PIDS"              // Used only on Linux
+	cpuTrendHeader  = "CPU TREND"
+	memTrendHeader  = "MEM TREND"
+
+	// trendStatsColumns are the columns added to the default table format
+	// when showing the trend of the statistics.
+	trendStatsColumns = "\t{{.CPUTrend}}\t{{.MemTrend}}"
 
 	noValue = "--"
 )
@@ -40,6 +48,11 @@ type StatsEntry struct {
 	BlockWrite       float64
 	PidsCurrent      uint64 // Not used on Windows
 	IsInvalid        bool
+
+	// cpuHistory and memHistory are the most recent CPU percentages and
+	// memory usages, oldest first, used to render their trend.
+	cpuHistory []float64
+	memHistory []float64
 }
 
 // Stats represents an entity to store containers statistics synchronously
@@ -47,6 +60,9 @@ type Stats struct {
 	mutex sync.RWMutex
 	StatsEntry
 	err error
+
+	// historySize is the number of samples to keep for rendering trends.
+	historySize int
 }
 
 // GetError returns the container statistics error.
@@ -90,9 +106,23 @@ func (cs *Stats) SetStatistics(s StatsEntry) {
 	cs.mutex.Lock()
 	defer cs.mutex.Unlock()
 	s.Container = cs.Container
+	if cs.historySize > 0 {
+		s.cpuHistory = appendHistory(cs.cpuHistory, s.CPUPercentage, cs.historySize)
+		s.memHistory = appendHistory(cs.memHistory, s.Memory, cs.historySize)
+	}
 	cs.StatsEntry = s
 }
 
+// appendHistory returns a copy of history with v appended, keeping at most
+// size values. A copy is returned, as the history is shared with entries
+// returned by [Stats.GetStatistics].
+func appendHistory(history []float64, v float64, size int) []float64 {
+	if len(history) >= size {
+		history = history[len(history)-size+1:]
+	}
+	return append(append(make([]float64, 0, len(history)+1), history...), v)
+}
+
 // GetStatistics returns container statistics with other meta data such as the container name
 func (cs *Stats) GetStatistics() StatsEntry {
 	cs.mutex.RLock()
@@ -146,6 +176,8 @@ func statsFormatWrite(ctx formatter.Context, stats []StatsEntry, osType string,
 		"NetIO":     netIOHeader,
 		"BlockIO":   blockIOHeader,
 		"PIDs":      pidsHeader,
+		"CPUTrend":  cpuTrendHeader,
+		"MemTrend":  memTrendHeader,
 	}
 	statsCtx.os = osType
 	return ctx.Write(&statsCtx, render)
@@ -226,6 +258,42 @@ func (c *statsContext) PIDs() string {
 	return strconv.FormatUint(c.s.PidsCurrent, 10)
 }
 
+// CPUTrend returns a sparkline of the most recent CPU percentages.
+func (c *statsContext) CPUTrend() string {
+	if len(c.s.cpuHistory) == 0 {
+		return noValue
+	}
+	return sparkline(c.s.cpuHistory, 100)
+}
+
+// MemTrend returns a sparkline of the most recent memory usages, relative to
+// the memory limit if there is one.
+func (c *statsContext) MemTrend() string {
+	if len(c.s.memHistory) == 0 {
+		return noValue
+	}
+	return sparkline(c.s.memHistory, c.s.MemoryLimit)
+}
+
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline renders values as a sparkline, scaled from zero to the ceiling,
+// or to the largest value if it exceeds the ceiling.
+func sparkline(values []float64, ceiling float64) string {
+	for _, v := range values {
+		ceiling = math.Max(ceiling, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		var i int
+		if ceiling > 0 && v > 0 {
+			i = int(math.Round(v / ceiling * float64(len(sparkTicks)-1)))
+		}
+		b.WriteRune(sparkTicks[i])
+	}
+	return b.String()
+}
+
 func formatPercentage(val float64) string {
 	return strconv.FormatFloat(val, 'f', 2, 64) + "%"
 }
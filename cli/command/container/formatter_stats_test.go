@@ -477,3 +477,36 @@ func genStats() []statsContext {
 	}
 	return entries
 }
+
+func TestSparkline(t *testing.T) {
+	assert.Check(t, is.Equal(sparkline(nil, 100), ""))
+	assert.Check(t, is.Equal(sparkline([]float64{0, 25, 50, 75, 100}, 100), "▁▃▅▆█"))
+	// values exceeding the ceiling are scaled to the largest value
+	assert.Check(t, is.Equal(sparkline([]float64{100, 200}, 100), "▅█"))
+	// values are scaled to the largest value if there's no ceiling
+	assert.Check(t, is.Equal(sparkline([]float64{0, 0}, 0), "▁▁"))
+	assert.Check(t, is.Equal(sparkline([]float64{1, 7}, 0), "▂█"))
+}
+
+func TestStatsHistory(t *testing.T) {
+	s := NewStats("web")
+	s.historySize = 3
+	for i := 1; i <= 5; i++ {
+		s.SetStatistics(StatsEntry{CPUPercentage: float64(i), Memory: float64(i * 10)})
+	}
+	entry := s.GetStatistics()
+	assert.Check(t, is.DeepEqual(entry.cpuHistory, []float64{3, 4, 5}))
+	assert.Check(t, is.DeepEqual(entry.memHistory, []float64{30, 40, 50}))
+
+	var out bytes.Buffer
+	err := statsFormatWrite(formatter.Context{
+		Output: &out,
+		Format: "table {{.Container}}\t{{.CPUTrend}}\t{{.MemTrend}}",
+	}, []StatsEntry{entry, {Container: "idle"}}, "linux", false)
+	assert.NilError(t, err)
+	expected := `CONTAINER   CPU TREND   MEM TREND
+web         ▁▁▁         ▅▇█
+idle        --          --
+`
+	assert.Check(t, is.Equal(out.String(), expected))
+}
@@ -5,6 +5,7 @@
 
 import (
 	"context"
+	"errors"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
@@ -17,6 +18,7 @@
 type inspectOptions struct {
 	format string
 	size   bool
+	as     string
 	refs   []string
 }
 
@@ -39,11 +41,19 @@ func newInspectCommand(dockerCLI command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.StringVarP(&opts.format, "format", "f", "", flagsHelper.InspectFormatHelp)
 	flags.BoolVarP(&opts.size, "size", "s", false, "Display total file sizes")
+	flags.StringVar(&opts.as, "as", "", `Output the configuration as a "run" command or as a "compose" file`)
+	_ = cmd.RegisterFlagCompletionFunc("as", completion.FromList(inspectAsRun, inspectAsCompose))
 
 	return cmd
 }
 
 func runInspect(ctx context.Context, dockerCLI command.Cli, opts inspectOptions) error {
+	if opts.as != "" {
+		if opts.format != "" || opts.size {
+			return errors.New("the --as option cannot be used with --format or --size")
+		}
+		return runInspectAs(ctx, dockerCLI, opts)
+	}
 	apiClient := dockerCLI.Client()
 	return inspect.Inspect(dockerCLI.Out(), opts.refs, opts.format, func(ref string) (any, []byte, error) {
 		return apiClient.ContainerInspectWithRaw(ctx, ref, opts.size)
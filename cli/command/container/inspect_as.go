@@ -0,0 +1,1027 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.23
+
+package container
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/docker/cli/internal/lazyregexp"
+	"github.com/docker/cli/internal/volumespec"
+	dockerspec "github.com/moby/docker-image-spec/specs-go/v1"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/mount"
+	"github.com/moby/moby/api/types/network"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	inspectAsRun     = "run"
+	inspectAsCompose = "compose"
+
+	// defaultLogDriver is the logging driver that is used when no logging
+	// driver is configured for the daemon.
+	defaultLogDriver = "json-file"
+
+	// defaultShmSize is the default size of /dev/shm.
+	defaultShmSize = 64 * 1024 * 1024
+
+	// composeLabelPrefix is the prefix of the labels that are set on
+	// containers that are created by compose.
+	composeLabelPrefix = "com.docker.compose."
+)
+
+// runInspectAs prints the configuration of containers as "docker run"
+// commands, or as a compose file.
+func runInspectAs(ctx context.Context, dockerCLI command.Cli, opts inspectOptions) error {
+	if opts.as != inspectAsRun && opts.as != inspectAsCompose {
+		return fmt.Errorf("invalid value for --as: %q: must be %q or %q", opts.as, inspectAsRun, inspectAsCompose)
+	}
+
+	apiClient := dockerCLI.Client()
+	var (
+		ctrs []container.InspectResponse
+		errs []error
+	)
+	for _, ref := range opts.refs {
+		ctr, err := apiClient.ContainerInspect(ctx, ref)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if ctr.Config == nil || ctr.HostConfig == nil {
+			errs = append(errs, fmt.Errorf("container %s has no configuration", ref))
+			continue
+		}
+		// The configuration of the container includes the defaults of its
+		// image, which should not be repeated. If the image no longer exists,
+		// the full configuration is used.
+		if img, err := apiClient.ImageInspect(ctx, ctr.Image); err == nil {
+			ctr.Config = withoutImageDefaults(ctr.Config, img.Config)
+		}
+		ctrs = append(ctrs, ctr)
+	}
+
+	if opts.as == inspectAsRun {
+		for i, ctr := range ctrs {
+			if i > 0 {
+				_, _ = fmt.Fprintln(dockerCLI.Out())
+			}
+			_, _ = fmt.Fprintln(dockerCLI.Out(), formatRunCommand(runCommandArgs(ctr)))
+		}
+	} else if len(ctrs) > 0 {
+		project, warnings := composeProjectFromContainers(ctrs)
+		for _, w := range warnings {
+			_, _ = fmt.Fprintln(dockerCLI.Err(), "WARNING:", w)
+		}
+		enc := yaml.NewEncoder(dockerCLI.Out())
+		enc.SetIndent(2)
+		if err := enc.Encode(project); err != nil {
+			return err
+		}
+		if err := enc.Close(); err != nil {
+			return err
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// withoutImageDefaults returns a copy of the configuration of a container
+// without the options that are inherited from its image.
+func withoutImageDefaults(cfg *container.Config, img *dockerspec.DockerOCIImageConfig) *container.Config {
+	if img == nil {
+		return cfg
+	}
+	c := *cfg
+
+	c.Env = nil
+	imageEnv := make(map[string]struct{}, len(img.Env))
+	for _, e := range img.Env {
+		imageEnv[e] = struct{}{}
+	}
+	for _, e := range cfg.Env {
+		if _, ok := imageEnv[e]; !ok {
+			c.Env = append(c.Env, e)
+		}
+	}
+
+	c.Labels = make(map[string]string)
+	for k, v := range cfg.Labels {
+		if iv, ok := img.Labels[k]; !ok || iv != v {
+			c.Labels[k] = v
+		}
+	}
+
+	// The command of the image is only used if the entrypoint is not set
+	// when creating the container.
+	if reflect.DeepEqual([]string(cfg.Entrypoint), img.Entrypoint) {
+		c.Entrypoint = nil
+		if reflect.DeepEqual([]string(cfg.Cmd), img.Cmd) {
+			c.Cmd = nil
+		}
+	} else if len(cfg.Entrypoint) == 0 {
+		// The entrypoint of the image was reset.
+		c.Entrypoint = []string{""}
+	}
+
+	if cfg.WorkingDir == img.WorkingDir {
+		c.WorkingDir = ""
+	}
+	if cfg.User == img.User {
+		c.User = ""
+	}
+	if cfg.StopSignal == img.StopSignal {
+		c.StopSignal = ""
+	}
+	if reflect.DeepEqual(cfg.Healthcheck, img.Healthcheck) {
+		c.Healthcheck = nil
+	}
+
+	c.ExposedPorts = make(container.PortSet)
+	for p := range cfg.ExposedPorts {
+		if _, ok := img.ExposedPorts[string(p)]; !ok {
+			c.ExposedPorts[p] = struct{}{}
+		}
+	}
+	c.Volumes = make(map[string]struct{})
+	for v := range cfg.Volumes {
+		if _, ok := img.Volumes[v]; !ok {
+			c.Volumes[v] = struct{}{}
+		}
+	}
+	return &c
+}
+
+// runCommandArgs returns the arguments of a "docker run" command that creates
+// a container with the given configuration. Each element is an option and its
+// value, and the last element is the image and command.
+func runCommandArgs(ctr container.InspectResponse) [][]string {
+	cfg, hc := ctr.Config, ctr.HostConfig
+	var args [][]string
+	add := func(flag string, values ...string) {
+		for _, v := range values {
+			args = append(args, []string{flag, v})
+		}
+	}
+	addBool := func(flag string, set bool) {
+		if set {
+			args = append(args, []string{flag})
+		}
+	}
+	addInt := func(flag string, v int64) {
+		if v != 0 {
+			add(flag, strconv.FormatInt(v, 10))
+		}
+	}
+	addString := func(flag string, v string) {
+		if v != "" {
+			add(flag, v)
+		}
+	}
+
+	addString("--name", strings.TrimPrefix(ctr.Name, "/"))
+	addBool("--detach", !cfg.AttachStdout && !cfg.AttachStderr)
+	addBool("--interactive", cfg.OpenStdin)
+	addBool("--tty", cfg.Tty)
+	addBool("--rm", hc.AutoRemove)
+	if r := restartPolicy(hc.RestartPolicy); r != "" {
+		add("--restart", r)
+	}
+
+	if len(cfg.Entrypoint) > 0 {
+		add("--entrypoint", cfg.Entrypoint[0])
+	}
+	addString("--user", cfg.User)
+	addString("--workdir", cfg.WorkingDir)
+	add("--env", cfg.Env...)
+	for _, k := range sortedKeys(cfg.Labels) {
+		add("--label", k+"="+cfg.Labels[k])
+	}
+	if hostname := containerHostname(ctr); hostname != "" {
+		add("--hostname", hostname)
+	}
+	addString("--domainname", cfg.Domainname)
+
+	// Networking
+	networks := containerNetworks(ctr)
+	if len(networks) == 1 {
+		n := networks[0]
+		add("--network", n.name)
+		if n.endpoint != nil {
+			add("--network-alias", n.endpoint.Aliases...)
+			if ipam := n.endpoint.IPAMConfig; ipam != nil {
+				addString("--ip", ipam.IPv4Address)
+				addString("--ip6", ipam.IPv6Address)
+			}
+		}
+	} else {
+		for _, n := range networks {
+			add("--network", n.attachmentOpt())
+		}
+	}
+	addBool("--publish-all", hc.PublishAllPorts)
+	add("--publish", publishedPorts(hc.PortBindings)...)
+	add("--expose", exposedPorts(cfg.ExposedPorts, hc.PortBindings)...)
+	add("--dns", hc.DNS...)
+	add("--dns-search", hc.DNSSearch...)
+	add("--dns-option", hc.DNSOptions...)
+	add("--add-host", hc.ExtraHosts...)
+	add("--link", links(hc.Links)...)
+
+	// Storage
+	add("--volume", hc.Binds...)
+	add("--volume", anonymousVolumes(cfg.Volumes)...)
+	for _, m := range hc.Mounts {
+		add("--mount", formatMount(m))
+	}
+	for _, p := range sortedKeys(hc.Tmpfs) {
+		if opts := hc.Tmpfs[p]; opts != "" {
+			p += ":" + opts
+		}
+		add("--tmpfs", p)
+	}
+	add("--volumes-from", hc.VolumesFrom...)
+
+	// Resources
+	if hc.Memory != 0 {
+		add("--memory", formatBytes(hc.Memory))
+	}
+	if hc.MemoryReservation != 0 {
+		add("--memory-reservation", formatBytes(hc.MemoryReservation))
+	}
+	if hc.MemorySwap > 0 {
+		add("--memory-swap", formatBytes(hc.MemorySwap))
+	} else if hc.MemorySwap < 0 {
+		add("--memory-swap", "-1")
+	}
+	if hc.MemorySwappiness != nil && *hc.MemorySwappiness >= 0 {
+		add("--memory-swappiness", strconv.FormatInt(*hc.MemorySwappiness, 10))
+	}
+	if hc.NanoCPUs != 0 {
+		add("--cpus", formatNanoCPUs(hc.NanoCPUs))
+	}
+	addInt("--cpu-shares", hc.CPUShares)
+	addInt("--cpu-period", hc.CPUPeriod)
+	addInt("--cpu-quota", hc.CPUQuota)
+	addString("--cpuset-cpus", hc.CpusetCpus)
+	addString("--cpuset-mems", hc.CpusetMems)
+	if hc.PidsLimit != nil && *hc.PidsLimit > 0 {
+		add("--pids-limit", strconv.FormatInt(*hc.PidsLimit, 10))
+	}
+	addInt("--blkio-weight", int64(hc.BlkioWeight))
+	addBool("--oom-kill-disable", hc.OomKillDisable != nil && *hc.OomKillDisable)
+	addInt("--oom-score-adj", int64(hc.OomScoreAdj))
+	if hc.ShmSize != 0 && hc.ShmSize != defaultShmSize {
+		add("--shm-size", formatBytes(hc.ShmSize))
+	}
+	for _, u := range hc.Ulimits {
+		add("--ulimit", u.String())
+	}
+	add("--device", devices(hc.Devices)...)
+	add("--device-cgroup-rule", hc.DeviceCgroupRules...)
+	add("--gpus", gpus(hc.DeviceRequests)...)
+
+	// Security and isolation
+	addBool("--privileged", hc.Privileged)
+	addBool("--read-only", hc.ReadonlyRootfs)
+	addBool("--init", hc.Init != nil && *hc.Init)
+	add("--cap-add", hc.CapAdd...)
+	add("--cap-drop", hc.CapDrop...)
+	add("--security-opt", hc.SecurityOpt...)
+	add("--group-add", hc.GroupAdd...)
+	addString("--userns", string(hc.UsernsMode))
+	addString("--pid", string(hc.PidMode))
+	addString("--ipc", ipcMode(hc.IpcMode))
+	addString("--uts", string(hc.UTSMode))
+	if hc.CgroupnsMode == container.CgroupnsModeHost {
+		add("--cgroupns", string(hc.CgroupnsMode))
+	}
+	addString("--cgroup-parent", hc.CgroupParent)
+	addString("--runtime", containerRuntime(hc.Runtime))
+	addString("--isolation", isolation(hc.Isolation))
+	for _, k := range sortedKeys(hc.Sysctls) {
+		add("--sysctl", k+"="+hc.Sysctls[k])
+	}
+	for _, k := range sortedKeys(hc.Annotations) {
+		add("--annotation", k+"="+hc.Annotations[k])
+	}
+
+	// Logging, health, and stopping
+	if hc.LogConfig.Type != "" && hc.LogConfig.Type != defaultLogDriver {
+		add("--log-driver", hc.LogConfig.Type)
+	}
+	for _, k := range sortedKeys(hc.LogConfig.Config) {
+		add("--log-opt", k+"="+hc.LogConfig.Config[k])
+	}
+	if h := cfg.Healthcheck; h != nil {
+		if len(h.Test) > 0 && h.Test[0] == "NONE" {
+			args = append(args, []string{"--no-healthcheck"})
+		} else {
+			addString("--health-cmd", healthCmd(h.Test))
+			if h.Interval != 0 {
+				add("--health-interval", h.Interval.String())
+			}
+			if h.Timeout != 0 {
+				add("--health-timeout", h.Timeout.String())
+			}
+			if h.StartPeriod != 0 {
+				add("--health-start-period", h.StartPeriod.String())
+			}
+			if h.StartInterval != 0 {
+				add("--health-start-interval", h.StartInterval.String())
+			}
+			addInt("--health-retries", int64(h.Retries))
+		}
+	}
+	addString("--stop-signal", cfg.StopSignal)
+	if cfg.StopTimeout != nil {
+		add("--stop-timeout", strconv.Itoa(*cfg.StopTimeout))
+	}
+
+	image := []string{cfg.Image}
+	if len(cfg.Entrypoint) > 1 {
+		image = append(image, cfg.Entrypoint[1:]...)
+	}
+	return append(args, append(image, cfg.Cmd...))
+}
+
+// formatRunCommand formats the arguments of a "docker run" command, with an
+// option on each line.
+func formatRunCommand(args [][]string) string {
+	lines := []string{"docker run"}
+	for _, a := range args {
+		quoted := make([]string, 0, len(a))
+		for _, s := range a {
+			quoted = append(quoted, shellQuote(s))
+		}
+		lines = append(lines, "  "+strings.Join(quoted, " "))
+	}
+	return strings.Join(lines, " \\\n")
+}
+
+var shellSafeRe = lazyregexp.New(`^[a-zA-Z0-9_@%+=:,./-]+$`)
+
+// shellQuote quotes a string for use as an argument in a POSIX shell.
+func shellQuote(s string) string {
+	if shellSafeRe.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// composeProject is a compose file with the services for one or more
+// containers. Networks and volumes are not managed by the project, and are
+// declared as external.
+type composeProject struct {
+	Services composetypes.Services                 `yaml:"services"`
+	Networks map[string]composetypes.NetworkConfig `yaml:"networks,omitempty"`
+	Volumes  map[string]composetypes.VolumeConfig  `yaml:"volumes,omitempty"`
+}
+
+// composeProjectFromContainers returns a compose project with a service for
+// each container, and warnings for options that cannot be represented in a
+// compose file.
+func composeProjectFromContainers(ctrs []container.InspectResponse) (composeProject, []string) {
+	project := composeProject{
+		Networks: make(map[string]composetypes.NetworkConfig),
+		Volumes:  make(map[string]composetypes.VolumeConfig),
+	}
+	var warnings []string
+	for _, ctr := range ctrs {
+		svc, w := composeService(ctr)
+		warnings = append(warnings, w...)
+		project.Services = append(project.Services, svc)
+		for name := range svc.Networks {
+			project.Networks[name] = composetypes.NetworkConfig{External: composetypes.External{External: true}}
+		}
+		for _, v := range svc.Volumes {
+			if v.Type == string(mount.TypeVolume) && v.Source != "" {
+				project.Volumes[v.Source] = composetypes.VolumeConfig{External: composetypes.External{External: true}}
+			}
+		}
+	}
+	return project, warnings
+}
+
+// composeService returns a compose service that creates a container with the
+// given configuration.
+func composeService(ctr container.InspectResponse) (composetypes.ServiceConfig, []string) {
+	cfg, hc := ctr.Config, ctr.HostConfig
+	name := strings.TrimPrefix(ctr.Name, "/")
+	var warnings []string
+	warnf := func(format string, args ...any) {
+		warnings = append(warnings, name+": "+fmt.Sprintf(format, args...))
+	}
+
+	svc := composetypes.ServiceConfig{
+		Name:          name,
+		ContainerName: name,
+		Image:         cfg.Image,
+		Entrypoint:    cfg.Entrypoint,
+		Command:       cfg.Cmd,
+		User:          cfg.User,
+		WorkingDir:    cfg.WorkingDir,
+		Hostname:      containerHostname(ctr),
+		DomainName:    cfg.Domainname,
+		StdinOpen:     cfg.OpenStdin,
+		Tty:           cfg.Tty,
+		Restart:       restartPolicy(hc.RestartPolicy),
+		StopSignal:    cfg.StopSignal,
+
+		DNS:           hc.DNS,
+		DNSSearch:     hc.DNSSearch,
+		ExtraHosts:    hc.ExtraHosts,
+		ExternalLinks: links(hc.Links),
+		Expose:        exposedPorts(cfg.ExposedPorts, hc.PortBindings),
+
+		Devices:      devices(hc.Devices),
+		Privileged:   hc.Privileged,
+		ReadOnly:     hc.ReadonlyRootfs,
+		Init:         hc.Init,
+		CapAdd:       hc.CapAdd,
+		CapDrop:      hc.CapDrop,
+		SecurityOpt:  hc.SecurityOpt,
+		UserNSMode:   string(hc.UsernsMode),
+		Pid:          string(hc.PidMode),
+		Ipc:          ipcMode(hc.IpcMode),
+		CgroupParent: hc.CgroupParent,
+		Isolation:    isolation(hc.Isolation),
+		OomScoreAdj:  int64(hc.OomScoreAdj),
+		Sysctls:      hc.Sysctls,
+		Extras:       make(map[string]any),
+	}
+	if hc.AutoRemove {
+		warnf("the --rm option is not supported in compose files")
+	}
+	if hc.CgroupnsMode == container.CgroupnsModeHost {
+		svc.CgroupNSMode = string(hc.CgroupnsMode)
+	}
+	if len(cfg.Env) > 0 {
+		svc.Environment = make(composetypes.MappingWithEquals)
+		for _, e := range cfg.Env {
+			k, v, ok := strings.Cut(e, "=")
+			if ok {
+				svc.Environment[k] = &v
+			} else {
+				svc.Environment[k] = nil
+			}
+		}
+	}
+	for k, v := range cfg.Labels {
+		if strings.HasPrefix(k, composeLabelPrefix) {
+			continue
+		}
+		if svc.Labels == nil {
+			svc.Labels = make(composetypes.Labels)
+		}
+		svc.Labels[k] = v
+	}
+
+	// Networking
+	switch networks := containerNetworks(ctr); {
+	case len(networks) == 1 && networks[0].endpoint == nil:
+		svc.NetworkMode = networks[0].name
+	case len(networks) > 0:
+		svc.Networks = make(map[string]*composetypes.ServiceNetworkConfig)
+		for _, n := range networks {
+			var nc *composetypes.ServiceNetworkConfig
+			if ep := n.endpoint; len(ep.Aliases) > 0 || ep.IPAMConfig != nil || len(ep.DriverOpts) > 0 {
+				nc = &composetypes.ServiceNetworkConfig{
+					Aliases:    ep.Aliases,
+					DriverOpts: ep.DriverOpts,
+				}
+				if ep.IPAMConfig != nil {
+					nc.Ipv4Address = ep.IPAMConfig.IPv4Address
+					nc.Ipv6Address = ep.IPAMConfig.IPv6Address
+				}
+			}
+			svc.Networks[n.name] = nc
+		}
+	}
+	if hc.PublishAllPorts {
+		warnf("the --publish-all option is not supported in compose files")
+	}
+	for _, p := range sortedKeys(hc.PortBindings) {
+		target, _ := strconv.ParseUint(p.Port(), 10, 32)
+		for _, b := range hc.PortBindings[p] {
+			pc := composetypes.ServicePortConfig{Target: uint32(target), Protocol: p.Proto()}
+			if b.HostPort != "" {
+				published, err := strconv.ParseUint(b.HostPort, 10, 32)
+				if err != nil {
+					warnf("port range %s of published port %s is not supported in compose files", b.HostPort, p)
+					continue
+				}
+				pc.Published = uint32(published)
+			}
+			if b.HostIP != "" && b.HostIP != "0.0.0.0" && b.HostIP != "::" {
+				warnf("host IP %s of published port %s is not supported in compose files", b.HostIP, p)
+			}
+			svc.Ports = append(svc.Ports, pc)
+		}
+	}
+
+	// Storage
+	for _, b := range hc.Binds {
+		v, err := volumespec.Parse(b)
+		if err != nil {
+			warnf("invalid volume %s: %v", b, err)
+			continue
+		}
+		svc.Volumes = append(svc.Volumes, v)
+	}
+	for _, p := range anonymousVolumes(cfg.Volumes) {
+		svc.Volumes = append(svc.Volumes, composetypes.ServiceVolumeConfig{Type: string(mount.TypeVolume), Target: p})
+	}
+	for _, m := range hc.Mounts {
+		svc.Volumes = append(svc.Volumes, composeVolume(m))
+	}
+	for _, p := range sortedKeys(hc.Tmpfs) {
+		if opts := hc.Tmpfs[p]; opts != "" {
+			p += ":" + opts
+		}
+		svc.Tmpfs = append(svc.Tmpfs, p)
+	}
+	if len(hc.VolumesFrom) > 0 {
+		svc.Extras["volumes_from"] = hc.VolumesFrom
+	}
+
+	// Resources
+	if hc.NanoCPUs != 0 || hc.Memory != 0 || (hc.PidsLimit != nil && *hc.PidsLimit > 0) {
+		limits := &composetypes.ResourceLimit{MemoryBytes: composetypes.UnitBytes(hc.Memory)}
+		if hc.NanoCPUs != 0 {
+			limits.NanoCPUs = formatNanoCPUs(hc.NanoCPUs)
+		}
+		if hc.PidsLimit != nil && *hc.PidsLimit > 0 {
+			limits.Pids = *hc.PidsLimit
+		}
+		svc.Deploy.Resources.Limits = limits
+	}
+	if hc.MemoryReservation != 0 {
+		svc.Deploy.Resources.Reservations = &composetypes.Resource{MemoryBytes: composetypes.UnitBytes(hc.MemoryReservation)}
+	}
+	if hc.MemorySwap != 0 {
+		svc.Extras["memswap_limit"] = hc.MemorySwap
+	}
+	if hc.MemorySwappiness != nil && *hc.MemorySwappiness >= 0 {
+		svc.Extras["mem_swappiness"] = *hc.MemorySwappiness
+	}
+	if hc.CPUShares != 0 {
+		svc.Extras["cpu_shares"] = hc.CPUShares
+	}
+	if hc.CPUPeriod != 0 {
+		svc.Extras["cpu_period"] = hc.CPUPeriod
+	}
+	if hc.CPUQuota != 0 {
+		svc.Extras["cpu_quota"] = hc.CPUQuota
+	}
+	if hc.CpusetCpus != "" {
+		svc.Extras["cpuset"] = hc.CpusetCpus
+	}
+	if hc.OomKillDisable != nil && *hc.OomKillDisable {
+		svc.Extras["oom_kill_disable"] = true
+	}
+	if hc.ShmSize != 0 && hc.ShmSize != defaultShmSize {
+		svc.ShmSize = formatBytes(hc.ShmSize)
+	}
+	for _, u := range hc.Ulimits {
+		if svc.Ulimits == nil {
+			svc.Ulimits = make(map[string]*composetypes.UlimitsConfig)
+		}
+		if u.Soft == u.Hard {
+			svc.Ulimits[u.Name] = &composetypes.UlimitsConfig{Single: int(u.Soft)}
+		} else {
+			svc.Ulimits[u.Name] = &composetypes.UlimitsConfig{Soft: int(u.Soft), Hard: int(u.Hard)}
+		}
+	}
+	if g := gpus(hc.DeviceRequests); len(g) > 0 {
+		svc.Extras["gpus"] = g[0]
+	}
+	if len(hc.GroupAdd) > 0 {
+		svc.Extras["group_add"] = hc.GroupAdd
+	}
+	if len(hc.DNSOptions) > 0 {
+		svc.Extras["dns_opt"] = hc.DNSOptions
+	}
+	if r := containerRuntime(hc.Runtime); r != "" {
+		svc.Extras["runtime"] = r
+	}
+	if hc.UTSMode != "" {
+		svc.Extras["uts"] = string(hc.UTSMode)
+	}
+
+	// Logging, health, and stopping
+	if (hc.LogConfig.Type != "" && hc.LogConfig.Type != defaultLogDriver) || len(hc.LogConfig.Config) > 0 {
+		svc.Logging = &composetypes.LoggingConfig{Driver: hc.LogConfig.Type, Options: hc.LogConfig.Config}
+	}
+	if h := cfg.Healthcheck; h != nil {
+		if len(h.Test) > 0 && h.Test[0] == "NONE" {
+			svc.HealthCheck = &composetypes.HealthCheckConfig{Disable: true}
+		} else {
+			svc.HealthCheck = &composetypes.HealthCheckConfig{
+				Test:          h.Test,
+				Interval:      composeDuration(h.Interval),
+				Timeout:       composeDuration(h.Timeout),
+				StartPeriod:   composeDuration(h.StartPeriod),
+				StartInterval: composeDuration(h.StartInterval),
+			}
+			if h.Retries != 0 {
+				retries := uint64(h.Retries)
+				svc.HealthCheck.Retries = &retries
+			}
+		}
+	}
+	if cfg.StopTimeout != nil {
+		svc.StopGracePeriod = composeDuration(time.Duration(*cfg.StopTimeout) * time.Second)
+	}
+	return svc, warnings
+}
+
+// composeVolume returns the compose volume for a mount.
+func composeVolume(m mount.Mount) composetypes.ServiceVolumeConfig {
+	v := composetypes.ServiceVolumeConfig{
+		Type:        string(m.Type),
+		Source:      m.Source,
+		Target:      m.Target,
+		ReadOnly:    m.ReadOnly,
+		Consistency: string(m.Consistency),
+	}
+	if o := m.BindOptions; o != nil && o.Propagation != "" {
+		v.Bind = &composetypes.ServiceVolumeBind{Propagation: string(o.Propagation)}
+	}
+	if o := m.VolumeOptions; o != nil && (o.NoCopy || o.Subpath != "") {
+		v.Volume = &composetypes.ServiceVolumeVolume{NoCopy: o.NoCopy, Subpath: o.Subpath}
+	}
+	if o := m.ImageOptions; o != nil && o.Subpath != "" {
+		v.Image = &composetypes.ServiceVolumeImage{Subpath: o.Subpath}
+	}
+	if o := m.TmpfsOptions; o != nil && o.SizeBytes != 0 {
+		v.Tmpfs = &composetypes.ServiceVolumeTmpfs{Size: o.SizeBytes}
+	}
+	return v
+}
+
+func composeDuration(d time.Duration) *composetypes.Duration {
+	if d == 0 {
+		return nil
+	}
+	cd := composetypes.Duration(d)
+	return &cd
+}
+
+// containerNetwork is a network to which a container is connected.
+type containerNetwork struct {
+	name string
+
+	// endpoint holds the options that were set when connecting the
+	// container to the network. It is nil for networks that are not user
+	// defined, such as "host".
+	endpoint *network.EndpointSettings
+}
+
+// attachmentOpt returns the network in the format of the --network option.
+func (n containerNetwork) attachmentOpt() string {
+	if n.endpoint == nil {
+		return n.name
+	}
+	fields := []string{"name=" + n.name}
+	for _, a := range n.endpoint.Aliases {
+		fields = append(fields, "alias="+a)
+	}
+	if ipam := n.endpoint.IPAMConfig; ipam != nil {
+		if ipam.IPv4Address != "" {
+			fields = append(fields, "ip="+ipam.IPv4Address)
+		}
+		if ipam.IPv6Address != "" {
+			fields = append(fields, "ip6="+ipam.IPv6Address)
+		}
+	}
+	for _, k := range sortedKeys(n.endpoint.DriverOpts) {
+		fields = append(fields, "driver-opt="+k+"="+n.endpoint.DriverOpts[k])
+	}
+	if len(fields) == 1 {
+		return n.name
+	}
+	return formatCSV(fields)
+}
+
+// containerNetworks returns the networks to which a container is connected,
+// starting with the network that is used as network mode. It returns no
+// networks for containers that are connected to the default bridge network
+// only.
+func containerNetworks(ctr container.InspectResponse) []containerNetwork {
+	mode := ctr.HostConfig.NetworkMode
+	if mode == "" || mode == "default" {
+		mode = network.NetworkBridge
+	}
+	if !mode.IsUserDefined() {
+		if mode.IsBridge() {
+			return nil
+		}
+		return []containerNetwork{{name: string(mode)}}
+	}
+
+	var networks []containerNetwork
+	var settings map[string]*network.EndpointSettings
+	if ctr.NetworkSettings != nil {
+		settings = ctr.NetworkSettings.Networks
+	}
+	names := sortedKeys(settings)
+	sort.SliceStable(names, func(i, j int) bool {
+		return names[i] == string(mode) && names[j] != string(mode)
+	})
+	if len(names) == 0 {
+		names = []string{string(mode)}
+	}
+	for _, name := range names {
+		ep := &network.EndpointSettings{}
+		if s := settings[name]; s != nil {
+			ep.DriverOpts = s.DriverOpts
+			if s.IPAMConfig != nil && (s.IPAMConfig.IPv4Address != "" || s.IPAMConfig.IPv6Address != "") {
+				ep.IPAMConfig = &network.EndpointIPAMConfig{
+					IPv4Address: s.IPAMConfig.IPv4Address,
+					IPv6Address: s.IPAMConfig.IPv6Address,
+				}
+			}
+			for _, a := range s.Aliases {
+				// Older daemons add the short ID of the container as alias.
+				if !strings.HasPrefix(ctr.ID, a) && a != strings.TrimPrefix(ctr.Name, "/") {
+					ep.Aliases = append(ep.Aliases, a)
+				}
+			}
+		}
+		networks = append(networks, containerNetwork{name: name, endpoint: ep})
+	}
+	return networks
+}
+
+// containerHostname returns the hostname of a container, unless it is the
+// default hostname, or the hostname of the network namespace that it joined.
+func containerHostname(ctr container.InspectResponse) string {
+	if mode := ctr.HostConfig.NetworkMode; mode.IsHost() || mode.IsContainer() {
+		return ""
+	}
+	if len(ctr.ID) >= 12 && ctr.Config.Hostname == ctr.ID[:12] {
+		return ""
+	}
+	return ctr.Config.Hostname
+}
+
+func restartPolicy(p container.RestartPolicy) string {
+	switch {
+	case p.Name == "" || p.Name == container.RestartPolicyDisabled:
+		return ""
+	case p.Name == container.RestartPolicyOnFailure && p.MaximumRetryCount > 0:
+		return fmt.Sprintf("%s:%d", p.Name, p.MaximumRetryCount)
+	default:
+		return string(p.Name)
+	}
+}
+
+// publishedPorts returns the published ports in the format of the --publish
+// option.
+func publishedPorts(bindings container.PortMap) []string {
+	var ports []string
+	for _, p := range sortedKeys(bindings) {
+		port := p.Port()
+		if p.Proto() != "tcp" {
+			port += "/" + p.Proto()
+		}
+		for _, b := range bindings[p] {
+			switch {
+			case b.HostIP != "":
+				ip := b.HostIP
+				if strings.Contains(ip, ":") {
+					ip = "[" + ip + "]"
+				}
+				ports = append(ports, ip+":"+b.HostPort+":"+port)
+			case b.HostPort != "":
+				ports = append(ports, b.HostPort+":"+port)
+			default:
+				ports = append(ports, port)
+			}
+		}
+	}
+	return ports
+}
+
+// exposedPorts returns the exposed ports that are not published.
+func exposedPorts(exposed container.PortSet, bindings container.PortMap) []string {
+	var ports []string
+	for _, p := range sortedKeys(exposed) {
+		if _, ok := bindings[p]; ok {
+			continue
+		}
+		if p.Proto() == "tcp" {
+			ports = append(ports, p.Port())
+		} else {
+			ports = append(ports, string(p))
+		}
+	}
+	return ports
+}
+
+// anonymousVolumes returns the paths of anonymous volumes.
+func anonymousVolumes(volumes map[string]struct{}) []string {
+	return sortedKeys(volumes)
+}
+
+// links returns legacy links in the format of the --link option.
+func links(l []string) []string {
+	var out []string
+	for _, link := range l {
+		name, alias, ok := strings.Cut(link, ":")
+		if !ok {
+			continue
+		}
+		name = strings.TrimPrefix(name, "/")
+		alias = alias[strings.LastIndex(alias, "/")+1:]
+		if alias != name {
+			name += ":" + alias
+		}
+		out = append(out, name)
+	}
+	return out
+}
+
+// devices returns devices in the format of the --device option.
+func devices(devs []container.DeviceMapping) []string {
+	var out []string
+	for _, d := range devs {
+		s := d.PathOnHost
+		if d.PathInContainer != "" && d.PathInContainer != d.PathOnHost {
+			s += ":" + d.PathInContainer
+		}
+		if d.CgroupPermissions != "" && d.CgroupPermissions != "rwm" {
+			if d.PathInContainer == d.PathOnHost {
+				s += ":" + d.PathInContainer
+			}
+			s += ":" + d.CgroupPermissions
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// gpus returns GPU device requests in the format of the --gpus option.
+func gpus(requests []container.DeviceRequest) []string {
+	var out []string
+	for _, r := range requests {
+		if len(r.Capabilities) != 1 || !reflect.DeepEqual(r.Capabilities[0], []string{"gpu"}) {
+			continue
+		}
+		switch {
+		case len(r.DeviceIDs) > 0:
+			out = append(out, formatCSV([]string{"device=" + strings.Join(r.DeviceIDs, ",")}))
+		case r.Count < 0:
+			out = append(out, "all")
+		default:
+			out = append(out, strconv.Itoa(r.Count))
+		}
+	}
+	return out
+}
+
+func ipcMode(mode container.IpcMode) string {
+	if mode.IsPrivate() || mode.IsShareable() || mode == "" {
+		return ""
+	}
+	return string(mode)
+}
+
+func containerRuntime(name string) string {
+	if name == "runc" || name == "io.containerd.runc.v2" {
+		return ""
+	}
+	return name
+}
+
+func isolation(i container.Isolation) string {
+	if i.IsDefault() {
+		return ""
+	}
+	return string(i)
+}
+
+// healthCmd returns the health check command in the format of the
+// --health-cmd option, which is run with the shell.
+func healthCmd(test []string) string {
+	if len(test) < 2 {
+		return ""
+	}
+	if test[0] == "CMD-SHELL" {
+		return test[1]
+	}
+	quoted := make([]string, 0, len(test)-1)
+	for _, s := range test[1:] {
+		quoted = append(quoted, shellQuote(s))
+	}
+	return strings.Join(quoted, " ")
+}
+
+// formatMount returns a mount in the format of the --mount option.
+func formatMount(m mount.Mount) string {
+	fields := []string{"type=" + string(m.Type)}
+	if m.Source != "" {
+		fields = append(fields, "source="+m.Source)
+	}
+	fields = append(fields, "target="+m.Target)
+	if m.ReadOnly {
+		fields = append(fields, "readonly")
+	}
+	if m.Consistency != "" && m.Consistency != mount.ConsistencyDefault {
+		fields = append(fields, "consistency="+string(m.Consistency))
+	}
+	if o := m.BindOptions; o != nil {
+		if o.Propagation != "" {
+			fields = append(fields, "bind-propagation="+string(o.Propagation))
+		}
+		switch {
+		case o.NonRecursive:
+			fields = append(fields, "bind-recursive=disabled")
+		case o.ReadOnlyNonRecursive:
+			fields = append(fields, "bind-recursive=writable")
+		case o.ReadOnlyForceRecursive:
+			fields = append(fields, "bind-recursive=readonly")
+		}
+	}
+	if o := m.VolumeOptions; o != nil {
+		if o.NoCopy {
+			fields = append(fields, "volume-nocopy")
+		}
+		if o.Subpath != "" {
+			fields = append(fields, "volume-subpath="+o.Subpath)
+		}
+		if d := o.DriverConfig; d != nil {
+			if d.Name != "" {
+				fields = append(fields, "volume-driver="+d.Name)
+			}
+			for _, k := range sortedKeys(d.Options) {
+				fields = append(fields, "volume-opt="+k+"="+d.Options[k])
+			}
+		}
+		for _, k := range sortedKeys(o.Labels) {
+			fields = append(fields, "volume-label="+k+"="+o.Labels[k])
+		}
+	}
+	if o := m.ImageOptions; o != nil && o.Subpath != "" {
+		fields = append(fields, "image-subpath="+o.Subpath)
+	}
+	if o := m.TmpfsOptions; o != nil {
+		if o.SizeBytes != 0 {
+			fields = append(fields, "tmpfs-size="+strconv.FormatInt(o.SizeBytes, 10))
+		}
+		if o.Mode != 0 {
+			fields = append(fields, "tmpfs-mode="+strconv.FormatUint(uint64(o.Mode.Perm()), 8))
+		}
+	}
+	return formatCSV(fields)
+}
+
+// formatCSV formats fields as a CSV record, as used by options such as
+// --mount and --network, quoting fields that contain a comma.
+func formatCSV(fields []string) string {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write(fields)
+	w.Flush()
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// formatBytes formats a size in bytes in the largest unit that represents it
+// exactly, in the format accepted by options such as --memory.
+func formatBytes(n int64) string {
+	for _, u := range []struct {
+		suffix string
+		size   int64
+	}{{"g", 1 << 30}, {"m", 1 << 20}, {"k", 1 << 10}} {
+		if n%u.size == 0 {
+			return strconv.FormatInt(n/u.size, 10) + u.suffix
+		}
+	}
+	return strconv.FormatInt(n, 10)
+}
+
+func formatNanoCPUs(n int64) string {
+	return strconv.FormatFloat(float64(n)/1e9, 'f', -1, 64)
+}
+
+// sortedKeys returns the keys of a map in lexical order.
+func sortedKeys[K ~string, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
@@ -0,0 +1,205 @@
+package container
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	dockerspec "github.com/moby/docker-image-spec/specs-go/v1"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/image"
+	"github.com/moby/moby/api/types/mount"
+	"github.com/moby/moby/api/types/network"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func inspectAsTestContainer() container.InspectResponse {
+	pidsLimit := int64(100)
+	stopTimeout := 30
+	return container.InspectResponse{
+		ID:    "0123456789abcdef0123456789abcdef",
+		Name:  "/web",
+		Image: "sha256:image",
+		HostConfig: &container.HostConfig{
+			Binds:       []string{"/srv/www:/usr/share/nginx/html:ro", "cache:/var/cache/nginx"},
+			NetworkMode: "frontend",
+			PortBindings: container.PortMap{
+				"80/tcp":  {{HostPort: "8080"}},
+				"443/tcp": {{HostIP: "127.0.0.1", HostPort: "8443"}},
+			},
+			RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyOnFailure, MaximumRetryCount: 3},
+			LogConfig:     container.LogConfig{Type: "json-file", Config: map[string]string{"max-size": "10m"}},
+			CapAdd:        []string{"NET_ADMIN"},
+			Mounts: []mount.Mount{{
+				Type:         mount.TypeTmpfs,
+				Target:       "/tmp/cache",
+				TmpfsOptions: &mount.TmpfsOptions{SizeBytes: 1 << 20},
+			}},
+			Resources: container.Resources{
+				Memory:    512 << 20,
+				NanoCPUs:  1500000000,
+				PidsLimit: &pidsLimit,
+			},
+		},
+		Config: &container.Config{
+			Hostname:    "0123456789ab",
+			Image:       "nginx:alpine",
+			Env:         []string{"PATH=/usr/local/sbin:/usr/local/bin", "NGINX_HOST=example.com", "GREETING=hello world"},
+			Cmd:         []string{"nginx", "-g", "daemon off;"},
+			Labels:      map[string]string{"maintainer": "NGINX", "tier": "frontend"},
+			StopTimeout: &stopTimeout,
+			ExposedPorts: container.PortSet{
+				"80/tcp":  {},
+				"443/tcp": {},
+				"53/udp":  {},
+			},
+		},
+		NetworkSettings: &container.NetworkSettings{
+			Networks: map[string]*network.EndpointSettings{
+				"frontend": {Aliases: []string{"www", "0123456789ab"}},
+			},
+		},
+	}
+}
+
+func inspectAsTestImage(string) (image.InspectResponse, error) {
+	return image.InspectResponse{
+		Config: &dockerspec.DockerOCIImageConfig{
+			ImageConfig: ocispec.ImageConfig{
+				Env:          []string{"PATH=/usr/local/sbin:/usr/local/bin"},
+				Cmd:          []string{"nginx", "-g", "daemon off;"},
+				Labels:       map[string]string{"maintainer": "NGINX"},
+				ExposedPorts: map[string]struct{}{"80/tcp": {}},
+				StopSignal:   "SIGQUIT",
+			},
+		},
+	}, nil
+}
+
+func TestInspectAsRun(t *testing.T) {
+	fakeCLI := test.NewFakeCli(&fakeClient{
+		inspectFunc: func(ref string) (container.InspectResponse, error) {
+			if ref != "web" {
+				return container.InspectResponse{}, errors.New("no such container: " + ref)
+			}
+			return inspectAsTestContainer(), nil
+		},
+		imageInspectFunc: inspectAsTestImage,
+	})
+	cmd := newInspectCommand(fakeCLI)
+	cmd.SetArgs([]string{"--as", "run", "web", "nosuchcontainer"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), "no such container: nosuchcontainer"))
+
+	expected := `docker run \
+  --name web \
+  --detach \
+  --restart on-failure:3 \
+  --env NGINX_HOST=example.com \
+  --env 'GREETING=hello world' \
+  --label tier=frontend \
+  --network frontend \
+  --network-alias www \
+  --publish 127.0.0.1:8443:443 \
+  --publish 8080:80 \
+  --expose 53/udp \
+  --volume /srv/www:/usr/share/nginx/html:ro \
+  --volume cache:/var/cache/nginx \
+  --mount type=tmpfs,target=/tmp/cache,tmpfs-size=1048576 \
+  --memory 512m \
+  --cpus 1.5 \
+  --pids-limit 100 \
+  --cap-add NET_ADMIN \
+  --log-opt max-size=10m \
+  --stop-timeout 30 \
+  nginx:alpine
+`
+	assert.Check(t, is.Equal(fakeCLI.OutBuffer().String(), expected))
+}
+
+func TestInspectAsCompose(t *testing.T) {
+	fakeCLI := test.NewFakeCli(&fakeClient{
+		inspectFunc: func(string) (container.InspectResponse, error) {
+			return inspectAsTestContainer(), nil
+		},
+		imageInspectFunc: inspectAsTestImage,
+	})
+	cmd := newInspectCommand(fakeCLI)
+	cmd.SetArgs([]string{"--as", "compose", "web"})
+	assert.NilError(t, cmd.Execute())
+
+	expected := `services:
+  web:
+    cap_add:
+      - NET_ADMIN
+    container_name: web
+    deploy:
+      resources:
+        limits:
+          cpus: "1.5"
+          memory: "536870912"
+          pids: 100
+    environment:
+      GREETING: hello world
+      NGINX_HOST: example.com
+    expose:
+      - 53/udp
+    image: nginx:alpine
+    labels:
+      tier: frontend
+    logging:
+      driver: json-file
+      options:
+        max-size: 10m
+    networks:
+      frontend:
+        aliases:
+          - www
+    ports:
+      - target: 443
+        published: 8443
+        protocol: tcp
+      - target: 80
+        published: 8080
+        protocol: tcp
+    restart: on-failure:3
+    stop_grace_period: 30s
+    volumes:
+      - type: bind
+        source: /srv/www
+        target: /usr/share/nginx/html
+        read_only: true
+      - type: volume
+        source: cache
+        target: /var/cache/nginx
+      - type: tmpfs
+        target: /tmp/cache
+        tmpfs:
+          size: 1048576
+networks:
+  frontend:
+    external: true
+volumes:
+  cache:
+    external: true
+`
+	assert.Check(t, is.Equal(fakeCLI.OutBuffer().String(), expected))
+	assert.Check(t, is.Equal(fakeCLI.ErrBuffer().String(), "WARNING: web: host IP 127.0.0.1 of published port 443/tcp is not supported in compose files\n"))
+}
+
+func TestInspectAsInvalid(t *testing.T) {
+	for _, args := range [][]string{
+		{"--as", "kube", "web"},
+		{"--as", "run", "--format", "{{.ID}}", "web"},
+	} {
+		cmd := newInspectCommand(test.NewFakeCli(&fakeClient{}))
+		cmd.SetArgs(args)
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		assert.Check(t, cmd.Execute() != nil, args)
+	}
+}
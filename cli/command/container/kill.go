@@ -24,8 +24,14 @@ func newKillCommand(dockerCLI command.Cli) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "kill [OPTIONS] CONTAINER [CONTAINER...]",
 		Short: "Kill one or more running containers",
-		Args:  cli.RequiresMinArgs(1),
+		Args:  argsOrPicker(dockerCLI, cli.RequiresMinArgs(1)),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				var err error
+				if args, err = pickContainers(cmd.Context(), dockerCLI, pickOptions{action: "kill", multiple: true, confirm: true}); err != nil {
+					return err
+				}
+			}
 			opts.containers = args
 			return runKill(cmd.Context(), dockerCLI, &opts)
 		},
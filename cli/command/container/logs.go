@@ -2,11 +2,15 @@
 
 import (
 	"context"
+	"errors"
 	"io"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/completion"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/docker/cli/internal/logstream"
+	"github.com/docker/cli/opts"
 	"github.com/moby/moby/api/pkg/stdcopy"
 	"github.com/moby/moby/client"
 	"github.com/spf13/cobra"
@@ -19,21 +23,38 @@ type logsOptions struct {
 	timestamps bool
 	details    bool
 	tail       string
+	filter     opts.FilterOpt
+	format     string
+	grep       string
+	level      string
+	context    int
 
-	container string
+	containers []string
 }
 
 // newLogsCommand creates a new cobra.Command for "docker container logs"
 func newLogsCommand(dockerCLI command.Cli) *cobra.Command {
-	var opts logsOptions
+	options := logsOptions{filter: opts.NewFilterOpt()}
 
 	cmd := &cobra.Command{
-		Use:   "logs [OPTIONS] CONTAINER",
-		Short: "Fetch the logs of a container",
-		Args:  cli.ExactArgs(1),
+		Use:   "logs [OPTIONS] CONTAINER [CONTAINER...]",
+		Short: "Fetch the logs of one or more containers",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts.container = args[0]
-			return runLogs(cmd.Context(), dockerCLI, &opts)
+			if options.filter.Value().Len() == 0 {
+				if err := argsOrPicker(dockerCLI, cli.RequiresMinArgs(1))(cmd, args); err != nil {
+					return err
+				}
+				if len(args) == 0 {
+					var err error
+					if args, err = pickContainers(cmd.Context(), dockerCLI, pickOptions{action: "show the logs of", multiple: true, all: true}); err != nil {
+						return err
+					}
+				}
+			} else if len(args) > 0 {
+				return errors.New("filtering is not supported when specifying a list of containers")
+			}
+			options.containers = args
+			return runLogs(cmd.Context(), dockerCLI, &options)
 		},
 		Annotations: map[string]string{
 			"aliases": "docker container logs, docker logs",
@@ -43,18 +64,44 @@ func newLogsCommand(dockerCLI command.Cli) *cobra.Command {
 	}
 
 	flags := cmd.Flags()
-	flags.BoolVarP(&opts.follow, "follow", "f", false, "Follow log output")
-	flags.StringVar(&opts.since, "since", "", `Show logs since timestamp (e.g. "2013-01-02T13:23:37Z") or relative (e.g. "42m" for 42 minutes)`)
-	flags.StringVar(&opts.until, "until", "", `Show logs before a timestamp (e.g. "2013-01-02T13:23:37Z") or relative (e.g. "42m" for 42 minutes)`)
+	flags.BoolVarP(&options.follow, "follow", "f", false, "Follow log output")
+	flags.StringVar(&options.since, "since", "", `Show logs since timestamp (e.g. "2013-01-02T13:23:37Z") or relative (e.g. "42m" for 42 minutes)`)
+	flags.StringVar(&options.until, "until", "", `Show logs before a timestamp (e.g. "2013-01-02T13:23:37Z") or relative (e.g. "42m" for 42 minutes)`)
 	flags.SetAnnotation("until", "version", []string{"1.35"})
-	flags.BoolVarP(&opts.timestamps, "timestamps", "t", false, "Show timestamps")
-	flags.BoolVar(&opts.details, "details", false, "Show extra details provided to logs")
-	flags.StringVarP(&opts.tail, "tail", "n", "all", "Number of lines to show from the end of the logs")
+	flags.BoolVarP(&options.timestamps, "timestamps", "t", false, "Show timestamps")
+	flags.BoolVar(&options.details, "details", false, "Show extra details provided to logs")
+	flags.StringVarP(&options.tail, "tail", "n", "all", "Number of lines to show from the end of the logs")
+	flags.StringVar(&options.format, "format", "", flagsHelper.InspectFormatHelp)
+	flags.StringVar(&options.grep, "grep", "", "Only show lines matching the regular expression")
+	flags.StringVar(&options.level, "level", "", `Only show lines with at least the given log level ("trace", "debug", "info", "warn", "error", "fatal")`)
+	flags.IntVar(&options.context, "grep-context", 0, "Number of lines to show before and after lines selected by --grep or --level")
+
+	_ = cmd.RegisterFlagCompletionFunc("level", completion.FromList("trace", "debug", "info", "warn", "error", "fatal"))
+	flags.Var(&options.filter, "filter", "Show the logs of containers matching the filter (e.g. \"label=com.example=foo\" or \"project=myapp\")")
 	return cmd
 }
 
+// streamOptions returns the options for formatting and filtering logs.
+func (opts *logsOptions) streamOptions() logstream.Options {
+	return logstream.Options{
+		Format:     opts.format,
+		Grep:       opts.grep,
+		Level:      opts.level,
+		Context:    opts.context,
+		Timestamps: opts.timestamps,
+		Details:    opts.details,
+	}
+}
+
 func runLogs(ctx context.Context, dockerCli command.Cli, opts *logsOptions) error {
-	c, err := dockerCli.Client().ContainerInspect(ctx, opts.container)
+	if len(opts.containers) > 1 || opts.filter.Value().Len() > 0 || opts.streamOptions().Enabled() {
+		return runMultiLogs(ctx, dockerCli, opts)
+	}
+	var name string
+	if len(opts.containers) > 0 {
+		name = opts.containers[0]
+	}
+	c, err := dockerCli.Client().ContainerInspect(ctx, name)
 	if err != nil {
 		return err
 	}
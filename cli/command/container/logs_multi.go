@@ -0,0 +1,470 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.23
+
+package container
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/internal/logdetails"
+	"github.com/docker/cli/internal/logstream"
+	"github.com/docker/cli/internal/tui"
+	"github.com/moby/moby/api/pkg/stdcopy"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/events"
+	"github.com/moby/moby/api/types/filters"
+	"github.com/moby/moby/client"
+	"github.com/morikuni/aec"
+)
+
+// logsFlushDelay is how long log lines are held back when following the
+// logs of multiple containers, so that lines with an earlier timestamp
+// from other containers can be printed first.
+const logsFlushDelay = 250 * time.Millisecond
+
+// composeProjectLabel is the label set by compose on containers to
+// identify the project they belong to. The "project" filter for logs
+// is a shorthand for filtering on this label.
+const composeProjectLabel = "com.docker.compose.project"
+
+// logsPrefixColors are the colors used for the per-container prefix when
+// printing the logs of multiple containers.
+var logsPrefixColors = []aec.ANSI{
+	aec.CyanF,
+	aec.YellowF,
+	aec.GreenF,
+	aec.MagentaF,
+	aec.BlueF,
+	aec.LightCyanF,
+	aec.LightYellowF,
+	aec.LightGreenF,
+	aec.LightMagentaF,
+	aec.LightBlueF,
+}
+
+// logSource is a container whose logs are aggregated.
+type logSource struct {
+	id    string
+	name  string
+	tty   bool
+	color aec.ANSI
+}
+
+// logLine is a single line of log output of a container.
+type logLine struct {
+	source    int
+	timestamp time.Time
+	received  time.Time
+	stderr    bool
+	// rawTimestamp is the timestamp as returned by the daemon.
+	rawTimestamp []byte
+	// message is the log message, including the trailing newline.
+	message []byte
+}
+
+// logEvent is sent by the goroutines streaming the logs of a container
+// for each line of output, and when the stream ends.
+type logEvent struct {
+	source int
+	line   *logLine
+	done   bool
+	err    error
+}
+
+// runMultiLogs prints the logs of multiple containers, or the containers
+// matching the filters, interleaved by timestamp and prefixed with the
+// container's name. When following the logs of containers matching the
+// filters, containers that are started are attached to automatically.
+//
+// It is also used for the logs of a single container if the logs must be
+// formatted or filtered.
+func runMultiLogs(ctx context.Context, dockerCLI command.Cli, opts *logsOptions) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var printer *logstream.Printer
+	if streamOpts := opts.streamOptions(); streamOpts.Enabled() {
+		var err error
+		printer, err = logstream.NewPrinter(dockerCLI.Out(), dockerCLI.Err(), streamOpts)
+		if err != nil {
+			return err
+		}
+	}
+
+	apiClient := dockerCLI.Client()
+	logFilters := opts.filter.Value()
+	useFilters := logFilters.Len() > 0
+	if useFilters {
+		logFilters = withProjectFilter(logFilters)
+	}
+
+	var (
+		startEvents <-chan events.Message
+		eventErrs   <-chan error
+	)
+	if opts.follow && useFilters {
+		// Subscribe to events before listing the containers to not miss
+		// containers that are started in the meantime.
+		startEvents, eventErrs = apiClient.Events(ctx, client.EventsListOptions{
+			Filters: filters.NewArgs(
+				filters.Arg("type", string(events.ContainerEventType)),
+				filters.Arg("event", string(events.ActionStart)),
+			),
+		})
+	}
+
+	var containers []container.InspectResponse
+	if useFilters {
+		list, err := apiClient.ContainerList(ctx, client.ContainerListOptions{All: true, Filters: logFilters})
+		if err != nil {
+			return err
+		}
+		for _, c := range list {
+			ctr, err := apiClient.ContainerInspect(ctx, c.ID)
+			if err != nil {
+				return err
+			}
+			containers = append(containers, ctr)
+		}
+	} else {
+		for _, name := range opts.containers {
+			ctr, err := apiClient.ContainerInspect(ctx, name)
+			if err != nil {
+				return err
+			}
+			containers = append(containers, ctr)
+		}
+	}
+	sort.SliceStable(containers, func(i, j int) bool {
+		return containers[i].Name < containers[j].Name
+	})
+
+	a := newLogAggregator(dockerCLI, opts, printer)
+	a.prefix = useFilters || len(opts.containers) > 1
+	for _, c := range containers {
+		a.add(ctx, c, opts.since, opts.tail)
+	}
+
+	matches := func(id string) (container.InspectResponse, bool) {
+		f := logFilters.Clone()
+		f.Add("id", id)
+		list, err := apiClient.ContainerList(ctx, client.ContainerListOptions{All: true, Filters: f})
+		if err != nil || len(list) == 0 {
+			return container.InspectResponse{}, false
+		}
+		ctr, err := apiClient.ContainerInspect(ctx, id)
+		return ctr, err == nil
+	}
+	return a.run(ctx, startEvents, eventErrs, matches)
+}
+
+// withProjectFilter replaces "project=<name>" filters with a filter on the
+// compose project label.
+func withProjectFilter(f filters.Args) filters.Args {
+	projects := f.Get("project")
+	if len(projects) == 0 {
+		return f
+	}
+	f = f.Clone()
+	for _, project := range projects {
+		f.Del("project", project)
+		f.Add("label", composeProjectLabel+"="+project)
+	}
+	return f
+}
+
+type logAggregator struct {
+	apiClient client.ContainerAPIClient
+	opts      *logsOptions
+	out       io.Writer
+	err       io.Writer
+	output    tui.Output
+	printer   *logstream.Printer
+	prefix    bool
+
+	sources  []*logSource
+	queues   [][]*logLine
+	open     []bool
+	numOpen  int
+	attached map[string]bool
+	width    int
+	events   chan logEvent
+}
+
+func newLogAggregator(dockerCLI command.Cli, opts *logsOptions, printer *logstream.Printer) *logAggregator {
+	return &logAggregator{
+		apiClient: dockerCLI.Client(),
+		opts:      opts,
+		printer:   printer,
+		out:       dockerCLI.Out(),
+		err:       dockerCLI.Err(),
+		output:    tui.NewOutput(dockerCLI.Out()),
+		attached:  make(map[string]bool),
+		events:    make(chan logEvent),
+	}
+}
+
+// add starts streaming the logs of the container.
+func (a *logAggregator) add(ctx context.Context, c container.InspectResponse, since, tail string) {
+	src := &logSource{
+		id:    c.ID,
+		name:  strings.TrimPrefix(c.Name, "/"),
+		color: logsPrefixColors[len(a.sources)%len(logsPrefixColors)],
+	}
+	if c.Config != nil {
+		src.tty = c.Config.Tty
+	}
+	idx := len(a.sources)
+	a.sources = append(a.sources, src)
+	a.queues = append(a.queues, nil)
+	a.open = append(a.open, true)
+	a.numOpen++
+	a.attached[src.id] = true
+	a.width = max(a.width, len(src.name))
+
+	go a.stream(ctx, idx, src, client.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Since:      since,
+		Until:      a.opts.until,
+		Timestamps: true,
+		Follow:     a.opts.follow,
+		Tail:       tail,
+		// Details are always requested if the logs are formatted or
+		// filtered, so that they can be included in the output.
+		Details: a.opts.details || a.printer != nil,
+	})
+}
+
+// stream streams the logs of a container, and sends a logEvent for each
+// line of output.
+func (a *logAggregator) stream(ctx context.Context, idx int, src *logSource, options client.ContainerLogsOptions) {
+	var err error
+	defer func() {
+		select {
+		case a.events <- logEvent{source: idx, done: true, err: err}:
+		case <-ctx.Done():
+		}
+	}()
+
+	body, err := a.apiClient.ContainerLogs(ctx, src.id, options)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	stdout := &logLineWriter{ctx: ctx, events: a.events, source: idx}
+	stderr := &logLineWriter{ctx: ctx, events: a.events, source: idx, stderr: true}
+	if src.tty {
+		_, err = io.Copy(stdout, body)
+	} else {
+		_, err = stdcopy.StdCopy(stdout, stderr, body)
+	}
+	if err == nil {
+		err = stdout.flush()
+	}
+	if err == nil {
+		err = stderr.flush()
+	}
+}
+
+// run prints the log lines as they are received until all streams ended,
+// or, when following the logs of containers matching a filter, until ctx
+// is cancelled. Containers that are started are added if they match.
+func (a *logAggregator) run(ctx context.Context, startEvents <-chan events.Message, eventErrs <-chan error, matches func(id string) (container.InspectResponse, bool)) error {
+	var errs []error
+	for {
+		wait, err := a.flush()
+		if err != nil {
+			return err
+		}
+		if a.numOpen == 0 && startEvents == nil {
+			return errors.Join(errs...)
+		}
+		var timeout <-chan time.Time
+		if wait > 0 {
+			timeout = time.After(wait)
+		}
+		select {
+		case ev := <-a.events:
+			if ev.line != nil {
+				a.queues[ev.source] = append(a.queues[ev.source], ev.line)
+			}
+			if ev.done {
+				src := a.sources[ev.source]
+				a.open[ev.source] = false
+				a.numOpen--
+				delete(a.attached, src.id)
+				if ev.err != nil && ctx.Err() == nil {
+					errs = append(errs, fmt.Errorf("%s: %w", src.name, ev.err))
+				}
+			}
+		case <-timeout:
+		case e := <-startEvents:
+			if a.attached[e.Actor.ID] {
+				continue
+			}
+			if c, ok := matches(e.Actor.ID); ok {
+				// Only show the logs since the container was started; the
+				// logs of a container that was restarted were already shown.
+				since := fmt.Sprintf("%d.%09d", e.TimeNano/int64(time.Second), e.TimeNano%int64(time.Second))
+				a.add(ctx, c, since, "all")
+			}
+		case err := <-eventErrs:
+			if ctx.Err() != nil {
+				return context.Cause(ctx)
+			}
+			return err
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		}
+	}
+}
+
+// flush prints the queued log lines in timestamp order. A line is printed
+// once a line is queued for each container that is still streaming, so
+// that no line with an earlier timestamp can arrive. When following, lines
+// are printed after being held back for logsFlushDelay. It returns how long
+// to wait until the next held back line must be printed, or 0 if there is
+// no line held back.
+func (a *logAggregator) flush() (time.Duration, error) {
+	for {
+		next := -1
+		complete := true
+		for i, q := range a.queues {
+			if len(q) == 0 {
+				if a.open[i] {
+					complete = false
+				}
+				continue
+			}
+			if next < 0 || q[0].timestamp.Before(a.queues[next][0].timestamp) {
+				next = i
+			}
+		}
+		if next < 0 {
+			return 0, nil
+		}
+		line := a.queues[next][0]
+		if !complete {
+			if !a.opts.follow {
+				return 0, nil
+			}
+			if wait := logsFlushDelay - time.Since(line.received); wait > 0 {
+				return wait, nil
+			}
+		}
+		a.queues[next] = a.queues[next][1:]
+		if err := a.print(line); err != nil {
+			return 0, err
+		}
+	}
+}
+
+func (a *logAggregator) print(line *logLine) error {
+	src := a.sources[line.source]
+	var prefix string
+	if a.prefix {
+		prefix = a.output.Color(src.color).Apply(fmt.Sprintf("%-*s |", a.width, src.name)) + " "
+	}
+	if a.printer != nil {
+		e := logstream.Entry{
+			Container: src.name,
+			Stream:    "stdout",
+			Timestamp: line.timestamp,
+			Message:   string(line.message),
+		}
+		if line.stderr {
+			e.Stream = "stderr"
+		}
+		// Details are sent as "key=value,..." before the message, and
+		// are empty if there are no details.
+		if attrs, msg, ok := strings.Cut(e.Message, " "); ok {
+			e.Message = msg
+			if d, err := logdetails.Parse(attrs); err == nil {
+				e.Details = d
+			}
+		}
+		return a.printer.Print(e, prefix)
+	}
+
+	var b bytes.Buffer
+	b.WriteString(prefix)
+	if a.opts.timestamps && len(line.rawTimestamp) > 0 {
+		b.Write(line.rawTimestamp)
+		b.WriteByte(' ')
+	}
+	b.Write(line.message)
+	w := a.out
+	if line.stderr {
+		w = a.err
+	}
+	_, _ = w.Write(b.Bytes())
+	return nil
+}
+
+// logLineWriter splits the log output of a container into lines, and sends
+// a logEvent for each line.
+type logLineWriter struct {
+	ctx    context.Context
+	events chan<- logEvent
+	source int
+	stderr bool
+	buf    []byte
+	last   time.Time
+}
+
+func (w *logLineWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := w.buf[:i+1]
+		w.buf = w.buf[i+1:]
+		if err := w.send(line); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// flush sends the remaining output, if any, as a line.
+func (w *logLineWriter) flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	line := append(w.buf, '\n')
+	w.buf = nil
+	return w.send(line)
+}
+
+func (w *logLineWriter) send(line []byte) error {
+	l := &logLine{
+		source:    w.source,
+		timestamp: w.last,
+		received:  time.Now(),
+		stderr:    w.stderr,
+		message:   bytes.Clone(line),
+	}
+	if ts, msg, ok := bytes.Cut(line, []byte{' '}); ok {
+		if t, err := time.Parse(time.RFC3339Nano, string(ts)); err == nil {
+			l.timestamp, l.rawTimestamp, l.message = t, bytes.Clone(ts), bytes.Clone(msg)
+			w.last = t
+		}
+	}
+	select {
+	case w.events <- logEvent{source: w.source, line: l}:
+		return nil
+	case <-w.ctx.Done():
+		return w.ctx.Err()
+	}
+}
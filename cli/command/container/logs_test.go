@@ -1,13 +1,21 @@
 package container
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"errors"
 	"io"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/internal/test"
+	"github.com/docker/cli/opts"
+	"github.com/moby/moby/api/pkg/stdcopy"
 	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/events"
 	"github.com/moby/moby/client"
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
@@ -58,3 +66,158 @@ func TestRunLogs(t *testing.T) {
 		})
 	}
 }
+
+// muxedLogs returns the log lines multiplexed in the format used by the
+// API for containers without a TTY. Lines prefixed with "err:" are written
+// to stderr.
+func muxedLogs(lines ...string) io.ReadCloser {
+	var buf bytes.Buffer
+	stdout := stdcopy.NewStdWriter(&buf, stdcopy.Stdout)
+	stderr := stdcopy.NewStdWriter(&buf, stdcopy.Stderr)
+	for _, line := range lines {
+		if l, ok := strings.CutPrefix(line, "err:"); ok {
+			_, _ = stderr.Write([]byte(l))
+		} else {
+			_, _ = stdout.Write([]byte(line))
+		}
+	}
+	return io.NopCloser(&buf)
+}
+
+func inspectByID(containerID string) (container.InspectResponse, error) {
+	return container.InspectResponse{
+		ID:     containerID,
+		Name:   "/" + containerID,
+		Config: &container.Config{},
+	}, nil
+}
+
+func TestRunLogsMultipleContainers(t *testing.T) {
+	logs := map[string][]string{
+		"web": {
+			"2024-01-01T00:00:01.000000000Z web 1\n",
+			"2024-01-01T00:00:03.000000000Z web 2\n",
+		},
+		"db": {
+			"2024-01-01T00:00:02.000000000Z db 1\n",
+			"err:2024-01-01T00:00:04.000000000Z db error\n",
+		},
+	}
+	apiClient := &fakeClient{
+		inspectFunc: inspectByID,
+		logFunc: func(containerID string, options client.ContainerLogsOptions) (io.ReadCloser, error) {
+			assert.Check(t, options.Timestamps)
+			return muxedLogs(logs[containerID]...), nil
+		},
+	}
+
+	cli := test.NewFakeCli(apiClient)
+	assert.NilError(t, runLogs(context.TODO(), cli, &logsOptions{containers: []string{"web", "db"}}))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "web | web 1\ndb  | db 1\nweb | web 2\n"))
+	assert.Check(t, is.Equal(cli.ErrBuffer().String(), "db  | db error\n"))
+
+	cli = test.NewFakeCli(apiClient)
+	assert.NilError(t, runLogs(context.TODO(), cli, &logsOptions{containers: []string{"web", "db"}, timestamps: true}))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), ""+
+		"web | 2024-01-01T00:00:01.000000000Z web 1\n"+
+		"db  | 2024-01-01T00:00:02.000000000Z db 1\n"+
+		"web | 2024-01-01T00:00:03.000000000Z web 2\n"))
+}
+
+func TestRunLogsFilter(t *testing.T) {
+	apiClient := &fakeClient{
+		inspectFunc: inspectByID,
+		containerListFunc: func(options client.ContainerListOptions) ([]container.Summary, error) {
+			assert.Check(t, options.All)
+			assert.Check(t, is.DeepEqual(options.Filters.Get("label"), []string{"com.docker.compose.project=myapp"}))
+			assert.Check(t, is.Len(options.Filters.Get("project"), 0))
+			return []container.Summary{{ID: "web"}}, nil
+		},
+		logFunc: func(string, client.ContainerLogsOptions) (io.ReadCloser, error) {
+			return muxedLogs("2024-01-01T00:00:01.000000000Z hello\n"), nil
+		},
+	}
+
+	cli := test.NewFakeCli(apiClient)
+	options := &logsOptions{filter: opts.NewFilterOpt()}
+	assert.NilError(t, options.filter.Set("project=myapp"))
+	assert.NilError(t, runLogs(context.TODO(), cli, options))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "web | hello\n"))
+}
+
+func TestRunLogsFollowStartedContainers(t *testing.T) {
+	messages := make(chan events.Message, 1)
+	messages <- events.Message{
+		Type:     events.ContainerEventType,
+		Action:   events.ActionStart,
+		Actor:    events.Actor{ID: "web"},
+		TimeNano: time.Date(2024, 1, 1, 0, 0, 0, 5, time.UTC).UnixNano(),
+	}
+	apiClient := &fakeClient{
+		inspectFunc: inspectByID,
+		eventsFunc: func(options client.EventsListOptions) (<-chan events.Message, <-chan error) {
+			assert.Check(t, is.DeepEqual(options.Filters.Get("event"), []string{"start"}))
+			return messages, make(chan error)
+		},
+		containerListFunc: func(options client.ContainerListOptions) ([]container.Summary, error) {
+			if ids := options.Filters.Get("id"); len(ids) > 0 {
+				return []container.Summary{{ID: ids[0]}}, nil
+			}
+			return nil, nil
+		},
+		logFunc: func(_ string, options client.ContainerLogsOptions) (io.ReadCloser, error) {
+			assert.Check(t, is.Equal(options.Since, "1704067200.000000005"))
+			return muxedLogs("2024-01-01T00:00:01.000000000Z started\n"), nil
+		},
+	}
+
+	r, w := io.Pipe()
+	cli := test.NewFakeCli(apiClient)
+	cli.SetOut(streams.NewOut(w))
+	options := &logsOptions{follow: true, filter: opts.NewFilterOpt()}
+	assert.NilError(t, options.filter.Set("label=com.example=foo"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		done <- runLogs(ctx, cli, options)
+	}()
+
+	line, err := bufio.NewReader(r).ReadString('\n')
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(line, "web | started\n"))
+
+	cancel()
+	assert.Check(t, errors.Is(<-done, context.Canceled))
+}
+
+func TestRunLogsFormat(t *testing.T) {
+	apiClient := &fakeClient{
+		inspectFunc: inspectByID,
+		logFunc: func(_ string, options client.ContainerLogsOptions) (io.ReadCloser, error) {
+			assert.Check(t, options.Timestamps)
+			assert.Check(t, options.Details)
+			return muxedLogs(
+				"2024-01-01T00:00:01.000000000Z  level=info msg=hello\n",
+				"err:2024-01-01T00:00:02.000000000Z env=production level=error msg=failed\n",
+			), nil
+		},
+	}
+
+	cli := test.NewFakeCli(apiClient)
+	assert.NilError(t, runLogs(context.TODO(), cli, &logsOptions{
+		containers: []string{"web"},
+		format:     "json",
+		level:      "error",
+	}))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), `{"container":"web","stream":"stderr","timestamp":"2024-01-01T00:00:02Z","level":"error","message":"level=error msg=failed","details":{"env":"production"}}`+"\n"))
+
+	cli = test.NewFakeCli(apiClient)
+	assert.NilError(t, runLogs(context.TODO(), cli, &logsOptions{
+		containers: []string{"web"},
+		grep:       "hello",
+	}))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "level=info msg=hello\n"))
+	assert.Check(t, is.Equal(cli.ErrBuffer().String(), ""))
+}
@@ -0,0 +1,101 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/internal/prompt"
+	"github.com/moby/moby/api/types/container"
+	"github.com/spf13/cobra"
+)
+
+// pickOptions are the options for selecting containers interactively when a
+// command is run without containers as arguments.
+type pickOptions struct {
+	// action is what is done with the selected containers, as shown in the
+	// prompt; for example, "stop".
+	action string
+
+	// multiple allows more than one container to be selected.
+	multiple bool
+
+	// confirm requests confirmation if more than one container is selected.
+	confirm bool
+
+	// all and filters select the containers that can be selected, in the
+	// same way as for [completion.ContainerNames].
+	all     bool
+	filters []func(container.Summary) bool
+}
+
+// canPickContainers returns whether containers can be selected
+// interactively, which requires stdin to be a terminal.
+func canPickContainers(dockerCLI command.Streams) bool {
+	return dockerCLI.In().IsTerminal()
+}
+
+// argsOrPicker returns a validator for the arguments of a command that
+// accepts no arguments if containers can be selected interactively, and
+// otherwise validates the arguments with validate.
+func argsOrPicker(dockerCLI command.Streams, validate cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && canPickContainers(dockerCLI) {
+			return nil
+		}
+		return validate(cmd, args)
+	}
+}
+
+// pickContainers requests the user to select containers from the containers
+// that are offered for completion, and returns their names. The prompt is
+// written to stderr, so that it is not mixed with the output of the command.
+func pickContainers(ctx context.Context, dockerCLI command.Cli, opts pickOptions) ([]string, error) {
+	ctrs, err := completion.Containers(ctx, dockerCLI, opts.all, opts.filters...)
+	if err != nil {
+		return nil, err
+	}
+	if len(ctrs) == 0 {
+		return nil, fmt.Errorf("no containers to %s", opts.action)
+	}
+
+	options := make([]prompt.Option, 0, len(ctrs))
+	for _, ctr := range ctrs {
+		name := formatter.TruncateID(ctr.ID)
+		if names := formatter.StripNamePrefix(ctr.Names); len(names) > 0 {
+			name = names[0]
+		}
+		options = append(options, prompt.Option{
+			Value:       name,
+			Description: ctr.Image + "\t" + ctr.Status,
+		})
+	}
+
+	message := "Select a container to " + opts.action
+	if opts.multiple {
+		message = "Select containers to " + opts.action
+	}
+	names, err := prompt.Select(ctx, dockerCLI.In(), dockerCLI.Err(), message, options, opts.multiple)
+	if err != nil {
+		if errors.Is(err, prompt.ErrNoSelection) {
+			return nil, cancelledErr{errors.New("no container selected")}
+		}
+		return nil, err
+	}
+
+	if opts.confirm && len(names) > 1 {
+		msg := fmt.Sprintf("Are you sure you want to %s %s?", opts.action, strings.Join(names, ", "))
+		ok, err := prompt.Confirm(ctx, dockerCLI.In(), dockerCLI.Err(), msg)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, cancelledErr{errors.New("container selection has been cancelled")}
+		}
+	}
+	return names, nil
+}
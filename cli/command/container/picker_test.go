@@ -0,0 +1,161 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/cli/internal/test"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// withTerminalInput sets the stdin of the CLI to a terminal, and writes each
+// line of input when the previous line is read.
+func withTerminalInput(t *testing.T, fakeCLI *test.FakeCli, lines ...string) {
+	t.Helper()
+	reader, writer := io.Pipe()
+	t.Cleanup(func() { _ = reader.Close() })
+	in := streams.NewIn(reader)
+	in.SetIsTerminal(true)
+	fakeCLI.SetIn(in)
+	go func() {
+		for _, l := range lines {
+			if _, err := writer.Write([]byte(l + "\n")); err != nil {
+				return
+			}
+		}
+	}()
+}
+
+func pickerTestClient() *fakeClient {
+	return &fakeClient{
+		containerListFunc: func(options client.ContainerListOptions) ([]container.Summary, error) {
+			ctrs := []container.Summary{
+				{ID: "aaaaaaaaaaaaaaaa", Names: []string{"/web"}, Image: "nginx:alpine", State: container.StateRunning, Status: "Up 2 hours"},
+				{ID: "bbbbbbbbbbbbbbbb", Names: []string{"/db"}, Image: "postgres:16", State: container.StateRunning, Status: "Up 2 hours"},
+			}
+			if options.All {
+				ctrs = append(ctrs, container.Summary{ID: "cccccccccccccccc", Names: []string{"/old"}, Image: "busybox", State: container.StateExited, Status: "Exited (0) 3 days ago"})
+			}
+			return ctrs, nil
+		},
+	}
+}
+
+func TestStopPicker(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		stopped []string
+	)
+	apiClient := pickerTestClient()
+	apiClient.containerStopFunc = func(_ context.Context, ctr string, _ client.ContainerStopOptions) error {
+		mu.Lock()
+		defer mu.Unlock()
+		stopped = append(stopped, ctr)
+		return nil
+	}
+
+	t.Run("not a terminal", func(t *testing.T) {
+		cmd := newStopCommand(test.NewFakeCli(apiClient))
+		cmd.SetArgs([]string{})
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		assert.Check(t, is.ErrorContains(cmd.Execute(), "requires at least 1 argument"))
+	})
+
+	t.Run("confirmed", func(t *testing.T) {
+		fakeCLI := test.NewFakeCli(apiClient)
+		withTerminalInput(t, fakeCLI, "*", "y")
+		cmd := newStopCommand(fakeCLI)
+		cmd.SetArgs([]string{})
+		assert.NilError(t, cmd.Execute())
+		sort.Strings(stopped)
+		assert.Check(t, is.DeepEqual(stopped, []string{"db", "web"}))
+		assert.Check(t, is.Contains(fakeCLI.ErrBuffer().String(), "Select containers to stop:\n"))
+		assert.Check(t, is.Contains(fakeCLI.ErrBuffer().String(), "Are you sure you want to stop web, db? [y/N] "))
+		assert.Check(t, is.Equal(fakeCLI.OutBuffer().String(), "web\ndb\n"))
+	})
+
+	t.Run("not confirmed", func(t *testing.T) {
+		stopped = nil
+		fakeCLI := test.NewFakeCli(apiClient)
+		withTerminalInput(t, fakeCLI, "1-2", "n")
+		cmd := newStopCommand(fakeCLI)
+		cmd.SetArgs([]string{})
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		assert.Check(t, is.Error(cmd.Execute(), "container selection has been cancelled"))
+		assert.Check(t, is.Len(stopped, 0))
+	})
+
+	t.Run("nothing selected", func(t *testing.T) {
+		fakeCLI := test.NewFakeCli(apiClient)
+		withTerminalInput(t, fakeCLI, "")
+		cmd := newStopCommand(fakeCLI)
+		cmd.SetArgs([]string{})
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		assert.Check(t, is.Error(cmd.Execute(), "no container selected"))
+	})
+}
+
+func TestPickExecArgs(t *testing.T) {
+	fakeCLI := test.NewFakeCli(pickerTestClient())
+	withTerminalInput(t, fakeCLI, "db", "psql -U 'app user'")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	args, shell, err := pickExecArgs(ctx, fakeCLI, func(container.Summary) bool { return true })
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(args, []string{"db", "psql", "-U", "app user"}))
+	assert.Check(t, !shell)
+
+	expected := `Select a container to run a command in:
+   1  web  nginx:alpine  Up 2 hours
+   2  db   postgres:16   Up 2 hours
+Enter a number, or text to search: Command to run [sh]: `
+	assert.Check(t, is.Equal(fakeCLI.ErrBuffer().String(), expected))
+
+	t.Run("default shell", func(t *testing.T) {
+		fakeCLI := test.NewFakeCli(pickerTestClient())
+		withTerminalInput(t, fakeCLI, "1", "")
+		args, shell, err := pickExecArgs(ctx, fakeCLI, func(container.Summary) bool { return true })
+		assert.NilError(t, err)
+		assert.Check(t, is.DeepEqual(args, []string{"web", "sh"}))
+		assert.Check(t, shell)
+	})
+}
+
+func TestExecPickerDefaultShell(t *testing.T) {
+	var execOptions []container.ExecOptions
+	apiClient := pickerTestClient()
+	apiClient.execCreateFunc = func(_ string, options container.ExecOptions) (container.ExecCreateResponse, error) {
+		execOptions = append(execOptions, options)
+		return container.ExecCreateResponse{}, errors.New("exec create failed")
+	}
+
+	for _, args := range [][]string{{}, {"--interactive=false"}} {
+		fakeCLI := test.NewFakeCli(apiClient)
+		withTerminalInput(t, fakeCLI, "1", "")
+		cmd := newExecCommand(fakeCLI)
+		cmd.SetArgs(args)
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		assert.Check(t, is.Error(cmd.Execute(), "exec create failed"))
+	}
+	assert.Assert(t, is.Len(execOptions, 2))
+	assert.Check(t, is.DeepEqual(execOptions[0].Cmd, []string{"sh"}))
+	assert.Check(t, execOptions[0].AttachStdin)
+	// stdout is not a terminal.
+	assert.Check(t, !execOptions[0].Tty)
+	// options that are set explicitly are not changed.
+	assert.Check(t, !execOptions[1].AttachStdin)
+}
@@ -0,0 +1,356 @@
+package container
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/internal/lazyregexp"
+	"github.com/moby/sys/atomicwriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// runProfilesDir is the directory, relative to the CLI's configuration
+	// directory, in which run profiles are stored.
+	runProfilesDir = "run-profiles"
+
+	// runProfileExt is the file extension of run profiles.
+	runProfileExt = ".yaml"
+)
+
+var (
+	// runProfileNameRe matches valid run profile names.
+	runProfileNameRe = lazyregexp.New(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+	// secretEnvRe matches the names of environment variables that are
+	// likely to hold credentials, such as "DB_PASSWORD" or "GITHUB_TOKEN".
+	secretEnvRe = lazyregexp.New(`(?i)passw(or)?d|passphrase|secret|token|credential|api_?key|access_?key|private_?key`)
+)
+
+// profileFlagsExcluded are flags of "docker run" that are not saved in a run
+// profile.
+var profileFlagsExcluded = map[string]struct{}{
+	"help":         {},
+	"profile":      {},
+	"save-profile": {},
+}
+
+// newProfileCommand returns a cobra command for `container profile` subcommands
+func newProfileCommand(dockerCLI command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Manage run profiles",
+		Long: `Manage run profiles
+
+Run profiles store the options of a "docker run" command, which are saved with
+"docker run --save-profile NAME", and used with "docker run --profile NAME".`,
+		Args: cli.NoArgs,
+		RunE: command.ShowHelp(dockerCLI.Err()),
+
+		DisableFlagsInUseLine: true,
+	}
+	cmd.AddCommand(
+		newProfileListCommand(dockerCLI),
+		newProfileInspectCommand(dockerCLI),
+		newProfileRemoveCommand(dockerCLI),
+	)
+	return cmd
+}
+
+// runProfile is a saved set of options for "docker run".
+type runProfile struct {
+	// Name is the name of the profile, which is the name of the file in
+	// which it is stored, without extension.
+	Name string `yaml:"-"`
+
+	// Image is the image to run, unless an image is passed on the command
+	// line.
+	Image string `yaml:"image"`
+
+	// Command is the command to run, unless an image is passed on the
+	// command line.
+	Command []string `yaml:"command,omitempty"`
+
+	// Flags are the values of the flags, keyed by their long name, as they
+	// were passed on the command line.
+	Flags map[string]profileValues `yaml:"flags,omitempty"`
+}
+
+// profileValues are the values passed to a flag. They are stored as a
+// scalar if the flag was passed once, and as a list otherwise.
+type profileValues []string
+
+func (v profileValues) MarshalYAML() (any, error) {
+	if len(v) == 1 {
+		return v[0], nil
+	}
+	return []string(v), nil
+}
+
+func (v *profileValues) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*v = profileValues{node.Value}
+		return nil
+	}
+	var values []string
+	if err := node.Decode(&values); err != nil {
+		return err
+	}
+	*v = values
+	return nil
+}
+
+// apply sets the flags of the profile that are not set on the command line.
+// Flags that are set on the command line replace the value of the profile.
+func (p *runProfile) apply(flags *pflag.FlagSet) error {
+	names := make([]string, 0, len(p.Flags))
+	for name := range p.Flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f := flags.Lookup(name)
+		if _, excluded := profileFlagsExcluded[name]; excluded || f == nil {
+			return fmt.Errorf("invalid run profile %q: unknown flag: --%s", p.Name, name)
+		}
+		if f.Changed {
+			continue
+		}
+		for _, value := range p.Flags[name] {
+			if err := flags.Set(name, value); err != nil {
+				return fmt.Errorf("invalid run profile %q: invalid argument %q for --%s: %w", p.Name, value, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// newRunProfile returns a profile for the flags that are set, and the image
+// and command to run. Relative paths of files and bind mounts are saved as
+// absolute paths, so that the profile can be used in other directories.
+func newRunProfile(name string, flags *pflag.FlagSet, recorder *flagRecorder, copts *containerOptions) *runProfile {
+	p := &runProfile{
+		Name:    name,
+		Image:   copts.Image,
+		Command: copts.Args,
+		Flags:   make(map[string]profileValues),
+	}
+	flags.Visit(func(f *pflag.Flag) {
+		if _, excluded := profileFlagsExcluded[f.Name]; excluded {
+			return
+		}
+		values := recorder.values[f.Name]
+		if len(values) == 0 {
+			return
+		}
+		resolved := make(profileValues, 0, len(values))
+		for _, v := range values {
+			resolved = append(resolved, resolveProfilePath(f.Name, v))
+		}
+		p.Flags[f.Name] = resolved
+	})
+	return p
+}
+
+// resolveProfilePath returns the value of a flag with relative paths made
+// absolute. Bind mount sources are only relative paths if they start with
+// ".", in the same way as when running a container.
+func resolveProfilePath(flag, value string) string {
+	abs := func(p string) string {
+		if filepath.IsAbs(p) {
+			return p
+		}
+		if a, err := filepath.Abs(p); err == nil {
+			return a
+		}
+		return p
+	}
+	switch flag {
+	case "env-file", "label-file", "cidfile":
+		return abs(value)
+	case "volume":
+		if src, rest, ok := strings.Cut(value, ":"); ok && strings.HasPrefix(src, ".") {
+			return abs(src) + ":" + rest
+		}
+	case "mount":
+		fields, err := csv.NewReader(strings.NewReader(value)).Read()
+		if err != nil {
+			return value
+		}
+		for i, field := range fields {
+			key, val, ok := strings.Cut(field, "=")
+			if ok && (key == "source" || key == "src") && strings.HasPrefix(val, ".") {
+				fields[i] = key + "=" + abs(val)
+			}
+		}
+		var buf strings.Builder
+		w := csv.NewWriter(&buf)
+		if err := w.Write(fields); err != nil {
+			return value
+		}
+		w.Flush()
+		return strings.TrimSuffix(buf.String(), "\n")
+	}
+	return value
+}
+
+// secretWarnings returns warnings for the environment variables in the
+// profile that look like they hold credentials, which are stored in plain
+// text.
+func (p *runProfile) secretWarnings() []string {
+	var warnings []string
+	for _, env := range p.Flags["env"] {
+		if name, _, ok := strings.Cut(env, "="); ok && secretEnvRe.MatchString(name) {
+			warnings = append(warnings, fmt.Sprintf("run profile %q stores the value of %s in plain text; use \"--env %s\" to pass the value from the environment instead", p.Name, name, name))
+		}
+	}
+	return warnings
+}
+
+// flagRecorder records the values that are passed to flags, so that they can
+// be saved in a run profile. Not all flag types can format their value in the
+// format in which it is passed, so values are recorded as they are set.
+type flagRecorder struct {
+	values map[string]profileValues
+}
+
+// recordFlags starts recording the values passed to the flags.
+func recordFlags(flags *pflag.FlagSet) *flagRecorder {
+	r := &flagRecorder{values: make(map[string]profileValues)}
+	flags.VisitAll(func(f *pflag.Flag) {
+		v := &recordedValue{Value: f.Value, name: f.Name, recorder: r}
+		if sv, ok := f.Value.(sliceValue); ok {
+			// Preserve the interface that is used to complete flags that
+			// can be passed multiple times.
+			f.Value = &recordedSliceValue{recordedValue: v, slice: sv}
+			return
+		}
+		f.Value = v
+	})
+	return r
+}
+
+type sliceValue interface {
+	GetSlice() []string
+}
+
+// recordedValue is a flag value that records the values that are set.
+type recordedValue struct {
+	pflag.Value
+	name     string
+	recorder *flagRecorder
+}
+
+func (v *recordedValue) Set(s string) error {
+	if err := v.Value.Set(s); err != nil {
+		return err
+	}
+	v.recorder.values[v.name] = append(v.recorder.values[v.name], s)
+	return nil
+}
+
+type recordedSliceValue struct {
+	*recordedValue
+	slice sliceValue
+}
+
+func (v *recordedSliceValue) GetSlice() []string {
+	return v.slice.GetSlice()
+}
+
+// runProfilePath returns the path of the file in which a profile is stored.
+func runProfilePath(name string) (string, error) {
+	if !runProfileNameRe.MatchString(name) {
+		return "", fmt.Errorf("invalid run profile name %q: only [a-zA-Z0-9][a-zA-Z0-9_.-] are allowed", name)
+	}
+	return config.Path(runProfilesDir, name+runProfileExt)
+}
+
+// loadRunProfile loads a run profile by name.
+func loadRunProfile(name string) (*runProfile, error) {
+	p, err := runProfilePath(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("run profile %q does not exist", name)
+		}
+		return nil, err
+	}
+	profile := &runProfile{Name: name}
+	if err := yaml.Unmarshal(data, profile); err != nil {
+		return nil, fmt.Errorf("invalid run profile %q: %w", name, err)
+	}
+	return profile, nil
+}
+
+// saveRunProfile saves a run profile, replacing the profile with the same
+// name, if any.
+func saveRunProfile(profile *runProfile) error {
+	p, err := runProfilePath(profile.Name)
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(profile)
+	if err != nil {
+		return err
+	}
+	// Profiles may contain credentials, such as the values of environment
+	// variables, so they are only readable by the user.
+	if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+		return err
+	}
+	return atomicwriter.WriteFile(p, data, 0o600)
+}
+
+// removeRunProfile removes a run profile by name.
+func removeRunProfile(name string) error {
+	p, err := runProfilePath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("run profile %q does not exist", name)
+		}
+		return err
+	}
+	return nil
+}
+
+// runProfileNames returns the names of the run profiles, in lexical order.
+func runProfileNames() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(config.Dir(), runProfilesDir))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), runProfileExt)
+		if ok && !e.IsDir() && runProfileNameRe.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// completeRunProfileNames offers completion for run profile names.
+func completeRunProfileNames(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	names, _ := runProfileNames()
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,41 @@
+package container
+
+import (
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/inspect"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+type profileInspectOptions struct {
+	format string
+	names  []string
+}
+
+func newProfileInspectCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts profileInspectOptions
+
+	cmd := &cobra.Command{
+		Use:   "inspect [OPTIONS] PROFILE [PROFILE...]",
+		Short: "Display detailed information on one or more run profiles",
+		Args:  cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.names = args
+			return runProfileInspect(dockerCLI, opts)
+		},
+		ValidArgsFunction:     completeRunProfileNames,
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.format, "format", "f", "", flagsHelper.InspectFormatHelp)
+	return cmd
+}
+
+func runProfileInspect(dockerCLI command.Cli, opts profileInspectOptions) error {
+	return inspect.Inspect(dockerCLI.Out(), opts.names, opts.format, func(name string) (any, []byte, error) {
+		p, err := loadRunProfile(name)
+		return p, nil, err
+	})
+}
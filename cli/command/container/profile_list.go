@@ -0,0 +1,125 @@
+package container
+
+import (
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultProfileTableFormat = "table {{.Name}}\t{{.Image}}\t{{.Command}}"
+	profileNameHeader         = "NAME"
+	profileImageHeader        = "IMAGE"
+	profileCommandHeader      = "COMMAND"
+	quietProfileFormat        = "{{.Name}}"
+)
+
+type profileListOptions struct {
+	quiet  bool
+	format string
+}
+
+func newProfileListCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts profileListOptions
+
+	cmd := &cobra.Command{
+		Use:     "ls [OPTIONS]",
+		Aliases: []string{"list"},
+		Short:   "List run profiles",
+		Args:    cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProfileList(dockerCLI, opts)
+		},
+		ValidArgsFunction:     cobra.NoFileCompletions,
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Only display profile names")
+	flags.StringVar(&opts.format, "format", "", flagsHelper.FormatHelp)
+	return cmd
+}
+
+func runProfileList(dockerCLI command.Cli, opts profileListOptions) error {
+	names, err := runProfileNames()
+	if err != nil {
+		return err
+	}
+	profiles := make([]*runProfile, 0, len(names))
+	for _, name := range names {
+		p, err := loadRunProfile(name)
+		if err != nil {
+			return err
+		}
+		profiles = append(profiles, p)
+	}
+
+	format := opts.format
+	if format == "" {
+		format = formatter.TableFormatKey
+	}
+	profileCtx := formatter.Context{
+		Output: dockerCLI.Out(),
+		Format: newProfileFormat(format, opts.quiet),
+	}
+	return profileFormatWrite(profileCtx, profiles)
+}
+
+// newProfileFormat returns a format for use with a profileContext.
+func newProfileFormat(source string, quiet bool) formatter.Format {
+	switch {
+	case quiet:
+		return quietProfileFormat
+	case source == formatter.TableFormatKey:
+		return defaultProfileTableFormat
+	default:
+		return formatter.Format(source)
+	}
+}
+
+// profileFormatWrite writes formatted run profiles using the Context.
+func profileFormatWrite(fmtCtx formatter.Context, profiles []*runProfile) error {
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for _, p := range profiles {
+			if err := format(&profileContext{p: p}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	profileCtx := &profileContext{
+		HeaderContext: formatter.HeaderContext{
+			Header: formatter.SubHeaderContext{
+				"Name":    profileNameHeader,
+				"Image":   profileImageHeader,
+				"Command": profileCommandHeader,
+			},
+		},
+	}
+	return fmtCtx.Write(profileCtx, render)
+}
+
+type profileContext struct {
+	formatter.HeaderContext
+	p *runProfile
+}
+
+func (c *profileContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *profileContext) Name() string {
+	return c.p.Name
+}
+
+func (c *profileContext) Image() string {
+	return c.p.Image
+}
+
+func (c *profileContext) Command() string {
+	return strings.Join(c.p.Command, " ")
+}
@@ -0,0 +1,37 @@
+package container
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+)
+
+func newProfileRemoveCommand(dockerCLI command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "rm PROFILE [PROFILE...]",
+		Aliases: []string{"remove"},
+		Short:   "Remove one or more run profiles",
+		Args:    cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProfileRemove(dockerCLI, args)
+		},
+		ValidArgsFunction:     completeRunProfileNames,
+		DisableFlagsInUseLine: true,
+	}
+	return cmd
+}
+
+func runProfileRemove(dockerCLI command.Cli, names []string) error {
+	var errs []error
+	for _, name := range names {
+		if err := removeRunProfile(name); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		_, _ = fmt.Fprintln(dockerCLI.Out(), name)
+	}
+	return errors.Join(errs...)
+}
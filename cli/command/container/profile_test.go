@@ -0,0 +1,178 @@
+package container
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"testing"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/internal/test"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/network"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func withRunProfilesDir(t *testing.T) string {
+	t.Helper()
+	orig := config.Dir()
+	dir := t.TempDir()
+	config.SetDir(dir)
+	t.Cleanup(func() { config.SetDir(orig) })
+	return filepath.Join(dir, runProfilesDir)
+}
+
+func TestRunSaveAndUseProfile(t *testing.T) {
+	profilesDir := withRunProfilesDir(t)
+
+	var (
+		created    *container.Config
+		hostConfig *container.HostConfig
+	)
+	fakeCLI := test.NewFakeCli(&fakeClient{
+		createContainerFunc: func(config *container.Config, hc *container.HostConfig, _ *network.NetworkingConfig, _ *ocispec.Platform, _ string) (container.CreateResponse, error) {
+			created, hostConfig = config, hc
+			return container.CreateResponse{ID: "id"}, nil
+		},
+		Version: "1.36",
+	})
+	run := func(args ...string) error {
+		cmd := newRunCommand(fakeCLI)
+		cmd.SetArgs(args)
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		return cmd.Execute()
+	}
+
+	err := run("--save-profile", "dev", "-d", "-e", "FOO=bar", "-e", "BAZ=qux", "--memory", "1000000", "-w", "/src", "busybox", "sh", "-c", "echo hi")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(fakeCLI.ErrBuffer().String(), "Saved run profile \"dev\"\n"))
+
+	saved, err := os.ReadFile(filepath.Join(profilesDir, "dev.yaml"))
+	assert.NilError(t, err)
+	expected := `image: busybox
+command:
+    - sh
+    - -c
+    - echo hi
+flags:
+    detach: "true"
+    env:
+        - FOO=bar
+        - BAZ=qux
+    memory: "1000000"
+    workdir: /src
+`
+	assert.Check(t, is.Equal(string(saved), expected))
+	if runtime.GOOS != "windows" {
+		fi, err := os.Stat(filepath.Join(profilesDir, "dev.yaml"))
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(fi.Mode().Perm(), os.FileMode(0o600)))
+	}
+
+	t.Run("replay", func(t *testing.T) {
+		assert.NilError(t, run("--profile", "dev"))
+		assert.Check(t, is.Equal(created.Image, "busybox"))
+		assert.Check(t, is.DeepEqual([]string(created.Cmd), []string{"sh", "-c", "echo hi"}))
+		// The order of environment variables is not preserved by "docker run".
+		env := append([]string{}, created.Env...)
+		sort.Strings(env)
+		assert.Check(t, is.DeepEqual(env, []string{"BAZ=qux", "FOO=bar"}))
+		assert.Check(t, is.Equal(created.WorkingDir, "/src"))
+		assert.Check(t, is.Equal(hostConfig.Memory, int64(1000000)))
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		assert.NilError(t, run("--profile", "dev", "-e", "ONLY=1", "alpine"))
+		assert.Check(t, is.Equal(created.Image, "alpine"))
+		assert.Check(t, is.Len(created.Cmd, 0))
+		assert.Check(t, is.DeepEqual(created.Env, []string{"ONLY=1"}))
+		assert.Check(t, is.Equal(created.WorkingDir, "/src"))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		assert.Check(t, is.Error(run("--profile", "nosuchprofile"), `run profile "nosuchprofile" does not exist`))
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		assert.Check(t, is.ErrorContains(run("--profile", "../dev"), `invalid run profile name "../dev"`))
+	})
+
+	t.Run("unknown flag", func(t *testing.T) {
+		assert.NilError(t, os.WriteFile(filepath.Join(profilesDir, "bad.yaml"), []byte("image: busybox\nflags:\n  no-such-flag: true\n"), 0o644))
+		assert.Check(t, is.Error(run("--profile", "bad"), `invalid run profile "bad": unknown flag: --no-such-flag`))
+	})
+}
+
+func TestRunSaveProfileResolvesPaths(t *testing.T) {
+	withRunProfilesDir(t)
+	wd, err := os.Getwd()
+	assert.NilError(t, err)
+
+	fakeCLI := test.NewFakeCli(&fakeClient{
+		createContainerFunc: func(*container.Config, *container.HostConfig, *network.NetworkingConfig, *ocispec.Platform, string) (container.CreateResponse, error) {
+			return container.CreateResponse{ID: "id"}, nil
+		},
+		Version: "1.36",
+	})
+	cmd := newRunCommand(fakeCLI)
+	cmd.SetArgs([]string{
+		"--save-profile", "paths", "-d",
+		"-v", "./src:/src:ro", "-v", "data:/data",
+		"--mount", "type=bind,src=.,dst=/app",
+		"-e", "DB_PASSWORD=hunter2", "-e", "API_TOKEN", "-e", "AUTHOR=jane",
+		"busybox",
+	})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(fakeCLI.ErrBuffer().String(), "Saved run profile \"paths\"\n"+
+		"WARNING: run profile \"paths\" stores the value of DB_PASSWORD in plain text; use \"--env DB_PASSWORD\" to pass the value from the environment instead\n"))
+
+	p, err := loadRunProfile("paths")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(p.Flags["volume"], profileValues{filepath.Join(wd, "src") + ":/src:ro", "data:/data"}))
+	assert.Check(t, is.DeepEqual(p.Flags["mount"], profileValues{"type=bind,src=" + wd + ",dst=/app"}))
+	assert.Check(t, is.DeepEqual(p.Flags["env"], profileValues{"DB_PASSWORD=hunter2", "API_TOKEN", "AUTHOR=jane"}))
+	assert.Check(t, is.Equal(resolveProfilePath("env-file", "dev.env"), filepath.Join(wd, "dev.env")))
+}
+
+func TestRunProfileCommands(t *testing.T) {
+	profilesDir := withRunProfilesDir(t)
+	assert.NilError(t, saveRunProfile(&runProfile{
+		Name:    "web",
+		Image:   "nginx",
+		Flags:   map[string]profileValues{"publish": {"8080:80"}},
+		Command: []string{"nginx", "-g", "daemon off;"},
+	}))
+	assert.NilError(t, saveRunProfile(&runProfile{Name: "db", Image: "postgres"}))
+	assert.NilError(t, os.WriteFile(filepath.Join(profilesDir, "README.md"), []byte("not a profile"), 0o644))
+
+	fakeCLI := test.NewFakeCli(&fakeClient{})
+	cmd := newProfileCommand(fakeCLI)
+	cmd.SilenceUsage = true
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"ls"})
+	assert.NilError(t, cmd.Execute())
+	expected := "NAME      IMAGE      COMMAND\n" +
+		"db        postgres   \n" +
+		"web       nginx      nginx -g daemon off;\n"
+	assert.Check(t, is.Equal(fakeCLI.OutBuffer().String(), expected))
+
+	fakeCLI.ResetOutputBuffers()
+	cmd.SetArgs([]string{"inspect", "--format", "{{.Image}} {{json .Flags}}", "web"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(fakeCLI.OutBuffer().String(), "nginx {\"publish\":[\"8080:80\"]}\n"))
+
+	fakeCLI.ResetOutputBuffers()
+	cmd.SetArgs([]string{"rm", "db", "nosuchprofile"})
+	assert.Check(t, is.Error(cmd.Execute(), `run profile "nosuchprofile" does not exist`))
+	assert.Check(t, is.Equal(fakeCLI.OutBuffer().String(), "db\n"))
+	names, err := runProfileNames()
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(names, []string{"web"}))
+}
@@ -22,8 +22,9 @@ func init() {
 }
 
 type pruneOptions struct {
-	force  bool
-	filter opts.FilterOpt
+	force     bool
+	filter    opts.FilterOpt
+	onDeleted func(id string)
 }
 
 // newPruneCommand returns a new cobra prune command for containers.
@@ -82,6 +83,9 @@ func runPrune(ctx context.Context, dockerCli command.Cli, options pruneOptions)
 		output = "Deleted Containers:\n"
 		for _, id := range report.ContainersDeleted {
 			output += id + "\n"
+			if options.onDeleted != nil {
+				options.onDeleted(id)
+			}
 		}
 		spaceReclaimed = report.SpaceReclaimed
 	}
@@ -102,7 +106,8 @@ func pruneFn(ctx context.Context, dockerCLI command.Cli, options pruner.PruneOpt
 		return 0, confirmMsg, cancelledErr{errors.New("containers prune has been cancelled")}
 	}
 	return runPrune(ctx, dockerCLI, pruneOptions{
-		force:  true,
-		filter: options.Filter,
+		force:     true,
+		filter:    options.Filter,
+		onDeleted: options.OnDeleted,
 	})
 }
@@ -27,11 +27,17 @@ func newRestartCommand(dockerCLI command.Cli) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "restart [OPTIONS] CONTAINER [CONTAINER...]",
 		Short: "Restart one or more containers",
-		Args:  cli.RequiresMinArgs(1),
+		Args:  argsOrPicker(dockerCLI, cli.RequiresMinArgs(1)),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if cmd.Flags().Changed("time") && cmd.Flags().Changed("timeout") {
 				return errors.New("conflicting options: cannot specify both --timeout and --time")
 			}
+			if len(args) == 0 {
+				var err error
+				if args, err = pickContainers(cmd.Context(), dockerCLI, pickOptions{action: "restart", multiple: true, confirm: true, all: true}); err != nil {
+					return err
+				}
+			}
 			opts.containers = args
 			opts.timeoutChanged = cmd.Flags().Changed("timeout") || cmd.Flags().Changed("time")
 			return runRestart(cmd.Context(), dockerCLI, &opts)
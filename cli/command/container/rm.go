@@ -27,20 +27,31 @@ type rmOptions struct {
 func newRmCommand(dockerCLI command.Cli) *cobra.Command {
 	var opts rmOptions
 
+	removable := func(ctr container.Summary) bool {
+		return opts.force || ctr.State == container.StateExited || ctr.State == container.StateCreated
+	}
+
 	cmd := &cobra.Command{
 		Use:   "rm [OPTIONS] CONTAINER [CONTAINER...]",
 		Short: "Remove one or more containers",
-		Args:  cli.RequiresMinArgs(1),
+		Args:  argsOrPicker(dockerCLI, cli.RequiresMinArgs(1)),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				var err error
+				if args, err = pickContainers(cmd.Context(), dockerCLI, pickOptions{
+					action: "remove", multiple: true, confirm: true,
+					all: true, filters: []func(container.Summary) bool{removable},
+				}); err != nil {
+					return err
+				}
+			}
 			opts.containers = args
 			return runRm(cmd.Context(), dockerCLI, &opts)
 		},
 		Annotations: map[string]string{
 			"aliases": "docker container rm, docker container remove, docker rm",
 		},
-		ValidArgsFunction: completion.ContainerNames(dockerCLI, true, func(ctr container.Summary) bool {
-			return opts.force || ctr.State == container.StateExited || ctr.State == container.StateCreated
-		}),
+		ValidArgsFunction:     completion.ContainerNames(dockerCLI, true, removable),
 		DisableFlagsInUseLine: true,
 	}
 
@@ -23,9 +23,15 @@
 
 type runOptions struct {
 	createOptions
-	detach     bool
-	sigProxy   bool
-	detachKeys string
+	detach      bool
+	sigProxy    bool
+	detachKeys  string
+	profile     string
+	saveProfile string
+
+	// recorder records the values passed to flags, for saving them as a
+	// run profile.
+	recorder *flagRecorder
 }
 
 // newRunCommand create a new "docker run" command.
@@ -36,8 +42,29 @@ func newRunCommand(dockerCLI command.Cli) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "run [OPTIONS] IMAGE [COMMAND] [ARG...]",
 		Short: "Create and run a new container from an image",
-		Args:  cli.RequiresMinArgs(1),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if options.profile != "" {
+				// The image and command are optional when using a profile.
+				return nil
+			}
+			return cli.RequiresMinArgs(1)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if options.profile != "" {
+				profile, err := loadRunProfile(options.profile)
+				if err != nil {
+					return err
+				}
+				if err := profile.apply(cmd.Flags()); err != nil {
+					return err
+				}
+				if len(args) == 0 {
+					if profile.Image == "" {
+						return fmt.Errorf("run profile %q does not specify an image", options.profile)
+					}
+					args = append([]string{profile.Image}, profile.Command...)
+				}
+			}
 			copts.Image = args[0]
 			if len(args) > 1 {
 				copts.Args = args[1:]
@@ -63,6 +90,8 @@ func newRunCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.StringVar(&options.pull, "pull", PullImageMissing, `Pull image before running ("`+PullImageAlways+`", "`+PullImageMissing+`", "`+PullImageNever+`")`)
 	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Suppress the pull output")
 	flags.BoolVarP(&options.createOptions.useAPISocket, "use-api-socket", "", false, "Bind mount Docker API socket and required auth")
+	flags.StringVar(&options.profile, "profile", "", "Use the options of a saved run profile; options passed on the command line take precedence")
+	flags.StringVar(&options.saveProfile, "save-profile", "", "Save the options as a run profile with the given name")
 
 	// Add an explicit help that doesn't have a `-h` to prevent the conflict
 	// with hostname
@@ -72,8 +101,11 @@ func newRunCommand(dockerCLI command.Cli) *cobra.Command {
 	addPlatformFlag(flags, &options.platform)
 	flags.BoolVar(&options.untrusted, "disable-content-trust", !dockerCLI.ContentTrustEnabled(), "Skip image verification")
 	copts = addFlags(flags)
+	options.recorder = recordFlags(flags)
 
 	_ = cmd.RegisterFlagCompletionFunc("detach-keys", completeDetachKeys)
+	_ = cmd.RegisterFlagCompletionFunc("profile", completeRunProfileNames)
+	_ = cmd.RegisterFlagCompletionFunc("save-profile", completeRunProfileNames)
 	addCompletions(cmd, dockerCLI)
 
 	return cmd
@@ -104,6 +136,16 @@ func runRun(ctx context.Context, dockerCli command.Cli, flags *pflag.FlagSet, ro
 			StatusCode: 125,
 		}
 	}
+	if ropts.saveProfile != "" {
+		profile := newRunProfile(ropts.saveProfile, flags, ropts.recorder, copts)
+		if err := saveRunProfile(profile); err != nil {
+			return fmt.Errorf("failed to save run profile: %w", err)
+		}
+		_, _ = fmt.Fprintf(dockerCli.Err(), "Saved run profile %q\n", ropts.saveProfile)
+		for _, w := range profile.secretWarnings() {
+			_, _ = fmt.Fprintln(dockerCli.Err(), "WARNING:", w)
+		}
+	}
 	return runContainer(ctx, dockerCli, ropts, copts, containerCfg)
 }
 
@@ -32,20 +32,31 @@ type StartOptions struct {
 func newStartCommand(dockerCLI command.Cli) *cobra.Command {
 	var opts StartOptions
 
+	stopped := func(ctr container.Summary) bool {
+		return ctr.State == container.StateExited || ctr.State == container.StateCreated
+	}
+
 	cmd := &cobra.Command{
 		Use:   "start [OPTIONS] CONTAINER [CONTAINER...]",
 		Short: "Start one or more stopped containers",
-		Args:  cli.RequiresMinArgs(1),
+		Args:  argsOrPicker(dockerCLI, cli.RequiresMinArgs(1)),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				var err error
+				if args, err = pickContainers(cmd.Context(), dockerCLI, pickOptions{
+					action: "start", multiple: true,
+					all: true, filters: []func(container.Summary) bool{stopped},
+				}); err != nil {
+					return err
+				}
+			}
 			opts.Containers = args
 			return RunStart(cmd.Context(), dockerCLI, &opts)
 		},
 		Annotations: map[string]string{
 			"aliases": "docker container start, docker start",
 		},
-		ValidArgsFunction: completion.ContainerNames(dockerCLI, true, func(ctr container.Summary) bool {
-			return ctr.State == container.StateExited || ctr.State == container.StateCreated
-		}),
+		ValidArgsFunction:     completion.ContainerNames(dockerCLI, true, stopped),
 		DisableFlagsInUseLine: true,
 	}
 
@@ -6,6 +6,7 @@
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"strings"
 	"sync"
 	"time"
@@ -61,6 +62,35 @@ type StatsOptions struct {
 	// above), but may require daemon-side validation as the list of accepted
 	// filters can differ between daemon- and API versions.
 	Filters *filters.Args
+
+	// Record is the path of a file to record samples of the statistics to.
+	// Samples are recorded as JSON lines if the file has a ".json", ".jsonl",
+	// or ".ndjson" extension, and as CSV otherwise.
+	Record string
+
+	// Interval is the interval at which samples are recorded if Record or
+	// Summary is set. It defaults to one second.
+	Interval time.Duration
+
+	// Duration is the duration to collect statistics for. Statistics are
+	// collected until interrupted if no duration is set.
+	Duration time.Duration
+
+	// Summary prints the minimum, average, maximum, and 95th percentile of
+	// the statistics of each container when collecting statistics ends.
+	Summary bool
+
+	// Trend is the number of samples to show the trend of the CPU and memory
+	// usage for. Trends are not shown if zero.
+	Trend int
+
+	// Alerts are thresholds, such as "cpu>90" or "mem>80%", to highlight the
+	// containers exceeding them for.
+	Alerts []string
+
+	// AlertExitCode is the status code to exit with once a threshold is
+	// exceeded. Collecting statistics continues if zero.
+	AlertExitCode int
 }
 
 // newStatsCommand creates a new [cobra.Command] for "docker container stats".
@@ -87,6 +117,13 @@ func newStatsCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.BoolVar(&options.NoStream, "no-stream", false, "Disable streaming stats and only pull the first result")
 	flags.BoolVar(&options.NoTrunc, "no-trunc", false, "Do not truncate output")
 	flags.StringVar(&options.Format, "format", "", flagsHelper.FormatHelp)
+	flags.StringVar(&options.Record, "record", "", "Record samples to a file (CSV, or JSON lines for .json, .jsonl, and .ndjson files)")
+	flags.DurationVar(&options.Interval, "interval", defaultStatsRecordInterval, "Interval at which to record samples")
+	flags.DurationVar(&options.Duration, "duration", 0, "Stop collecting statistics after the given duration")
+	flags.BoolVar(&options.Summary, "summary", false, "Print a summary of the statistics of each container when collecting ends")
+	flags.IntVar(&options.Trend, "trend", 0, "Show the trend of the CPU and memory usage over the given number of samples")
+	flags.StringSliceVar(&options.Alerts, "alert", nil, "Highlight containers exceeding thresholds (e.g. \"cpu>90,mem>80%\")")
+	flags.IntVar(&options.AlertExitCode, "alert-exit-code", 0, "Exit with the given status code once a threshold is exceeded")
 	return cmd
 }
 
@@ -108,6 +145,33 @@ func newStatsCommand(dockerCLI command.Cli) *cobra.Command {
 func RunStats(ctx context.Context, dockerCLI command.Cli, options *StatsOptions) error {
 	apiClient := dockerCLI.Client()
 
+	recording := options.Record != "" || options.Summary
+	interval := options.Interval
+	if interval == 0 {
+		interval = defaultStatsRecordInterval
+	}
+	if recording && interval < 0 {
+		return errors.New("invalid interval: must be a positive duration")
+	}
+	if options.Duration < 0 {
+		return errors.New("invalid duration: must be a positive duration")
+	}
+	if options.Trend < 0 {
+		return errors.New("invalid trend: the number of samples must be positive")
+	}
+	alerts, err := parseStatsAlerts(options.Alerts)
+	if err != nil {
+		return err
+	}
+	if options.AlertExitCode != 0 && len(alerts) == 0 {
+		return errors.New("the --alert-exit-code option requires thresholds to be set with --alert")
+	}
+	newStats := func(ctr string) *Stats {
+		s := NewStats(ctr)
+		s.historySize = options.Trend
+		return s
+	}
+
 	// waitFirst is a WaitGroup to wait first stat data's reach for each container
 	waitFirst := &sync.WaitGroup{}
 	// closeChan is a non-buffered channel used to collect errors from goroutines.
@@ -134,7 +198,7 @@ func RunStats(ctx context.Context, dockerCLI command.Cli, options *StatsOptions)
 		eh := newEventHandler()
 		if options.All {
 			eh.setHandler(events.ActionCreate, func(e events.Message) {
-				s := NewStats(e.Actor.ID)
+				s := newStats(e.Actor.ID)
 				if cStats.add(s) {
 					waitFirst.Add(1)
 					go collect(ctx, s, apiClient, !options.NoStream, waitFirst)
@@ -143,7 +207,7 @@ func RunStats(ctx context.Context, dockerCLI command.Cli, options *StatsOptions)
 		}
 
 		eh.setHandler(events.ActionStart, func(e events.Message) {
-			s := NewStats(e.Actor.ID)
+			s := newStats(e.Actor.ID)
 			if cStats.add(s) {
 				waitFirst.Add(1)
 				go collect(ctx, s, apiClient, !options.NoStream, waitFirst)
@@ -205,7 +269,7 @@ func RunStats(ctx context.Context, dockerCLI command.Cli, options *StatsOptions)
 			return err
 		}
 		for _, ctr := range cs {
-			s := NewStats(ctr.ID)
+			s := newStats(ctr.ID)
 			if cStats.add(s) {
 				waitFirst.Add(1)
 				go collect(ctx, s, apiClient, !options.NoStream, waitFirst)
@@ -226,7 +290,7 @@ func RunStats(ctx context.Context, dockerCLI command.Cli, options *StatsOptions)
 		// Create the list of containers, and start collecting stats for all
 		// containers passed.
 		for _, ctr := range options.Containers {
-			s := NewStats(ctr)
+			s := newStats(ctr)
 			if cStats.add(s) {
 				waitFirst.Add(1)
 				go collect(ctx, s, apiClient, !options.NoStream, waitFirst)
@@ -271,29 +335,90 @@ func RunStats(ctx context.Context, dockerCLI command.Cli, options *StatsOptions)
 	// Once formatted, it will be printed in one write to avoid screen flickering.
 	var statsTextBuffer bytes.Buffer
 
+	statsFormat := NewStatsFormat(format, daemonOSType)
+	if options.Trend > 0 && format == formatter.TableFormatKey {
+		statsFormat += trendStatsColumns
+	}
 	statsCtx := formatter.Context{
 		Output: &statsTextBuffer,
-		Format: NewStatsFormat(format, daemonOSType),
+		Format: statsFormat,
+	}
+
+	// Rows of containers exceeding a threshold are highlighted if the
+	// output is a terminal, and the format is a table.
+	highlight := len(alerts) > 0 && statsFormat.IsTable() && dockerCLI.Out().IsTerminal()
+	var alertErr error
+
+	var rec *statsRecorder
+	if recording {
+		var w io.Writer
+		if options.Record != "" {
+			f, err := os.Create(options.Record)
+			if err != nil {
+				return fmt.Errorf("failed to create recording: %w", err)
+			}
+			defer f.Close()
+			w = f
+		}
+		rec, err = newStatsRecorder(w, recordFormat(options.Record), options.Summary)
+		if err != nil {
+			return fmt.Errorf("failed to write recording: %w", err)
+		}
+	}
+
+	// recordC, done, and deadline are only set when recording, and when
+	// a duration is set; receiving from a nil channel blocks forever.
+	var recordC <-chan time.Time
+	var done <-chan struct{}
+	if rec != nil && !options.NoStream {
+		recordTicker := time.NewTicker(interval)
+		defer recordTicker.Stop()
+		recordC = recordTicker.C
+		done = ctx.Done()
+	}
+	var deadline <-chan time.Time
+	if options.Duration > 0 {
+		timer := time.NewTimer(options.Duration)
+		defer timer.Stop()
+		deadline = timer.C
 	}
 
-	var err error
 	ticker := time.NewTicker(500 * time.Millisecond)
 	defer ticker.Stop()
-	for range ticker.C {
-		var ccStats []StatsEntry
-		cStats.mu.RLock()
-		for _, c := range cStats.cs {
-			ccStats = append(ccStats, c.GetStatistics())
+loop:
+	for {
+		select {
+		case <-ticker.C:
+		case t := <-recordC:
+			if err := rec.record(t, cStats.entries()); err != nil {
+				return fmt.Errorf("failed to write recording: %w", err)
+			}
+			continue
+		case <-done:
+			break loop
+		case <-deadline:
+			break loop
 		}
-		cStats.mu.RUnlock()
+
+		ccStats := cStats.entries()
 
 		if !options.NoStream {
 			// Start by moving the cursor to the top-left
 			_, _ = fmt.Fprint(&statsTextBuffer, "\033[H")
 		}
 
-		if err = statsFormatWrite(statsCtx, ccStats, daemonOSType, !options.NoTrunc); err != nil {
-			break
+		if err := statsFormatWrite(statsCtx, ccStats, daemonOSType, !options.NoTrunc); err != nil {
+			return err
+		}
+
+		if len(alerts) > 0 {
+			triggered := checkStatsAlerts(alerts, ccStats)
+			if highlight {
+				highlightRows(&statsTextBuffer, triggered, 1)
+			}
+			if options.AlertExitCode != 0 && alertErr == nil {
+				alertErr = alertError(triggered, ccStats, options.AlertExitCode)
+			}
 		}
 
 		if !options.NoStream {
@@ -310,7 +435,15 @@ func RunStats(ctx context.Context, dockerCLI command.Cli, options *StatsOptions)
 		_, _ = fmt.Fprint(dockerCLI.Out(), statsTextBuffer.String())
 		statsTextBuffer.Reset()
 
-		if len(cStats.cs) == 0 && !showAll {
+		if options.NoStream && rec != nil {
+			if err := rec.record(time.Now(), ccStats); err != nil {
+				return fmt.Errorf("failed to write recording: %w", err)
+			}
+		}
+		if alertErr != nil {
+			break
+		}
+		if len(ccStats) == 0 && !showAll {
 			break
 		}
 		if options.NoStream {
@@ -323,7 +456,7 @@ func RunStats(ctx context.Context, dockerCLI command.Cli, options *StatsOptions)
 					// Suppress "unexpected EOF" errors in the CLI so that
 					// it shuts down cleanly when the daemon restarts.
 					if errors.Is(err, io.ErrUnexpectedEOF) {
-						return nil
+						break loop
 					}
 					return err
 				}
@@ -332,7 +465,15 @@ func RunStats(ctx context.Context, dockerCLI command.Cli, options *StatsOptions)
 			// just skip
 		}
 	}
-	return err
+	if options.Summary {
+		if err := statsSummaryFormatWrite(formatter.Context{
+			Output: dockerCLI.Out(),
+			Format: statsSummaryTableFormat,
+		}, rec.summarize(), !options.NoTrunc); err != nil {
+			return err
+		}
+	}
+	return alertErr
 }
 
 // newEventHandler initializes and returns an eventHandler
@@ -0,0 +1,168 @@
+package container
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/internal/lazyregexp"
+	"github.com/docker/go-units"
+	"github.com/morikuni/aec"
+)
+
+// statsAlertRe matches a threshold, such as "cpu>90", or "mem>=80%".
+var statsAlertRe = lazyregexp.New(`^\s*(cpu|mem|pids)\s*(>=|<=|>|<)\s*(\S+)\s*$`)
+
+// statsAlert is a threshold for a resource usage statistic of a container.
+type statsAlert struct {
+	spec     string
+	metric   string
+	operator string
+	value    float64
+
+	// percent is set if the threshold is a percentage. CPU thresholds are
+	// always a percentage; memory thresholds are in bytes unless a "%"
+	// suffix is used.
+	percent bool
+}
+
+// parseStatsAlerts parses thresholds in the format "<metric><operator><value>",
+// where metric is one of "cpu", "mem", or "pids", and operator is one of ">",
+// ">=", "<", or "<=".
+func parseStatsAlerts(specs []string) ([]statsAlert, error) {
+	alerts := make([]statsAlert, 0, len(specs))
+	for _, spec := range specs {
+		m := statsAlertRe.FindStringSubmatch(spec)
+		if m == nil {
+			return nil, fmt.Errorf("invalid alert %q: must be in the format <cpu|mem|pids><operator><value>, for example cpu>90", spec)
+		}
+		a := statsAlert{
+			spec:     strings.TrimSpace(spec),
+			metric:   m[1],
+			operator: m[2],
+		}
+		value := m[3]
+		if a.metric == "cpu" || strings.HasSuffix(value, "%") {
+			if a.metric == "pids" {
+				return nil, fmt.Errorf("invalid alert %q: pids thresholds cannot be a percentage", spec)
+			}
+			a.percent = true
+			value = strings.TrimSuffix(value, "%")
+		}
+		var err error
+		switch {
+		case a.metric == "mem" && !a.percent:
+			var b int64
+			b, err = units.RAMInBytes(value)
+			a.value = float64(b)
+		default:
+			a.value, err = strconv.ParseFloat(value, 64)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid alert %q: invalid value %q", spec, m[3])
+		}
+		alerts = append(alerts, a)
+	}
+	return alerts, nil
+}
+
+func (a statsAlert) String() string {
+	return a.spec
+}
+
+// current returns the current value of the statistic of the alert.
+func (a statsAlert) current(s StatsEntry) float64 {
+	switch a.metric {
+	case "cpu":
+		return s.CPUPercentage
+	case "mem":
+		if a.percent {
+			return s.MemoryPercentage
+		}
+		return s.Memory
+	default:
+		return float64(s.PidsCurrent)
+	}
+}
+
+// triggered returns whether the threshold is exceeded. Thresholds are never
+// exceeded for invalid statistics, such as those of stopped containers.
+func (a statsAlert) triggered(s StatsEntry) bool {
+	if s.IsInvalid {
+		return false
+	}
+	v := a.current(s)
+	switch a.operator {
+	case ">":
+		return v > a.value
+	case ">=":
+		return v >= a.value
+	case "<":
+		return v < a.value
+	default:
+		return v <= a.value
+	}
+}
+
+// format formats the current value of the statistic of the alert.
+func (a statsAlert) format(s StatsEntry) string {
+	v := a.current(s)
+	switch {
+	case a.percent:
+		return formatPercentage(v)
+	case a.metric == "mem":
+		return units.BytesSize(v)
+	default:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+}
+
+// checkStatsAlerts returns, for each of the statistics, the first alert that
+// is triggered, if any.
+func checkStatsAlerts(alerts []statsAlert, stats []StatsEntry) []*statsAlert {
+	triggered := make([]*statsAlert, len(stats))
+	for i, s := range stats {
+		for j := range alerts {
+			if alerts[j].triggered(s) {
+				triggered[i] = &alerts[j]
+				break
+			}
+		}
+	}
+	return triggered
+}
+
+// alertError returns an error with the given status code for the first
+// container that exceeds a threshold, or nil if no thresholds are exceeded.
+func alertError(triggered []*statsAlert, stats []StatsEntry, code int) error {
+	for i, a := range triggered {
+		if a == nil {
+			continue
+		}
+		name := strings.TrimPrefix(stats[i].Name, "/")
+		if name == "" {
+			name = stats[i].Container
+		}
+		return cli.StatusError{
+			StatusCode: code,
+			Status:     fmt.Sprintf("container %s exceeded threshold %s (%s)", name, a, a.format(stats[i])),
+		}
+	}
+	return nil
+}
+
+// highlightRows highlights the lines of a rendered stats table for which an
+// alert is triggered. The offset is the number of lines (such as the table
+// header) that precede the first row.
+func highlightRows(buf *bytes.Buffer, triggered []*statsAlert, offset int) {
+	lines := strings.Split(buf.String(), "\n")
+	for i, a := range triggered {
+		if a != nil && i+offset < len(lines) {
+			lines[i+offset] = aec.RedF.Apply(lines[i+offset])
+		}
+	}
+	buf.Reset()
+	buf.WriteString(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,100 @@
+package container
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/internal/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+	"github.com/morikuni/aec"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestParseStatsAlerts(t *testing.T) {
+	alerts, err := parseStatsAlerts([]string{"cpu>90", "mem>=80%", "mem > 512MiB", "pids<=10", "cpu<1.5%"})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(alerts, []statsAlert{
+		{spec: "cpu>90", metric: "cpu", operator: ">", value: 90, percent: true},
+		{spec: "mem>=80%", metric: "mem", operator: ">=", value: 80, percent: true},
+		{spec: "mem > 512MiB", metric: "mem", operator: ">", value: 512 * 1024 * 1024},
+		{spec: "pids<=10", metric: "pids", operator: "<=", value: 10},
+		{spec: "cpu<1.5%", metric: "cpu", operator: "<", value: 1.5, percent: true},
+	}, cmp.AllowUnexported(statsAlert{})))
+
+	for _, spec := range []string{"", "cpu", "disk>10", "cpu=>10", "cpu>lots", "pids>10%", "mem>10XB"} {
+		_, err := parseStatsAlerts([]string{spec})
+		assert.Check(t, is.ErrorContains(err, "invalid alert"), spec)
+	}
+}
+
+func TestStatsAlertTriggered(t *testing.T) {
+	alerts, err := parseStatsAlerts([]string{"cpu>90", "mem>80%", "mem>=1GiB"})
+	assert.NilError(t, err)
+
+	stats := []StatsEntry{
+		{Name: "/idle", CPUPercentage: 10, MemoryPercentage: 10, Memory: 1024},
+		{Name: "/busy", CPUPercentage: 95.5, MemoryPercentage: 10, Memory: 1024},
+		{Name: "/big", CPUPercentage: 10, MemoryPercentage: 10, Memory: 2 * 1024 * 1024 * 1024},
+		{Name: "/stopped", CPUPercentage: 100, IsInvalid: true},
+	}
+	triggered := checkStatsAlerts(alerts, stats)
+	assert.Assert(t, is.Len(triggered, 4))
+	assert.Check(t, triggered[0] == nil)
+	assert.Check(t, is.Equal(triggered[1].String(), "cpu>90"))
+	assert.Check(t, is.Equal(triggered[2].String(), "mem>=1GiB"))
+	assert.Check(t, triggered[3] == nil)
+
+	err = alertError(triggered, stats, 3)
+	assert.Check(t, is.Error(err, "container busy exceeded threshold cpu>90 (95.50%)"))
+	var stErr cli.StatusError
+	assert.Assert(t, errors.As(err, &stErr))
+	assert.Check(t, is.Equal(stErr.StatusCode, 3))
+
+	assert.Check(t, is.Nil(alertError(make([]*statsAlert, 2), stats, 3)))
+}
+
+func TestHighlightRows(t *testing.T) {
+	buf := bytes.NewBufferString("NAME\nidle\nbusy\n")
+	highlightRows(buf, []*statsAlert{nil, {spec: "cpu>90"}}, 1)
+	assert.Check(t, is.Equal(buf.String(), "NAME\nidle\n"+aec.RedF.Apply("busy")+"\n"))
+}
+
+func TestRunStatsAlertExitCode(t *testing.T) {
+	fakeCLI := test.NewFakeCli(&fakeClient{
+		containerStatsFunc: func(_ context.Context, containerID string, _ bool) (client.StatsResponseReader, error) {
+			resp, err := json.Marshal(container.StatsResponse{
+				Name:      "/" + containerID,
+				ID:        containerID,
+				PidsStats: container.PidsStats{Current: 42},
+			})
+			assert.NilError(t, err)
+			return client.StatsResponseReader{
+				Body:   io.NopCloser(bytes.NewReader(resp)),
+				OSType: "linux",
+			}, nil
+		},
+	})
+	err := RunStats(context.Background(), fakeCLI, &StatsOptions{
+		NoStream:      true,
+		Containers:    []string{"web"},
+		Alerts:        []string{"pids>40"},
+		AlertExitCode: 2,
+	})
+	assert.Check(t, is.Error(err, "container web exceeded threshold pids>40 (42)"))
+	assert.Check(t, is.Contains(fakeCLI.OutBuffer().String(), "web"))
+
+	err = RunStats(context.Background(), fakeCLI, &StatsOptions{
+		NoStream:      true,
+		Containers:    []string{"web"},
+		AlertExitCode: 2,
+	})
+	assert.Check(t, is.Error(err, "the --alert-exit-code option requires thresholds to be set with --alert"))
+}
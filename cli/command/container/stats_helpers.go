@@ -41,6 +41,17 @@ func (s *stats) remove(id string) {
 	s.mu.Unlock()
 }
 
+// entries returns the current statistics of all containers.
+func (s *stats) entries() []StatsEntry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entries := make([]StatsEntry, 0, len(s.cs))
+	for _, c := range s.cs {
+		entries = append(entries, c.GetStatistics())
+	}
+	return entries
+}
+
 func (s *stats) isKnownContainer(cid string) (int, bool) {
 	for i, c := range s.cs {
 		if c.Container == cid {
@@ -0,0 +1,363 @@
+package container
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"math"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/go-units"
+)
+
+const (
+	defaultStatsRecordInterval = time.Second
+
+	recordFormatCSV  = "csv"
+	recordFormatJSON = "json"
+
+	statsSummaryTableFormat = "table {{.ID}}\t{{.Name}}\t{{.Metric}}\t{{.Min}}\t{{.Avg}}\t{{.Max}}\t{{.P95}}"
+)
+
+// statsRecordColumns are the columns of a CSV recording, in order.
+var statsRecordColumns = []string{
+	"time", "id", "name",
+	"cpu_percent", "mem_usage", "mem_limit", "mem_percent",
+	"net_rx", "net_tx", "block_read", "block_write", "pids",
+}
+
+// statsSample is a single sample of the resource usage of a container, as
+// recorded by "docker stats --record". Memory, network, and block IO are
+// in bytes. Network and block IO are the totals since the container started.
+type statsSample struct {
+	Time       time.Time `json:"time"`
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	CPUPerc    float64   `json:"cpu_percent"`
+	MemUsage   float64   `json:"mem_usage"`
+	MemLimit   float64   `json:"mem_limit"`
+	MemPerc    float64   `json:"mem_percent"`
+	NetRx      float64   `json:"net_rx"`
+	NetTx      float64   `json:"net_tx"`
+	BlockRead  float64   `json:"block_read"`
+	BlockWrite float64   `json:"block_write"`
+	PIDs       uint64    `json:"pids"`
+}
+
+func newStatsSample(t time.Time, s StatsEntry) statsSample {
+	return statsSample{
+		Time:       t,
+		ID:         s.ID,
+		Name:       strings.TrimPrefix(s.Name, "/"),
+		CPUPerc:    s.CPUPercentage,
+		MemUsage:   s.Memory,
+		MemLimit:   s.MemoryLimit,
+		MemPerc:    s.MemoryPercentage,
+		NetRx:      s.NetworkRx,
+		NetTx:      s.NetworkTx,
+		BlockRead:  s.BlockRead,
+		BlockWrite: s.BlockWrite,
+		PIDs:       s.PidsCurrent,
+	}
+}
+
+func (s statsSample) csvRecord() []string {
+	f := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return []string{
+		s.Time.UTC().Format(time.RFC3339Nano), s.ID, s.Name,
+		f(s.CPUPerc), f(s.MemUsage), f(s.MemLimit), f(s.MemPerc),
+		f(s.NetRx), f(s.NetTx), f(s.BlockRead), f(s.BlockWrite),
+		strconv.FormatUint(s.PIDs, 10),
+	}
+}
+
+// recordFormat returns the format to record samples in, based on the
+// extension of the file; JSON lines for ".json", ".jsonl", and ".ndjson"
+// files, and CSV otherwise.
+func recordFormat(fileName string) string {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".json", ".jsonl", ".ndjson":
+		return recordFormatJSON
+	default:
+		return recordFormatCSV
+	}
+}
+
+// statsRecorder records samples of container statistics to a writer, and
+// keeps them for summarizing if needed.
+type statsRecorder struct {
+	csv  *csv.Writer
+	json *json.Encoder
+	keep bool
+
+	order   []string
+	samples map[string][]statsSample
+}
+
+// newStatsRecorder returns a recorder that writes samples to w in the
+// given format. No samples are written if w is nil. Samples are kept in
+// memory for [statsRecorder.summarize] if keep is set.
+func newStatsRecorder(w io.Writer, format string, keep bool) (*statsRecorder, error) {
+	r := &statsRecorder{
+		keep:    keep,
+		samples: make(map[string][]statsSample),
+	}
+	switch {
+	case w == nil:
+	case format == recordFormatJSON:
+		r.json = json.NewEncoder(w)
+	default:
+		r.csv = csv.NewWriter(w)
+		if err := r.csv.Write(statsRecordColumns); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// record records a sample for each of the given statistics. Invalid
+// statistics, such as those of containers that stopped, are skipped.
+func (r *statsRecorder) record(t time.Time, entries []StatsEntry) error {
+	for _, e := range entries {
+		if e.IsInvalid || e.ID == "" {
+			continue
+		}
+		s := newStatsSample(t, e)
+		switch {
+		case r.json != nil:
+			if err := r.json.Encode(s); err != nil {
+				return err
+			}
+		case r.csv != nil:
+			if err := r.csv.Write(s.csvRecord()); err != nil {
+				return err
+			}
+		}
+		if r.keep {
+			if _, ok := r.samples[s.ID]; !ok {
+				r.order = append(r.order, s.ID)
+			}
+			r.samples[s.ID] = append(r.samples[s.ID], s)
+		}
+	}
+	if r.csv != nil {
+		r.csv.Flush()
+		return r.csv.Error()
+	}
+	return nil
+}
+
+// statsAggregate is the minimum, average, maximum, and 95th percentile of
+// a series of values.
+type statsAggregate struct {
+	Count              int
+	Min, Avg, Max, P95 float64
+}
+
+func aggregate(values []float64) statsAggregate {
+	if len(values) == 0 {
+		return statsAggregate{}
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+	// nearest-rank percentile
+	p95 := int(math.Ceil(0.95*float64(len(sorted)))) - 1
+	return statsAggregate{
+		Count: len(sorted),
+		Min:   sorted[0],
+		Avg:   sum / float64(len(sorted)),
+		Max:   sorted[len(sorted)-1],
+		P95:   sorted[p95],
+	}
+}
+
+// statsSummary summarizes the samples recorded for a container. Network and
+// block IO are summarized as throughput (bytes per second) between samples.
+type statsSummary struct {
+	ID         string
+	Name       string
+	Samples    int
+	Start, End time.Time
+	CPUPerc    statsAggregate
+	MemUsage   statsAggregate
+	NetRx      statsAggregate
+	NetTx      statsAggregate
+	BlockRead  statsAggregate
+	BlockWrite statsAggregate
+}
+
+// summarize returns a summary for each container that samples were kept
+// for, in the order the containers were first recorded.
+func (r *statsRecorder) summarize() []statsSummary {
+	summaries := make([]statsSummary, 0, len(r.order))
+	for _, id := range r.order {
+		summaries = append(summaries, summarizeSamples(r.samples[id]))
+	}
+	return summaries
+}
+
+func summarizeSamples(samples []statsSample) statsSummary {
+	var cpu, mem, netRx, netTx, blkRead, blkWrite []float64
+	for i, s := range samples {
+		cpu = append(cpu, s.CPUPerc)
+		mem = append(mem, s.MemUsage)
+		if i == 0 {
+			continue
+		}
+		prev := samples[i-1]
+		elapsed := s.Time.Sub(prev.Time).Seconds()
+		if elapsed <= 0 {
+			continue
+		}
+		rate := func(cur, prev float64) []float64 {
+			// Counters are reset when a container restarts; skip the
+			// sample instead of reporting a negative throughput.
+			if cur < prev {
+				return nil
+			}
+			return []float64{(cur - prev) / elapsed}
+		}
+		netRx = append(netRx, rate(s.NetRx, prev.NetRx)...)
+		netTx = append(netTx, rate(s.NetTx, prev.NetTx)...)
+		blkRead = append(blkRead, rate(s.BlockRead, prev.BlockRead)...)
+		blkWrite = append(blkWrite, rate(s.BlockWrite, prev.BlockWrite)...)
+	}
+	last := samples[len(samples)-1]
+	return statsSummary{
+		ID:         last.ID,
+		Name:       last.Name,
+		Samples:    len(samples),
+		Start:      samples[0].Time,
+		End:        last.Time,
+		CPUPerc:    aggregate(cpu),
+		MemUsage:   aggregate(mem),
+		NetRx:      aggregate(netRx),
+		NetTx:      aggregate(netTx),
+		BlockRead:  aggregate(blkRead),
+		BlockWrite: aggregate(blkWrite),
+	}
+}
+
+type statsMetricKind int
+
+const (
+	metricPercentage statsMetricKind = iota
+	metricBytes
+	metricThroughput
+)
+
+// statsSummaryFormatWrite renders the summaries of recorded statistics,
+// printing a row for each metric of each container.
+func statsSummaryFormatWrite(ctx formatter.Context, summaries []statsSummary, trunc bool) error {
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for _, s := range summaries {
+			metrics := []struct {
+				name string
+				kind statsMetricKind
+				agg  statsAggregate
+			}{
+				{name: cpuPercHeader, kind: metricPercentage, agg: s.CPUPerc},
+				{name: "MEM USAGE", kind: metricBytes, agg: s.MemUsage},
+				{name: "NET RX/s", kind: metricThroughput, agg: s.NetRx},
+				{name: "NET TX/s", kind: metricThroughput, agg: s.NetTx},
+				{name: "BLOCK READ/s", kind: metricThroughput, agg: s.BlockRead},
+				{name: "BLOCK WRITE/s", kind: metricThroughput, agg: s.BlockWrite},
+			}
+			for _, m := range metrics {
+				if err := format(&statsSummaryContext{
+					s:      s,
+					metric: m.name,
+					kind:   m.kind,
+					agg:    m.agg,
+					trunc:  trunc,
+				}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	summaryCtx := statsSummaryContext{}
+	summaryCtx.Header = formatter.SubHeaderContext{
+		"ID":     formatter.ContainerIDHeader,
+		"Name":   formatter.NameHeader,
+		"Metric": "METRIC",
+		"Min":    "MIN",
+		"Avg":    "AVG",
+		"Max":    "MAX",
+		"P95":    "P95",
+	}
+	return ctx.Write(&summaryCtx, render)
+}
+
+type statsSummaryContext struct {
+	formatter.HeaderContext
+	s      statsSummary
+	metric string
+	kind   statsMetricKind
+	agg    statsAggregate
+	trunc  bool
+}
+
+func (c *statsSummaryContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *statsSummaryContext) ID() string {
+	if c.trunc {
+		return formatter.TruncateID(c.s.ID)
+	}
+	return c.s.ID
+}
+
+func (c *statsSummaryContext) Name() string {
+	if c.s.Name == "" {
+		return noValue
+	}
+	return c.s.Name
+}
+
+func (c *statsSummaryContext) Metric() string {
+	return c.metric
+}
+
+func (c *statsSummaryContext) Min() string {
+	return c.value(c.agg.Min)
+}
+
+func (c *statsSummaryContext) Avg() string {
+	return c.value(c.agg.Avg)
+}
+
+func (c *statsSummaryContext) Max() string {
+	return c.value(c.agg.Max)
+}
+
+func (c *statsSummaryContext) P95() string {
+	return c.value(c.agg.P95)
+}
+
+func (c *statsSummaryContext) value(v float64) string {
+	if c.agg.Count == 0 {
+		return noValue
+	}
+	switch c.kind {
+	case metricPercentage:
+		return formatPercentage(v)
+	case metricBytes:
+		return units.BytesSize(v)
+	default:
+		return units.HumanSizeWithPrecision(v, 3) + "/s"
+	}
+}
@@ -0,0 +1,170 @@
+package container
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/internal/test"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestRecordFormat(t *testing.T) {
+	tests := []struct {
+		fileName string
+		expected string
+	}{
+		{fileName: "stats.csv", expected: recordFormatCSV},
+		{fileName: "stats", expected: recordFormatCSV},
+		{fileName: "stats.json", expected: recordFormatJSON},
+		{fileName: "stats.JSONL", expected: recordFormatJSON},
+		{fileName: "dir.json/stats.ndjson", expected: recordFormatJSON},
+	}
+	for _, tc := range tests {
+		t.Run(tc.fileName, func(t *testing.T) {
+			assert.Check(t, is.Equal(recordFormat(tc.fileName), tc.expected))
+		})
+	}
+}
+
+func TestStatsRecorder(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	entries := []StatsEntry{
+		{ID: "abc123", Name: "/web", CPUPercentage: 12.5, Memory: 1024, MemoryLimit: 4096, MemoryPercentage: 25, NetworkRx: 10, NetworkTx: 20, BlockRead: 30, BlockWrite: 40, PidsCurrent: 3},
+		{ID: "def456", Name: "/db", IsInvalid: true},
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		var out bytes.Buffer
+		rec, err := newStatsRecorder(&out, recordFormatCSV, false)
+		assert.NilError(t, err)
+		assert.NilError(t, rec.record(ts, entries))
+		expected := `time,id,name,cpu_percent,mem_usage,mem_limit,mem_percent,net_rx,net_tx,block_read,block_write,pids
+2024-01-02T03:04:05Z,abc123,web,12.5,1024,4096,25,10,20,30,40,3
+`
+		assert.Check(t, is.Equal(out.String(), expected))
+		assert.Check(t, is.Len(rec.summarize(), 0))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var out bytes.Buffer
+		rec, err := newStatsRecorder(&out, recordFormatJSON, true)
+		assert.NilError(t, err)
+		assert.NilError(t, rec.record(ts, entries))
+		expected := `{"time":"2024-01-02T03:04:05Z","id":"abc123","name":"web","cpu_percent":12.5,"mem_usage":1024,"mem_limit":4096,"mem_percent":25,"net_rx":10,"net_tx":20,"block_read":30,"block_write":40,"pids":3}
+`
+		assert.Check(t, is.Equal(out.String(), expected))
+		assert.Check(t, is.Len(rec.summarize(), 1))
+	})
+}
+
+func TestAggregate(t *testing.T) {
+	assert.Check(t, is.DeepEqual(aggregate(nil), statsAggregate{}))
+
+	values := make([]float64, 0, 20)
+	for i := 20; i > 0; i-- {
+		values = append(values, float64(i))
+	}
+	assert.Check(t, is.DeepEqual(aggregate(values), statsAggregate{
+		Count: 20,
+		Min:   1,
+		Avg:   10.5,
+		Max:   20,
+		P95:   19,
+	}))
+	// the input must not be modified
+	assert.Check(t, is.Equal(values[0], 20.0))
+}
+
+func TestSummarizeSamples(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	samples := []statsSample{
+		{Time: ts, ID: "abc123", Name: "web", CPUPerc: 10, MemUsage: 100, NetRx: 0, BlockWrite: 1000},
+		{Time: ts.Add(time.Second), ID: "abc123", Name: "web", CPUPerc: 30, MemUsage: 300, NetRx: 1000, BlockWrite: 1000},
+		{Time: ts.Add(3 * time.Second), ID: "abc123", Name: "web", CPUPerc: 20, MemUsage: 200, NetRx: 5000, BlockWrite: 0},
+	}
+	s := summarizeSamples(samples)
+	assert.Check(t, is.Equal(s.Samples, 3))
+	assert.Check(t, is.Equal(s.End.Sub(s.Start), 3*time.Second))
+	assert.Check(t, is.DeepEqual(s.CPUPerc, statsAggregate{Count: 3, Min: 10, Avg: 20, Max: 30, P95: 30}))
+	assert.Check(t, is.DeepEqual(s.MemUsage, statsAggregate{Count: 3, Min: 100, Avg: 200, Max: 300, P95: 300}))
+	assert.Check(t, is.DeepEqual(s.NetRx, statsAggregate{Count: 2, Min: 1000, Avg: 1500, Max: 2000, P95: 2000}))
+	// the counter was reset, which must not produce a negative throughput
+	assert.Check(t, is.DeepEqual(s.BlockWrite, statsAggregate{Count: 1}))
+}
+
+func TestStatsSummaryFormatWrite(t *testing.T) {
+	summaries := []statsSummary{
+		{
+			ID:       "abc123def4567890",
+			Name:     "web",
+			Samples:  1,
+			CPUPerc:  statsAggregate{Count: 1, Min: 1.5, Avg: 1.5, Max: 1.5, P95: 1.5},
+			MemUsage: statsAggregate{Count: 1, Min: 1024, Avg: 1024, Max: 1024, P95: 1024},
+		},
+	}
+	var out bytes.Buffer
+	err := statsSummaryFormatWrite(formatter.Context{Output: &out, Format: statsSummaryTableFormat}, summaries, true)
+	assert.NilError(t, err)
+	expected := `CONTAINER ID   NAME      METRIC          MIN       AVG       MAX       P95
+abc123def456   web       CPU %           1.50%     1.50%     1.50%     1.50%
+abc123def456   web       MEM USAGE       1KiB      1KiB      1KiB      1KiB
+abc123def456   web       NET RX/s        --        --        --        --
+abc123def456   web       NET TX/s        --        --        --        --
+abc123def456   web       BLOCK READ/s    --        --        --        --
+abc123def456   web       BLOCK WRITE/s   --        --        --        --
+`
+	assert.Check(t, is.Equal(out.String(), expected))
+}
+
+func TestRunStatsRecordNoStream(t *testing.T) {
+	fakeCLI := test.NewFakeCli(&fakeClient{
+		containerStatsFunc: func(_ context.Context, containerID string, stream bool) (client.StatsResponseReader, error) {
+			assert.Check(t, !stream)
+			resp, err := json.Marshal(container.StatsResponse{
+				Name: "/" + containerID,
+				ID:   containerID + "-id",
+				MemoryStats: container.MemoryStats{
+					Usage: 2048,
+					Limit: 4096,
+				},
+			})
+			assert.NilError(t, err)
+			return client.StatsResponseReader{
+				Body:   io.NopCloser(bytes.NewReader(resp)),
+				OSType: "linux",
+			}, nil
+		},
+	})
+	recordFile := filepath.Join(t.TempDir(), "stats.jsonl")
+	err := RunStats(context.Background(), fakeCLI, &StatsOptions{
+		NoStream:   true,
+		Containers: []string{"web"},
+		Record:     recordFile,
+		Summary:    true,
+	})
+	assert.NilError(t, err)
+
+	recorded, err := os.ReadFile(recordFile)
+	assert.NilError(t, err)
+	var sample statsSample
+	assert.NilError(t, json.Unmarshal(recorded, &sample))
+	assert.Check(t, is.Equal(sample.ID, "web-id"))
+	assert.Check(t, is.Equal(sample.Name, "web"))
+	assert.Check(t, is.Equal(sample.MemUsage, 2048.0))
+
+	out := fakeCLI.OutBuffer().String()
+	assert.Check(t, is.Contains(out, "METRIC"))
+	assert.Check(t, is.Contains(out, "web-id"))
+	assert.Check(t, is.Equal(strings.Count(out, "MEM USAGE  "), 1))
+}
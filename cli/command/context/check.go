@@ -0,0 +1,91 @@
+package context
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/context/docker"
+	"github.com/docker/cli/cli/context/store"
+	"github.com/moby/moby/client"
+)
+
+const defaultCheckTimeout = 5 * time.Second
+
+const (
+	statusOK          = "ok"
+	statusUnreachable = "unreachable"
+	statusError       = "error"
+)
+
+// checkContexts concurrently checks the docker endpoint of each context,
+// and records the status, server version, API version, and latency of
+// the endpoint in the context. Contexts that failed to load are not
+// checked.
+func checkContexts(ctx context.Context, s store.Reader, contexts []*formatter.ClientContext, timeout time.Duration) {
+	var wg sync.WaitGroup
+	for _, c := range contexts {
+		if c.Error != "" {
+			continue
+		}
+		wg.Add(1)
+		go func(c *formatter.ClientContext) {
+			defer wg.Done()
+			checkContext(ctx, s, c, timeout)
+		}(c)
+	}
+	wg.Wait()
+}
+
+func checkContext(ctx context.Context, s store.Reader, c *formatter.ClientContext, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	apiClient, err := newContextClient(s, c.Name)
+	if err != nil {
+		c.Status, c.Error = statusError, err.Error()
+		return
+	}
+	defer apiClient.Close()
+
+	start := time.Now()
+	ping, err := apiClient.Ping(ctx)
+	if err != nil {
+		c.Status, c.Error = statusUnreachable, err.Error()
+		return
+	}
+	c.Latency = time.Since(start)
+	c.APIVersion = ping.APIVersion
+
+	v, err := apiClient.ServerVersion(ctx)
+	if err != nil {
+		c.Status, c.Error = statusError, err.Error()
+		return
+	}
+	c.Status = statusOK
+	c.ServerVersion = v.Version
+}
+
+// newContextClient returns an API client for the docker endpoint of the
+// named context. Connection helpers (such as "ssh://") are supported.
+func newContextClient(s store.Reader, name string) (*client.Client, error) {
+	meta, err := s.GetMetadata(name)
+	if err != nil {
+		return nil, err
+	}
+	epMeta, err := docker.EndpointFromContext(meta)
+	if err != nil {
+		return nil, err
+	}
+	ep, err := docker.WithTLSData(s, name, epMeta)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := ep.ClientOpts()
+	if err != nil {
+		return nil, err
+	}
+	return client.NewClientWithOpts(append(opts, client.WithUserAgent(command.UserAgent()))...)
+}
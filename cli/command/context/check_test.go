@@ -0,0 +1,108 @@
+package context
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func newTestDaemon(t *testing.T) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Api-Version", "1.51")
+		if strings.HasSuffix(r.URL.Path, "/version") {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"Version":"28.3.0","ApiVersion":"1.51"}`))
+			return
+		}
+		_, _ = w.Write([]byte("OK"))
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func createTestContextWithHost(t *testing.T, cli command.Cli, name, host string) {
+	t.Helper()
+	assert.NilError(t, runCreate(cli, name, createOptions{
+		endpoint: map[string]string{keyHost: host},
+	}))
+}
+
+func TestCheckContexts(t *testing.T) {
+	daemon := newTestDaemon(t)
+	stopped := httptest.NewServer(http.NotFoundHandler())
+	stopped.Close()
+
+	cli := makeFakeCli(t)
+	createTestContextWithHost(t, cli, "up", "tcp://"+daemon.Listener.Addr().String())
+	createTestContextWithHost(t, cli, "down", "tcp://"+stopped.Listener.Addr().String())
+
+	contexts := []*formatter.ClientContext{
+		{Name: "up"},
+		{Name: "down"},
+		{Name: "broken", Error: "context not found"},
+	}
+	checkContexts(context.Background(), cli.ContextStore(), contexts, 5*time.Second)
+
+	up := contexts[0]
+	assert.Check(t, is.Equal(up.Status, statusOK))
+	assert.Check(t, is.Equal(up.ServerVersion, "28.3.0"))
+	assert.Check(t, is.Equal(up.APIVersion, "1.51"))
+	assert.Check(t, up.Latency > 0)
+	assert.Check(t, is.Equal(up.Error, ""))
+
+	down := contexts[1]
+	assert.Check(t, is.Equal(down.Status, statusUnreachable))
+	assert.Check(t, down.Error != "")
+
+	broken := contexts[2]
+	assert.Check(t, is.Equal(broken.Status, ""))
+	assert.Check(t, is.Equal(broken.Error, "context not found"))
+}
+
+func TestListCheck(t *testing.T) {
+	daemon := newTestDaemon(t)
+	cli := makeFakeCli(t)
+	createTestContextWithHost(t, cli, "up", "tcp://"+daemon.Listener.Addr().String())
+	cli.SetCurrentContext("up")
+
+	cli.OutBuffer().Reset()
+	assert.NilError(t, runList(context.Background(), cli, &listOptions{
+		check:        true,
+		checkTimeout: 5 * time.Second,
+		format:       `{{.Name}}{{if .Current}} *{{end}} {{.Status}} {{.ServerVersion}} {{.APIVersion}}`,
+	}))
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), "up * ok 28.3.0 1.51\n"))
+
+	cli.OutBuffer().Reset()
+	assert.NilError(t, runList(context.Background(), cli, &listOptions{check: true, checkTimeout: 5 * time.Second}))
+	header := strings.Fields(strings.SplitN(cli.OutBuffer().String(), "\n", 2)[0])
+	assert.Check(t, is.DeepEqual(header, []string{"NAME", "DOCKER", "ENDPOINT", "STATUS", "SERVER", "VERSION", "API", "VERSION", "LATENCY", "ERROR"}))
+}
+
+func TestListCheckInvalidOptions(t *testing.T) {
+	for _, tc := range []struct {
+		args        []string
+		expectedErr string
+	}{
+		{args: []string{"--check-timeout", "1s"}, expectedErr: "--check-timeout can only be used with --check"},
+		{args: []string{"--check", "--quiet"}, expectedErr: "conflicting options: cannot specify both --quiet and --check"},
+	} {
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			cmd := newListCommand(makeFakeCli(t))
+			cmd.SetArgs(tc.args)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			assert.Check(t, is.Error(cmd.Execute(), tc.expectedErr))
+		})
+	}
+}
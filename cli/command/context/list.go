@@ -4,9 +4,12 @@
 package context
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"sort"
+	"time"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
@@ -19,8 +22,10 @@
 )
 
 type listOptions struct {
-	format string
-	quiet  bool
+	format       string
+	quiet        bool
+	check        bool
+	checkTimeout time.Duration
 }
 
 func newListCommand(dockerCLI command.Cli) *cobra.Command {
@@ -31,7 +36,13 @@ func newListCommand(dockerCLI command.Cli) *cobra.Command {
 		Short:   "List contexts",
 		Args:    cli.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runList(dockerCLI, opts)
+			if cmd.Flags().Changed("check-timeout") && !opts.check {
+				return errors.New("--check-timeout can only be used with --check")
+			}
+			if opts.quiet && opts.check {
+				return errors.New("conflicting options: cannot specify both --quiet and --check")
+			}
+			return runList(cmd.Context(), dockerCLI, opts)
 		},
 		ValidArgsFunction:     cobra.NoFileCompletions,
 		DisableFlagsInUseLine: true,
@@ -40,10 +51,12 @@ func newListCommand(dockerCLI command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.StringVar(&opts.format, "format", "", flagsHelper.FormatHelp)
 	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Only show context names")
+	flags.BoolVar(&opts.check, "check", false, "Check the status of each context's docker endpoint")
+	flags.DurationVar(&opts.checkTimeout, "check-timeout", defaultCheckTimeout, "Timeout for checking a context's docker endpoint")
 	return cmd
 }
 
-func runList(dockerCli command.Cli, opts *listOptions) error {
+func runList(ctx context.Context, dockerCli command.Cli, opts *listOptions) error {
 	if opts.format == "" {
 		opts.format = formatter.TableFormatKey
 	}
@@ -104,6 +117,9 @@ func runList(dockerCli command.Cli, opts *listOptions) error {
 	sort.Slice(contexts, func(i, j int) bool {
 		return sortorder.NaturalLess(contexts[i].Name, contexts[j].Name)
 	})
+	if opts.check {
+		checkContexts(ctx, dockerCli.ContextStore(), contexts, opts.checkTimeout)
+	}
 	if err := format(dockerCli, opts, contexts); err != nil {
 		return err
 	}
@@ -115,6 +131,12 @@ func runList(dockerCli command.Cli, opts *listOptions) error {
 }
 
 func format(dockerCli command.Cli, opts *listOptions, contexts []*formatter.ClientContext) error {
+	if opts.check {
+		return formatter.ClientContextCheckWrite(formatter.Context{
+			Output: dockerCli.Out(),
+			Format: formatter.NewClientContextCheckFormat(opts.format, opts.quiet),
+		}, contexts)
+	}
 	contextCtx := formatter.Context{
 		Output: dockerCli.Out(),
 		Format: formatter.NewClientContextFormat(opts.format, opts.quiet),
@@ -1,6 +1,7 @@
 package context
 
 import (
+	"context"
 	"testing"
 
 	"github.com/docker/cli/cli/command"
@@ -33,7 +34,7 @@ func TestList(t *testing.T) {
 	createTestContexts(t, cli, "current", "other", "unset")
 	cli.SetCurrentContext("current")
 	cli.OutBuffer().Reset()
-	assert.NilError(t, runList(cli, &listOptions{}))
+	assert.NilError(t, runList(context.Background(), cli, &listOptions{}))
 	golden.Assert(t, cli.OutBuffer().String(), "list.golden")
 }
 
@@ -47,19 +48,19 @@ func TestListJSON(t *testing.T) {
 
 	t.Run("format={{json .}}", func(t *testing.T) {
 		cli.OutBuffer().Reset()
-		assert.NilError(t, runList(cli, &listOptions{format: formatter.JSONFormat}))
+		assert.NilError(t, runList(context.Background(), cli, &listOptions{format: formatter.JSONFormat}))
 		golden.Assert(t, cli.OutBuffer().String(), "list-json.golden")
 	})
 
 	t.Run("format=json", func(t *testing.T) {
 		cli.OutBuffer().Reset()
-		assert.NilError(t, runList(cli, &listOptions{format: formatter.JSONFormatKey}))
+		assert.NilError(t, runList(context.Background(), cli, &listOptions{format: formatter.JSONFormatKey}))
 		golden.Assert(t, cli.OutBuffer().String(), "list-json.golden")
 	})
 
 	t.Run("format={{ json .Name }}", func(t *testing.T) {
 		cli.OutBuffer().Reset()
-		assert.NilError(t, runList(cli, &listOptions{format: `{{ json .Name }}`}))
+		assert.NilError(t, runList(context.Background(), cli, &listOptions{format: `{{ json .Name }}`}))
 		golden.Assert(t, cli.OutBuffer().String(), "list-json-name.golden")
 	})
 }
@@ -69,7 +70,7 @@ func TestListQuiet(t *testing.T) {
 	createTestContexts(t, cli, "current", "other")
 	cli.SetCurrentContext("current")
 	cli.OutBuffer().Reset()
-	assert.NilError(t, runList(cli, &listOptions{quiet: true}))
+	assert.NilError(t, runList(context.Background(), cli, &listOptions{quiet: true}))
 	golden.Assert(t, cli.OutBuffer().String(), "quiet-list.golden")
 }
 
@@ -77,6 +78,6 @@ func TestListError(t *testing.T) {
 	cli := makeFakeCli(t)
 	cli.SetCurrentContext("nosuchcontext")
 	cli.OutBuffer().Reset()
-	assert.NilError(t, runList(cli, &listOptions{}))
+	assert.NilError(t, runList(context.Background(), cli, &listOptions{}))
 	golden.Assert(t, cli.OutBuffer().String(), "list-with-error.golden")
 }
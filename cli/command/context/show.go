@@ -8,22 +8,40 @@
 	"github.com/spf13/cobra"
 )
 
+// contextSourceProvider is implemented by CLIs that can describe how the
+// current context was selected.
+type contextSourceProvider interface {
+	CurrentContextSource() command.ContextSource
+}
+
+type showOptions struct {
+	why bool
+}
+
 // newShowCommand creates a new cobra.Command for `docker context sow`
 func newShowCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts showOptions
 	cmd := &cobra.Command{
-		Use:   "show",
+		Use:   "show [OPTIONS]",
 		Short: "Print the name of the current context",
 		Args:  cli.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			runShow(dockerCLI)
+			runShow(dockerCLI, opts)
 			return nil
 		},
 		ValidArgsFunction:     cobra.NoFileCompletions,
 		DisableFlagsInUseLine: true,
 	}
+	cmd.Flags().BoolVar(&opts.why, "why", false, "Print how the current context was selected")
 	return cmd
 }
 
-func runShow(dockerCli command.Cli) {
+func runShow(dockerCli command.Cli, opts showOptions) {
 	fmt.Fprintln(dockerCli.Out(), dockerCli.CurrentContext())
+	if !opts.why {
+		return
+	}
+	if p, ok := dockerCli.(contextSourceProvider); ok {
+		fmt.Fprintln(dockerCli.Out(), "Source:", p.CurrentContextSource())
+	}
 }
@@ -3,6 +3,8 @@
 import (
 	"testing"
 
+	"github.com/docker/cli/cli/command"
+	"gotest.tools/v3/assert"
 	"gotest.tools/v3/golden"
 )
 
@@ -12,6 +14,15 @@ func TestShow(t *testing.T) {
 	cli.SetCurrentContext("current")
 
 	cli.OutBuffer().Reset()
-	runShow(cli)
+	runShow(cli, showOptions{})
 	golden.Assert(t, cli.OutBuffer().String(), "show.golden")
 }
+
+func TestShowWhy(t *testing.T) {
+	cli := makeFakeCli(t)
+	cli.SetCurrentContext("current")
+	cli.SetCurrentContextSource(command.ContextSource{Kind: command.ContextSourceFile, Path: "/project/.dockercontext"})
+
+	runShow(cli, showOptions{why: true})
+	assert.Equal(t, cli.OutBuffer().String(), "current\nSource: .dockercontext file (/project/.dockercontext)\n")
+}
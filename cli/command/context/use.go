@@ -54,5 +54,11 @@ func runUse(dockerCLI command.Cli, name string) error {
 		_, _ = fmt.Fprintf(dockerCLI.Err(), "Warning: %[1]s environment variable overrides the active context. "+
 			"To use %[2]q, either set the global --context flag, or unset %[1]s environment variable.\n", client.EnvOverrideHost, name)
 	}
+	if p, ok := dockerCLI.(contextSourceProvider); ok && dockerCLI.CurrentContext() != name {
+		if src := p.CurrentContextSource(); src.Kind == command.ContextSourceFile {
+			_, _ = fmt.Fprintf(dockerCLI.Err(), "Warning: %[1]s overrides the active context in this directory. "+
+				"To use %[2]q, either set the global --context flag, or remove %[1]s.\n", src.Path, name)
+		}
+	}
 	return nil
 }
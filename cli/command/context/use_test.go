@@ -152,3 +152,15 @@ func TestUseHostOverrideEmpty(t *testing.T) {
 	apiclient := cli.Client()
 	assert.Equal(t, apiclient.DaemonHost(), socketPath)
 }
+
+func TestUseWithContextFile(t *testing.T) {
+	configDir := t.TempDir()
+	cli := makeFakeCli(t, withCliConfig(configfile.New(filepath.Join(configDir, "config.json"))))
+	createTestContext(t, cli, "test", nil)
+	cli.SetCurrentContext("project")
+	cli.SetCurrentContextSource(command.ContextSource{Kind: command.ContextSourceFile, Path: "/project/.dockercontext"})
+
+	cli.ErrBuffer().Reset()
+	assert.NilError(t, newUseCommand(cli).RunE(nil, []string{"test"}))
+	assert.Check(t, is.Contains(cli.ErrBuffer().String(), `Warning: /project/.dockercontext overrides the active context in this directory. To use "test", either set the global --context flag, or remove /project/.dockercontext.`))
+}
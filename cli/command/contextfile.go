@@ -0,0 +1,104 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ContextFileName is the name of the file that sets the context to use for
+// the directory it is in, and its subdirectories. The file contains the name
+// of the context; empty lines and lines starting with "#" are ignored.
+const ContextFileName = ".dockercontext"
+
+// ContextSourceKind describes the kind of source that selected the current
+// context.
+type ContextSourceKind string
+
+const (
+	// ContextSourceFlag is used if the context was set through the
+	// "--context" command-line option.
+	ContextSourceFlag ContextSourceKind = "flag"
+	// ContextSourceHostFlag is used if the default context was used because
+	// the "--host" command-line option is set.
+	ContextSourceHostFlag ContextSourceKind = "host-flag"
+	// ContextSourceHostEnv is used if the default context was used because
+	// the "DOCKER_HOST" environment variable is set.
+	ContextSourceHostEnv ContextSourceKind = "host-env"
+	// ContextSourceEnv is used if the context was set through the
+	// "DOCKER_CONTEXT" environment variable ([EnvOverrideContext]).
+	ContextSourceEnv ContextSourceKind = "env"
+	// ContextSourceFile is used if the context was set through a
+	// [ContextFileName] file in the working directory or one of its parents.
+	ContextSourceFile ContextSourceKind = "file"
+	// ContextSourceConfig is used if the context was set through the
+	// "currentContext" field in the CLI configuration file.
+	ContextSourceConfig ContextSourceKind = "config"
+	// ContextSourceDefault is used if no context is configured.
+	ContextSourceDefault ContextSourceKind = "default"
+)
+
+// ContextSource describes how the current context was selected.
+type ContextSource struct {
+	Kind ContextSourceKind
+	// Path is the path of the [ContextFileName] file or the CLI configuration
+	// file that set the context, if any.
+	Path string
+}
+
+// String returns a human-readable description of the source.
+func (s ContextSource) String() string {
+	switch s.Kind {
+	case ContextSourceFlag:
+		return "--context command-line option"
+	case ContextSourceHostFlag:
+		return "--host command-line option (uses the default context)"
+	case ContextSourceHostEnv:
+		return "DOCKER_HOST environment variable (uses the default context)"
+	case ContextSourceEnv:
+		return EnvOverrideContext + " environment variable"
+	case ContextSourceFile:
+		return ContextFileName + " file (" + s.Path + ")"
+	case ContextSourceConfig:
+		if s.Path == "" {
+			return "currentContext in configuration file"
+		}
+		return "currentContext in configuration file (" + s.Path + ")"
+	default:
+		return "no context configured (uses the default context)"
+	}
+}
+
+// findContextFile looks for a [ContextFileName] file in dir and its parent
+// directories, and returns the context name it contains and its path. Files
+// that cannot be read, or do not contain a context name are skipped.
+func findContextFile(dir string) (name string, path string) {
+	for {
+		path = filepath.Join(dir, ContextFileName)
+		if name = readContextFile(path); name != "" {
+			return name, path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ""
+		}
+		dir = parent
+	}
+}
+
+// readContextFile returns the context name from the [ContextFileName] file
+// at path, or an empty string if the file does not exist, or does not
+// contain a context name.
+func readContextFile(path string) string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return line
+		}
+	}
+	return ""
+}
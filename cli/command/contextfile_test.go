@@ -0,0 +1,71 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	cliflags "github.com/docker/cli/cli/flags"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestFindContextFile(t *testing.T) {
+	root := t.TempDir()
+	project := filepath.Join(root, "project")
+	nested := filepath.Join(project, "empty", "nested")
+	assert.NilError(t, os.MkdirAll(nested, 0o755))
+	assert.NilError(t, os.WriteFile(filepath.Join(project, ContextFileName), []byte("# comment\n\n  my-context  \n"), 0o644))
+	assert.NilError(t, os.WriteFile(filepath.Join(project, "empty", ContextFileName), []byte("# no context here\n"), 0o644))
+
+	name, path := findContextFile(nested)
+	assert.Check(t, is.Equal(name, "my-context"))
+	assert.Check(t, is.Equal(path, filepath.Join(project, ContextFileName)))
+
+	name, path = findContextFile(root)
+	assert.Check(t, is.Equal(name, ""))
+	assert.Check(t, is.Equal(path, ""))
+}
+
+func TestResolveContext(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "")
+	t.Setenv(EnvOverrideContext, "")
+
+	dir := t.TempDir()
+	contextFile := filepath.Join(dir, ContextFileName)
+	assert.NilError(t, os.WriteFile(contextFile, []byte("from-file\n"), 0o644))
+	wd, err := os.Getwd()
+	assert.NilError(t, err)
+	assert.NilError(t, os.Chdir(dir))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	cfg := &configfile.ConfigFile{Filename: "/config.json", CurrentContext: "from-config"}
+
+	name, source := resolveContext(&cliflags.ClientOptions{Context: "from-flag"}, cfg)
+	assert.Check(t, is.Equal(name, "from-flag"))
+	assert.Check(t, is.Equal(source, ContextSource{Kind: ContextSourceFlag}))
+
+	name, source = resolveContext(&cliflags.ClientOptions{}, cfg)
+	assert.Check(t, is.Equal(name, "from-file"))
+	assert.Check(t, is.Equal(source.Kind, ContextSourceFile))
+	assert.Check(t, is.Equal(filepath.Base(source.Path), ContextFileName))
+
+	t.Setenv(EnvOverrideContext, "from-env")
+	name, source = resolveContext(&cliflags.ClientOptions{}, cfg)
+	assert.Check(t, is.Equal(name, "from-env"))
+	assert.Check(t, is.Equal(source, ContextSource{Kind: ContextSourceEnv}))
+
+	t.Setenv("DOCKER_HOST", "tcp://127.0.0.1:2375")
+	name, source = resolveContext(&cliflags.ClientOptions{}, cfg)
+	assert.Check(t, is.Equal(name, DefaultContextName))
+	assert.Check(t, is.Equal(source, ContextSource{Kind: ContextSourceHostEnv}))
+
+	t.Setenv("DOCKER_HOST", "")
+	t.Setenv(EnvOverrideContext, "")
+	assert.NilError(t, os.Remove(contextFile))
+	name, source = resolveContext(&cliflags.ClientOptions{}, cfg)
+	assert.Check(t, is.Equal(name, "from-config"))
+	assert.Check(t, is.Equal(source, ContextSource{Kind: ContextSourceConfig, Path: "/config.json"}))
+	assert.Check(t, is.Equal(source.String(), "currentContext in configuration file (/config.json)"))
+}
@@ -1,10 +1,19 @@
 package formatter
 
+import "time"
+
 const (
 	// ClientContextTableFormat is the default client context format.
 	ClientContextTableFormat = "table {{.Name}}{{if .Current}} *{{end}}\t{{.Description}}\t{{.DockerEndpoint}}\t{{.Error}}"
 
+	// ClientContextCheckTableFormat is the default client context format
+	// when checking the contexts' docker endpoints.
+	ClientContextCheckTableFormat = "table {{.Name}}{{if .Current}} *{{end}}\t{{.DockerEndpoint}}\t{{.Status}}\t{{.ServerVersion}}\t{{.APIVersion}}\t{{.Latency}}\t{{.Error}}"
+
 	dockerEndpointHeader = "DOCKER ENDPOINT"
+	serverVersionHeader  = "SERVER VERSION"
+	apiVersionHeader     = "API VERSION"
+	latencyHeader        = "LATENCY"
 	quietContextFormat   = "{{.Name}}"
 
 	maxErrLength = 45
@@ -21,6 +30,15 @@ func NewClientContextFormat(source string, quiet bool) Format {
 	return Format(source)
 }
 
+// NewClientContextCheckFormat returns a Format for rendering the results of
+// checking the contexts' docker endpoints.
+func NewClientContextCheckFormat(source string, quiet bool) Format {
+	if !quiet && source == TableFormatKey {
+		return ClientContextCheckTableFormat
+	}
+	return NewClientContextFormat(source, quiet)
+}
+
 // ClientContext is a context for display
 type ClientContext struct {
 	Name           string
@@ -28,6 +46,13 @@ type ClientContext struct {
 	DockerEndpoint string
 	Current        bool
 	Error          string
+
+	// Status, ServerVersion, APIVersion, and Latency are set when checking
+	// the context's docker endpoint.
+	Status        string
+	ServerVersion string
+	APIVersion    string
+	Latency       time.Duration
 }
 
 // ClientContextWrite writes formatted contexts using the Context
@@ -43,6 +68,20 @@ func ClientContextWrite(ctx Context, contexts []*ClientContext) error {
 	return ctx.Write(newClientContextContext(), render)
 }
 
+// ClientContextCheckWrite writes formatted contexts using the Context,
+// including the results of checking the contexts' docker endpoints.
+func ClientContextCheckWrite(ctx Context, contexts []*ClientContext) error {
+	render := func(format func(subContext SubContext) error) error {
+		for _, context := range contexts {
+			if err := format(&clientContextCheckContext{clientContextContext: &clientContextContext{c: context}}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return ctx.Write(newClientContextCheckContext(), render)
+}
+
 type clientContextContext struct {
 	HeaderContext
 	c *ClientContext
@@ -84,3 +123,52 @@ func (c *clientContextContext) Error() string {
 	// TODO(thaJeztah) add "--no-trunc" option to context ls and set default to 30 cols to match "docker service ps"
 	return Ellipsis(c.c.Error, maxErrLength)
 }
+
+type clientContextCheckContext struct {
+	*clientContextContext
+}
+
+func newClientContextCheckContext() *clientContextCheckContext {
+	ctx := clientContextCheckContext{clientContextContext: &clientContextContext{}}
+	ctx.Header = SubHeaderContext{
+		"Name":           NameHeader,
+		"Description":    DescriptionHeader,
+		"DockerEndpoint": dockerEndpointHeader,
+		"Status":         StatusHeader,
+		"ServerVersion":  serverVersionHeader,
+		"APIVersion":     apiVersionHeader,
+		"Latency":        latencyHeader,
+		"Error":          ErrorHeader,
+	}
+	return &ctx
+}
+
+func (c *clientContextCheckContext) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(c)
+}
+
+// Status returns the result of checking the context's docker endpoint.
+func (c *clientContextCheckContext) Status() string {
+	return c.c.Status
+}
+
+func (c *clientContextCheckContext) ServerVersion() string {
+	return c.c.ServerVersion
+}
+
+func (c *clientContextCheckContext) APIVersion() string {
+	return c.c.APIVersion
+}
+
+// Latency returns the round-trip time of pinging the context's docker
+// endpoint, rounded for presentation.
+func (c *clientContextCheckContext) Latency() string {
+	switch d := c.c.Latency; {
+	case d <= 0:
+		return ""
+	case d < time.Millisecond:
+		return d.Round(time.Microsecond).String()
+	default:
+		return d.Round(time.Millisecond).String()
+	}
+}
@@ -40,6 +40,10 @@ type DiskUsageContext struct {
 	Volumes     []*volume.Volume
 	BuildCache  []*build.CacheRecord
 	BuilderSize int64
+
+	// GroupBy groups the disk usage if set, instead of listing the disk
+	// usage of each object.
+	GroupBy *DiskUsageGroupBy
 }
 
 func (ctx *DiskUsageContext) startSubsection(format string) (*template.Template, error) {
@@ -84,6 +88,9 @@ func NewDiskUsageFormat(source string, verbose bool) Format {
 }
 
 func (ctx *DiskUsageContext) Write() (err error) {
+	if ctx.GroupBy != nil {
+		return ctx.groupedWrite()
+	}
 	if ctx.Verbose {
 		return ctx.verboseWrite()
 	}
@@ -0,0 +1,313 @@
+package formatter
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/docker/go-units"
+	"github.com/moby/moby/api/types/image"
+	"github.com/moby/moby/api/types/mount"
+)
+
+const (
+	defaultDiskUsageGroupTableFormat = "table {{.Group}}\t{{.Images}}\t{{.SharedSize}}\t{{.UniqueSize}}\t{{.Containers}}\t{{.ContainersSize}}\t{{.Volumes}}\t{{.VolumesSize}}\t{{.BuildCacheSize}}\t{{.TotalSize}}"
+
+	// noDiskUsageGroup is the group of objects that can't be attributed to
+	// a single group.
+	noDiskUsageGroup = "<none>"
+)
+
+// DiskUsageGroupBy describes how the disk usage is grouped.
+type DiskUsageGroupBy struct {
+	// Label is the key of the label to group objects by, such as
+	// "com.docker.compose.project". Objects are grouped by the repository
+	// of their image if no label is set.
+	Label string
+}
+
+// ParseDiskUsageGroupBy parses the value of the --group-by option, which is
+// either "label=<key>" or "repository".
+func ParseDiskUsageGroupBy(value string) (*DiskUsageGroupBy, error) {
+	if value == "repository" {
+		return &DiskUsageGroupBy{}, nil
+	}
+	if key, ok := strings.CutPrefix(value, "label="); ok {
+		if key == "" {
+			return nil, errors.New("invalid group: label key cannot be empty")
+		}
+		return &DiskUsageGroupBy{Label: key}, nil
+	}
+	return nil, errors.New(`invalid group: must be "label=<key>" or "repository"`)
+}
+
+// diskUsageGroup is the disk usage of the objects of a group. Sizes are in
+// bytes.
+type diskUsageGroup struct {
+	name           string
+	images         int
+	size           int64
+	sharedSize     int64
+	containers     int
+	containersSize int64
+	volumes        []string
+	volumesSize    int64
+	buildCache     int
+	buildCacheSize int64
+}
+
+func (g *diskUsageGroup) uniqueSize() int64 {
+	return g.size - g.sharedSize
+}
+
+// total returns the disk space that is only used by the group. The size of
+// image layers that are shared with other images is not included, even if
+// those images are in the same group: the daemon reports how much of an
+// image is shared, but not with which images, so shared layers can't be
+// attributed to a group.
+func (g *diskUsageGroup) total() int64 {
+	return g.uniqueSize() + g.containersSize + g.volumesSize + g.buildCacheSize
+}
+
+// groups returns the disk usage of each group, ordered by total size.
+//
+// Images, containers, and volumes are attributed to a group by their label
+// when grouping by label. When grouping by repository, images are attributed
+// to their repository, and containers to the repository of their image.
+// Images and volumes that are not attributed to a group themselves are
+// attributed to the group of the containers that use them, if all of those
+// containers belong to the same group. Objects that can't be attributed,
+// including build cache, are in the "<none>" group.
+func (ctx *DiskUsageContext) groups() []*diskUsageGroup {
+	groups := map[string]*diskUsageGroup{}
+	group := func(name string) *diskUsageGroup {
+		if name == "" {
+			name = noDiskUsageGroup
+		}
+		g, ok := groups[name]
+		if !ok {
+			g = &diskUsageGroup{name: name}
+			groups[name] = g
+		}
+		return g
+	}
+	byLabel := func(labels map[string]string) string {
+		return labels[ctx.GroupBy.Label]
+	}
+
+	images := make(map[string]*image.Summary, len(ctx.Images))
+	for _, img := range ctx.Images {
+		images[img.ID] = img
+	}
+
+	// The groups of the containers that use an image or a volume.
+	imageUsers := map[string]map[string]struct{}{}
+	volumeUsers := map[string]map[string]struct{}{}
+	addUser := func(users map[string]map[string]struct{}, key, name string) {
+		if users[key] == nil {
+			users[key] = map[string]struct{}{}
+		}
+		users[key][name] = struct{}{}
+	}
+	onlyUser := func(users map[string]struct{}) string {
+		if len(users) != 1 {
+			return ""
+		}
+		for name := range users {
+			return name
+		}
+		return ""
+	}
+
+	for _, c := range ctx.Containers {
+		var name string
+		if ctx.GroupBy.Label != "" {
+			name = byLabel(c.Labels)
+		} else if img, ok := images[c.ImageID]; ok {
+			name = imageRepository(img)
+		} else if ref, err := reference.ParseNormalizedNamed(c.Image); err == nil {
+			name = reference.FamiliarName(ref)
+		}
+		g := group(name)
+		g.containers++
+		g.containersSize += c.SizeRw
+
+		addUser(imageUsers, c.ImageID, g.name)
+		for _, m := range c.Mounts {
+			if m.Type == mount.TypeVolume {
+				addUser(volumeUsers, m.Name, g.name)
+			}
+		}
+	}
+
+	for _, img := range ctx.Images {
+		var name string
+		if ctx.GroupBy.Label != "" {
+			name = byLabel(img.Labels)
+		} else {
+			name = imageRepository(img)
+		}
+		if name == "" {
+			name = onlyUser(imageUsers[img.ID])
+		}
+		g := group(name)
+		g.images++
+		if img.Size != -1 {
+			g.size += img.Size
+		}
+		if img.SharedSize != -1 {
+			g.sharedSize += img.SharedSize
+		}
+	}
+
+	for _, v := range ctx.Volumes {
+		var name string
+		if ctx.GroupBy.Label != "" {
+			name = byLabel(v.Labels)
+		}
+		if name == "" {
+			name = onlyUser(volumeUsers[v.Name])
+		}
+		g := group(name)
+		g.volumes = append(g.volumes, v.Name)
+		if v.UsageData != nil && v.UsageData.Size != -1 {
+			g.volumesSize += v.UsageData.Size
+		}
+	}
+
+	// The daemon does not relate build cache to images or containers, so
+	// build cache can't be attributed to a group.
+	for _, bc := range ctx.BuildCache {
+		g := group(noDiskUsageGroup)
+		g.buildCache++
+		if !bc.Shared {
+			g.buildCacheSize += bc.Size
+		}
+	}
+
+	sorted := make([]*diskUsageGroup, 0, len(groups))
+	for _, g := range groups {
+		sort.Strings(g.volumes)
+		sorted = append(sorted, g)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if (a.name == noDiskUsageGroup) != (b.name == noDiskUsageGroup) {
+			return b.name == noDiskUsageGroup
+		}
+		if a.total() != b.total() {
+			return a.total() > b.total()
+		}
+		return a.name < b.name
+	})
+	return sorted
+}
+
+// imageRepository returns the repository of the first tag of an image, or
+// an empty string if the image is dangling.
+func imageRepository(img *image.Summary) string {
+	if len(img.RepoTags) == 0 || isDangling(*img) {
+		return ""
+	}
+	ref, err := reference.ParseNormalizedNamed(img.RepoTags[0])
+	if err != nil {
+		return ""
+	}
+	return reference.FamiliarName(ref)
+}
+
+// groupedWrite writes the disk usage of each group.
+func (ctx *DiskUsageContext) groupedWrite() error {
+	if ctx.Format == TableFormatKey {
+		ctx.Format = defaultDiskUsageGroupTableFormat
+	}
+	groups := ctx.groups()
+	render := func(format func(subContext SubContext) error) error {
+		for _, g := range groups {
+			if err := format(&diskUsageGroupContext{g: g}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	groupCtx := diskUsageGroupContext{}
+	groupCtx.Header = SubHeaderContext{
+		"Group":          "GROUP",
+		"Images":         "IMAGES",
+		"Size":           SizeHeader,
+		"SharedSize":     sharedSizeHeader,
+		"UniqueSize":     uniqueSizeHeader,
+		"Containers":     containersHeader,
+		"ContainersSize": "CONTAINERS SIZE",
+		"Volumes":        "VOLUMES",
+		"VolumeNames":    "VOLUME NAMES",
+		"VolumesSize":    "VOLUMES SIZE",
+		"BuildCache":     "BUILD CACHE",
+		"BuildCacheSize": "BUILD CACHE SIZE",
+		"TotalSize":      "UNIQUE TOTAL",
+	}
+	return ctx.Context.Write(&groupCtx, render)
+}
+
+type diskUsageGroupContext struct {
+	HeaderContext
+	g *diskUsageGroup
+}
+
+func (c *diskUsageGroupContext) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(c)
+}
+
+func (c *diskUsageGroupContext) Group() string {
+	return c.g.name
+}
+
+func (c *diskUsageGroupContext) Images() string {
+	return strconv.Itoa(c.g.images)
+}
+
+func (c *diskUsageGroupContext) Size() string {
+	return units.HumanSize(float64(c.g.size))
+}
+
+func (c *diskUsageGroupContext) SharedSize() string {
+	return units.HumanSize(float64(c.g.sharedSize))
+}
+
+func (c *diskUsageGroupContext) UniqueSize() string {
+	return units.HumanSize(float64(c.g.uniqueSize()))
+}
+
+func (c *diskUsageGroupContext) Containers() string {
+	return strconv.Itoa(c.g.containers)
+}
+
+func (c *diskUsageGroupContext) ContainersSize() string {
+	return units.HumanSize(float64(c.g.containersSize))
+}
+
+func (c *diskUsageGroupContext) Volumes() string {
+	return strconv.Itoa(len(c.g.volumes))
+}
+
+func (c *diskUsageGroupContext) VolumeNames() string {
+	return strings.Join(c.g.volumes, ",")
+}
+
+func (c *diskUsageGroupContext) VolumesSize() string {
+	return units.HumanSize(float64(c.g.volumesSize))
+}
+
+func (c *diskUsageGroupContext) BuildCache() string {
+	return strconv.Itoa(c.g.buildCache)
+}
+
+func (c *diskUsageGroupContext) BuildCacheSize() string {
+	return units.HumanSize(float64(c.g.buildCacheSize))
+}
+
+func (c *diskUsageGroupContext) TotalSize() string {
+	return units.HumanSize(float64(c.g.total()))
+}
@@ -4,7 +4,13 @@
 	"bytes"
 	"testing"
 
+	"github.com/moby/moby/api/types/build"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/image"
+	"github.com/moby/moby/api/types/mount"
+	"github.com/moby/moby/api/types/volume"
 	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 	"gotest.tools/v3/golden"
 )
 
@@ -117,3 +123,103 @@ func TestDiskUsageContextFormatWrite(t *testing.T) {
 		})
 	}
 }
+
+func diskUsageGroupTestContext(format string, groupBy *DiskUsageGroupBy) DiskUsageContext {
+	compose := func(project string) map[string]string {
+		return map[string]string{"com.docker.compose.project": project}
+	}
+	return DiskUsageContext{
+		Context: Context{Format: Format(format)},
+		Verbose: true,
+		GroupBy: groupBy,
+		Images: []*image.Summary{
+			{ID: "sha256:web", RepoTags: []string{"shop/web:1.0"}, Size: 300e6, SharedSize: 100e6, Labels: compose("shop")},
+			{ID: "sha256:web-old", RepoTags: []string{"shop/web:0.9"}, Size: 280e6, SharedSize: 100e6, Labels: compose("shop")},
+			{ID: "sha256:postgres", RepoTags: []string{"postgres:16"}, Size: 400e6, SharedSize: 100e6},
+			{ID: "sha256:redis", RepoTags: []string{"redis:7"}, Size: 100e6},
+			{ID: "sha256:dangling", RepoTags: []string{"<none>:<none>"}, Size: 50e6},
+		},
+		Containers: []*container.Summary{
+			{ID: "c1", Image: "shop/web:1.0", ImageID: "sha256:web", SizeRw: 1e6, Labels: compose("shop")},
+			{
+				ID: "c2", Image: "postgres:16", ImageID: "sha256:postgres", SizeRw: 2e6, Labels: compose("shop"),
+				Mounts: []container.MountPoint{{Type: mount.TypeVolume, Name: "shop_db"}, {Type: mount.TypeVolume, Name: "shared"}},
+			},
+			{
+				ID: "c3", Image: "postgres:16", ImageID: "sha256:postgres", SizeRw: 3e6, Labels: compose("blog"),
+				Mounts: []container.MountPoint{{Type: mount.TypeVolume, Name: "blog_db"}, {Type: mount.TypeVolume, Name: "shared"}},
+			},
+			{ID: "c4", Image: "redis:7", ImageID: "sha256:redis", SizeRw: 4e6, Labels: compose("blog")},
+		},
+		Volumes: []*volume.Volume{
+			{Name: "shop_db", Labels: compose("shop"), UsageData: &volume.UsageData{Size: 700e6}},
+			{Name: "blog_db", UsageData: &volume.UsageData{Size: 200e6}},
+			{Name: "shared", UsageData: &volume.UsageData{Size: 10e6}},
+		},
+		BuildCache: []*build.CacheRecord{
+			{ID: "cache1", Size: 500e6},
+			{ID: "cache2", Size: 100e6, Shared: true},
+		},
+	}
+}
+
+func TestDiskUsageContextGroupedWrite(t *testing.T) {
+	tests := []struct {
+		doc      string
+		format   string
+		groupBy  *DiskUsageGroupBy
+		expected string
+	}{
+		{
+			doc:     "label",
+			format:  "table",
+			groupBy: &DiskUsageGroupBy{Label: "com.docker.compose.project"},
+			expected: `GROUP     IMAGES    SHARED SIZE   UNIQUE SIZE   CONTAINERS   CONTAINERS SIZE   VOLUMES   VOLUMES SIZE   BUILD CACHE SIZE   UNIQUE TOTAL
+shop      2         200MB         380MB         2            3MB               1         700MB          0B                 1.083GB
+blog      1         0B            100MB         2            7MB               1         200MB          0B                 307MB
+<none>    2         100MB         350MB         0            0B                1         10MB           500MB              860MB
+`,
+		},
+		{
+			doc:     "repository",
+			format:  "table {{.Group}}\t{{.Images}}\t{{.Containers}}\t{{.VolumeNames}}\t{{.TotalSize}}",
+			groupBy: &DiskUsageGroupBy{},
+			expected: `GROUP      IMAGES    CONTAINERS   VOLUME NAMES             UNIQUE TOTAL
+postgres   1         2            blog_db,shared,shop_db   1.215GB
+shop/web   2         1                                     381MB
+redis      1         1                                     104MB
+<none>     1         0                                     550MB
+`,
+		},
+		{
+			doc:      "json",
+			format:   "json",
+			groupBy:  &DiskUsageGroupBy{Label: "tier"},
+			expected: `{"BuildCache":"2","BuildCacheSize":"500MB","Containers":"4","ContainersSize":"10MB","Group":"\u003cnone\u003e","Images":"5","SharedSize":"300MB","Size":"1.13GB","TotalSize":"2.25GB","UniqueSize":"830MB","VolumeNames":"blog_db,shared,shop_db","Volumes":"3","VolumesSize":"910MB"}` + "\n",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			var out bytes.Buffer
+			ctx := diskUsageGroupTestContext(tc.format, tc.groupBy)
+			ctx.Output = &out
+			assert.NilError(t, ctx.Write())
+			assert.Check(t, is.Equal(out.String(), tc.expected))
+		})
+	}
+}
+
+func TestParseDiskUsageGroupBy(t *testing.T) {
+	groupBy, err := ParseDiskUsageGroupBy("label=com.docker.compose.project")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(groupBy, &DiskUsageGroupBy{Label: "com.docker.compose.project"}))
+
+	groupBy, err = ParseDiskUsageGroupBy("repository")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(groupBy, &DiskUsageGroupBy{}))
+
+	for _, value := range []string{"label=", "label", "tag", ""} {
+		_, err = ParseDiskUsageGroupBy(value)
+		assert.Check(t, err != nil, value)
+	}
+}
@@ -27,6 +27,7 @@ type fakeClient struct {
 	imageImportFunc  func(source client.ImageImportSource, ref string, options client.ImageImportOptions) (io.ReadCloser, error)
 	imageHistoryFunc func(img string, options ...client.ImageHistoryOption) ([]image.HistoryResponseItem, error)
 	imageBuildFunc   func(context.Context, io.Reader, client.ImageBuildOptions) (client.ImageBuildResponse, error)
+	diskUsageFunc    func(options client.DiskUsageOptions) (system.DiskUsage, error)
 }
 
 func (cli *fakeClient) ImageTag(_ context.Context, img, ref string) error {
@@ -123,3 +124,10 @@ func (cli *fakeClient) ImageBuild(ctx context.Context, buildContext io.Reader, o
 	}
 	return client.ImageBuildResponse{Body: io.NopCloser(strings.NewReader(""))}, nil
 }
+
+func (cli *fakeClient) DiskUsage(_ context.Context, options client.DiskUsageOptions) (system.DiskUsage, error) {
+	if cli.diskUsageFunc != nil {
+		return cli.diskUsageFunc(options)
+	}
+	return system.DiskUsage{}, nil
+}
@@ -27,6 +27,7 @@ type imagesOptions struct {
 	filter      opts.FilterOpt
 	calledAs    string
 	tree        bool
+	interactive bool
 }
 
 // newImagesCommand creates a new `docker images` command
@@ -67,6 +68,10 @@ func newImagesCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.SetAnnotation("tree", "version", []string{"1.47"})
 	flags.SetAnnotation("tree", "experimentalCLI", nil)
 
+	flags.BoolVar(&options.interactive, "interactive", false, "Browse the tree in an interactive terminal UI (requires --tree) (EXPERIMENTAL)")
+	flags.SetAnnotation("interactive", "version", []string{"1.47"})
+	flags.SetAnnotation("interactive", "experimentalCLI", nil)
+
 	return cmd
 }
 
@@ -83,6 +88,9 @@ func runImages(ctx context.Context, dockerCLI command.Cli, options imagesOptions
 		filters.Add("reference", options.matchName)
 	}
 
+	if options.interactive && !options.tree {
+		return errors.New("--interactive can only be used with --tree")
+	}
 	if options.tree {
 		if options.quiet {
 			return errors.New("--quiet is not yet supported with --tree")
@@ -93,13 +101,15 @@ func runImages(ctx context.Context, dockerCLI command.Cli, options imagesOptions
 		if options.showDigests {
 			return errors.New("--show-digest is not yet supported with --tree")
 		}
-		if options.format != "" {
-			return errors.New("--format is not yet supported with --tree")
+		if options.interactive && options.format != "" {
+			return errors.New("--interactive can't be used with --format")
 		}
 
 		return runTree(ctx, dockerCLI, treeOptions{
-			all:     options.all,
-			filters: filters,
+			all:         options.all,
+			filters:     filters,
+			interactive: options.interactive,
+			format:      options.format,
 		})
 	}
 
@@ -13,6 +13,7 @@
 	"github.com/docker/cli/internal/prompt"
 	"github.com/docker/cli/opts"
 	"github.com/docker/go-units"
+	"github.com/moby/moby/api/types/image"
 	"github.com/spf13/cobra"
 )
 
@@ -24,9 +25,12 @@ func init() {
 }
 
 type pruneOptions struct {
-	force  bool
-	all    bool
-	filter opts.FilterOpt
+	force         bool
+	all           bool
+	filter        opts.FilterOpt
+	retentionOpts pruner.RetentionOptions
+	retention     pruner.RetentionPolicy
+	onDeleted     func(id string)
 }
 
 // newPruneCommand returns a new cobra prune command for images
@@ -38,6 +42,14 @@ func newPruneCommand(dockerCLI command.Cli) *cobra.Command {
 		Short: "Remove unused images",
 		Args:  cli.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			options.retention, err = options.retentionOpts.Policy(dockerCLI.ConfigFile())
+			if err != nil {
+				return err
+			}
+			if notice := options.retention.ConfigNotice(); notice != "" {
+				_, _ = fmt.Fprintln(dockerCLI.Err(), notice)
+			}
 			spaceReclaimed, output, err := runPrune(cmd.Context(), dockerCLI, options)
 			if err != nil {
 				return err
@@ -57,6 +69,7 @@ func newPruneCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.BoolVarP(&options.force, "force", "f", false, "Do not prompt for confirmation")
 	flags.BoolVarP(&options.all, "all", "a", false, "Remove all unused images, not just dangling ones")
 	flags.Var(&options.filter, "filter", `Provide filter values (e.g. "until=<timestamp>")`)
+	options.retentionOpts.InstallFlags(flags)
 
 	return cmd
 }
@@ -65,6 +78,8 @@ func newPruneCommand(dockerCLI command.Cli) *cobra.Command {
 	allImageWarning = `WARNING! This will remove all images without at least one container associated to them.
 Are you sure you want to continue?`
 	danglingWarning = `WARNING! This will remove all dangling images.
+Are you sure you want to continue?`
+	retentionWarning = `WARNING! This will remove %s, %s.
 Are you sure you want to continue?`
 )
 
@@ -77,6 +92,9 @@ func runPrune(ctx context.Context, dockerCli command.Cli, options pruneOptions)
 	if options.all {
 		warning = allImageWarning
 	}
+	if options.retention.IsSet() {
+		warning = fmt.Sprintf(retentionWarning, pruneDescription(options.all), options.retention)
+	}
 	if !options.force {
 		r, err := prompt.Confirm(ctx, dockerCli.In(), dockerCli.Out(), warning)
 		if err != nil {
@@ -87,7 +105,12 @@ func runPrune(ctx context.Context, dockerCli command.Cli, options pruneOptions)
 		}
 	}
 
-	report, err := dockerCli.Client().ImagesPrune(ctx, pruneFilters)
+	var report image.PruneReport
+	if options.retention.IsSet() {
+		report, err = pruneWithRetention(ctx, dockerCli, pruneFilters, options.all, options.retention)
+	} else {
+		report, err = dockerCli.Client().ImagesPrune(ctx, pruneFilters)
+	}
 	if err != nil {
 		return 0, "", err
 	}
@@ -104,6 +127,9 @@ func runPrune(ctx context.Context, dockerCli command.Cli, options pruneOptions)
 				sb.WriteString("deleted: ")
 				sb.WriteString(st.Deleted)
 				sb.WriteByte('\n')
+				if options.onDeleted != nil {
+					options.onDeleted(st.Deleted)
+				}
 			}
 		}
 		output = sb.String()
@@ -122,17 +148,25 @@ func (cancelledErr) Cancelled() {}
 func pruneFn(ctx context.Context, dockerCLI command.Cli, options pruner.PruneOptions) (uint64, string, error) {
 	if !options.Confirmed {
 		// Dry-run: perform validation and produce confirmation before pruning.
-		var confirmMsg string
-		if options.All {
-			confirmMsg = "all images without at least one container associated to them"
-		} else {
-			confirmMsg = "all dangling images"
+		confirmMsg := pruneDescription(options.All)
+		if options.Retention.IsSet() {
+			confirmMsg += ", " + options.Retention.String()
 		}
 		return 0, confirmMsg, cancelledErr{errors.New("image prune has been cancelled")}
 	}
 	return runPrune(ctx, dockerCLI, pruneOptions{
-		force:  true,
-		all:    options.All,
-		filter: options.Filter,
+		force:     true,
+		all:       options.All,
+		filter:    options.Filter,
+		retention: options.Retention,
+		onDeleted: options.OnDeleted,
 	})
 }
+
+// pruneDescription returns a short description of the images to prune.
+func pruneDescription(all bool) string {
+	if all {
+		return "all images without at least one container associated to them"
+	}
+	return "all dangling images"
+}
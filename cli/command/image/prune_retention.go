@@ -0,0 +1,265 @@
+package image
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/system/pruner"
+	"github.com/docker/cli/internal/timestamp"
+	"github.com/moby/moby/api/types/filters"
+	"github.com/moby/moby/api/types/image"
+	"github.com/moby/moby/api/types/system"
+	"github.com/moby/moby/client"
+	"github.com/sirupsen/logrus"
+)
+
+// acceptedPruneFilters are the filters that the daemon accepts when pruning
+// images.
+var acceptedPruneFilters = map[string]bool{
+	"dangling": true,
+	"label":    true,
+	"label!":   true,
+	"until":    true,
+}
+
+// pruneWithRetention prunes images matching the given filters client-side,
+// keeping the images selected by the given retention policy. It produces a
+// report similar to the daemon's image prune endpoint.
+func pruneWithRetention(ctx context.Context, dockerCLI command.Cli, pruneFilters filters.Args, all bool, policy pruner.RetentionPolicy) (image.PruneReport, error) {
+	now := time.Now()
+	if err := pruneFilters.Validate(acceptedPruneFilters); err != nil {
+		return image.PruneReport{}, err
+	}
+	match, err := newPruneFilter(pruneFilters, now)
+	if err != nil {
+		return image.PruneReport{}, err
+	}
+
+	// The "label!" and "until" filters are only accepted when pruning,
+	// and are applied to the listed images instead.
+	listFilters := pruneFilters.Clone()
+	for _, name := range []string{"label!", "until"} {
+		for _, v := range listFilters.Get(name) {
+			listFilters.Del(name, v)
+		}
+	}
+	if all {
+		// The "dangling=false" filter means "all images" when pruning,
+		// but "only tagged images" when listing images.
+		listFilters.Del("dangling", "false")
+	}
+	list, err := dockerCLI.Client().ImageList(ctx, client.ImageListOptions{Filters: listFilters})
+	if err != nil {
+		return image.PruneReport{}, err
+	}
+	candidates := make([]image.Summary, 0, len(list))
+	for _, img := range list {
+		if match(img) {
+			candidates = append(candidates, img)
+		}
+	}
+	du, err := dockerCLI.Client().DiskUsage(ctx, client.DiskUsageOptions{
+		Types: []system.DiskUsageObject{system.ImageObject, system.ContainerObject},
+	})
+	if err != nil {
+		return image.PruneReport{}, err
+	}
+
+	var report image.PruneReport
+	for _, img := range selectImagesToPrune(candidates, du, policy, now) {
+		deleted, err := removeImage(ctx, dockerCLI.Client(), img)
+		report.ImagesDeleted = append(report.ImagesDeleted, deleted...)
+		if err != nil {
+			// Like the daemon's prune endpoint, skip images that could not
+			// be removed; for example, because a container was created from
+			// the image after selecting the images to prune.
+			logrus.Debugf("failed to prune image %s: %v", img.ID, err)
+		}
+		for _, d := range deleted {
+			if d.Deleted == img.ID {
+				report.SpaceReclaimed += uint64(uniqueSize(img))
+				break
+			}
+		}
+	}
+	return report, nil
+}
+
+// newPruneFilter returns a function that matches images against the
+// "label!" and "until" filters, which are not accepted when listing images.
+func newPruneFilter(pruneFilters filters.Args, now time.Time) (func(image.Summary) bool, error) {
+	var until time.Time
+	if values := pruneFilters.Get("until"); len(values) > 1 {
+		return nil, errors.New("more than one until filter specified")
+	} else if len(values) == 1 {
+		var err error
+		if until, err = timestamp.Parse(values[0], now); err != nil {
+			return nil, fmt.Errorf("invalid until filter: %w", err)
+		}
+	}
+	return func(img image.Summary) bool {
+		if !until.IsZero() && img.Created >= until.Unix() {
+			return false
+		}
+		if pruneFilters.Contains("label!") && pruneFilters.MatchKVList("label!", img.Labels) {
+			return false
+		}
+		return true
+	}, nil
+}
+
+// removeImage removes an image without forcing its removal, so that an image
+// that is used by a container is never removed. An image that is tagged in
+// multiple repositories can't be removed by ID without forcing, so its tags
+// are removed instead, which removes the image along with its last tag.
+func removeImage(ctx context.Context, apiClient client.ImageAPIClient, img image.Summary) ([]image.DeleteResponse, error) {
+	if len(repositories(img)) <= 1 {
+		return apiClient.ImageRemove(ctx, img.ID, client.ImageRemoveOptions{PruneChildren: true})
+	}
+	var deleted []image.DeleteResponse
+	for _, tag := range img.RepoTags {
+		if tag == "<none>:<none>" {
+			continue
+		}
+		resp, err := apiClient.ImageRemove(ctx, tag, client.ImageRemoveOptions{PruneChildren: true})
+		deleted = append(deleted, resp...)
+		if err != nil {
+			return deleted, err
+		}
+	}
+	return deleted, nil
+}
+
+// selectImagesToPrune returns the candidates to remove (oldest first) after
+// applying the retention policy. Images that are used by a container are
+// never selected. The disk usage is used to determine the newest images of
+// each repository, when images were last used, and the total amount of disk
+// space used by images.
+func selectImagesToPrune(candidates []image.Summary, du system.DiskUsage, policy pruner.RetentionPolicy, now time.Time) []image.Summary {
+	images := make(map[string]image.Summary, len(du.Images))
+	for _, img := range du.Images {
+		if img != nil {
+			images[img.ID] = *img
+		}
+	}
+
+	// lastUsed holds the most recent time an image was created or used
+	// by a container.
+	lastUsed := make(map[string]int64, len(images))
+	for id, img := range images {
+		lastUsed[id] = img.Created
+	}
+	inUse := make(map[string]bool)
+	for _, c := range du.Containers {
+		if c == nil {
+			continue
+		}
+		inUse[c.ImageID] = true
+		if c.Created > lastUsed[c.ImageID] {
+			lastUsed[c.ImageID] = c.Created
+		}
+	}
+
+	keep := make(map[string]bool)
+	if policy.KeepLast > 0 {
+		for _, ids := range imagesByRepository(images) {
+			for i, id := range ids {
+				if i >= policy.KeepLast {
+					break
+				}
+				keep[id] = true
+			}
+		}
+	}
+	if policy.KeepUsedWithin > 0 {
+		threshold := now.Add(-policy.KeepUsedWithin).Unix()
+		for id, img := range images {
+			if len(repositories(img)) > 0 && lastUsed[id] >= threshold {
+				keep[id] = true
+			}
+		}
+	}
+
+	var selected []image.Summary
+	for _, img := range candidates {
+		if keep[img.ID] || inUse[img.ID] || img.Containers > 0 || images[img.ID].Containers > 0 {
+			continue
+		}
+		if duImg, ok := images[img.ID]; ok {
+			// Prefer the disk usage information, which includes the
+			// shared size of the image.
+			img = duImg
+		}
+		selected = append(selected, img)
+	}
+	sort.SliceStable(selected, func(i, j int) bool {
+		return selected[i].Created < selected[j].Created
+	})
+
+	if policy.KeepStorage > 0 {
+		usage := du.LayersSize
+		var n int
+		for n < len(selected) && usage > policy.KeepStorage {
+			usage -= uniqueSize(selected[n])
+			n++
+		}
+		selected = selected[:n]
+	}
+	return selected
+}
+
+// imagesByRepository returns the IDs of the given images for each
+// repository, sorted by creation date (newest first).
+func imagesByRepository(images map[string]image.Summary) map[string][]string {
+	byRepo := make(map[string][]string)
+	for id, img := range images {
+		for _, repo := range repositories(img) {
+			byRepo[repo] = append(byRepo[repo], id)
+		}
+	}
+	for _, ids := range byRepo {
+		sort.Slice(ids, func(i, j int) bool {
+			if images[ids[i]].Created == images[ids[j]].Created {
+				return ids[i] < ids[j]
+			}
+			return images[ids[i]].Created > images[ids[j]].Created
+		})
+	}
+	return byRepo
+}
+
+// repositories returns the (familiar) names of the repositories an image
+// is tagged in.
+func repositories(img image.Summary) []string {
+	var repos []string
+	seen := make(map[string]bool)
+	for _, tag := range img.RepoTags {
+		if tag == "<none>:<none>" {
+			continue
+		}
+		ref, err := reference.ParseNormalizedNamed(tag)
+		if err != nil {
+			continue
+		}
+		name := reference.FamiliarName(ref)
+		if !seen[name] {
+			seen[name] = true
+			repos = append(repos, name)
+		}
+	}
+	return repos
+}
+
+// uniqueSize returns the amount of disk space that is used by the image
+// only, and would be reclaimed when removing it.
+func uniqueSize(img image.Summary) int64 {
+	if img.SharedSize > 0 && img.SharedSize <= img.Size {
+		return img.Size - img.SharedSize
+	}
+	return img.Size
+}
@@ -7,11 +7,18 @@
 	"io"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/containerd/errdefs"
+	"github.com/docker/cli/cli/command/system/pruner"
+	"github.com/docker/cli/cli/config/configfile"
 	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/internal/test"
+	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/api/types/filters"
 	"github.com/moby/moby/api/types/image"
+	"github.com/moby/moby/api/types/system"
+	"github.com/moby/moby/client"
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
 	"gotest.tools/v3/golden"
@@ -127,3 +134,181 @@ func TestPrunePromptTermination(t *testing.T) {
 	cmd.SetErr(io.Discard)
 	test.TerminatePrompt(ctx, t, cmd, cli)
 }
+
+func TestSelectImagesToPrune(t *testing.T) {
+	now := time.Unix(1_000_000, 0)
+	day := int64(24 * time.Hour / time.Second)
+	images := []*image.Summary{
+		{ID: "app-v1", RepoTags: []string{"app:v1"}, Created: now.Unix() - 30*day, Size: 10},
+		{ID: "app-v2", RepoTags: []string{"app:v2"}, Created: now.Unix() - 20*day, Size: 10},
+		{ID: "app-v3", RepoTags: []string{"app:v3"}, Created: now.Unix() - 10*day, Size: 10},
+		{ID: "db-v1", RepoTags: []string{"db:v1"}, Created: now.Unix() - 40*day, Size: 20},
+		{ID: "used", RepoTags: []string{"tool:latest"}, Created: now.Unix() - 50*day, Size: 5},
+		{ID: "dangling", RepoTags: []string{"<none>:<none>"}, Created: now.Unix() - 60*day, Size: 5},
+	}
+	du := system.DiskUsage{
+		LayersSize: 60,
+		Images:     images,
+		Containers: []*container.Summary{
+			{ImageID: "db-v1", Created: now.Unix() - 2*day},
+		},
+	}
+	var candidates []image.Summary
+	for _, img := range images {
+		candidates = append(candidates, *img)
+	}
+
+	testCases := []struct {
+		doc      string
+		policy   pruner.RetentionPolicy
+		expected []string
+	}{
+		{
+			doc:      "no retention",
+			expected: []string{"dangling", "used", "app-v1", "app-v2", "app-v3"},
+		},
+		{
+			doc:      "keep last",
+			policy:   pruner.RetentionPolicy{KeepLast: 2},
+			expected: []string{"dangling", "app-v1"},
+		},
+		{
+			doc:      "keep used within",
+			policy:   pruner.RetentionPolicy{KeepUsedWithin: 15 * 24 * time.Hour},
+			expected: []string{"dangling", "used", "app-v1", "app-v2"},
+		},
+		{
+			doc:      "keep storage",
+			policy:   pruner.RetentionPolicy{KeepStorage: 45},
+			expected: []string{"dangling", "used", "app-v1"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.doc, func(t *testing.T) {
+			var actual []string
+			for _, img := range selectImagesToPrune(candidates, du, tc.policy, now) {
+				actual = append(actual, img.ID)
+			}
+			assert.Check(t, is.DeepEqual(tc.expected, actual))
+		})
+	}
+}
+
+func TestPruneWithRetentionFromConfig(t *testing.T) {
+	var removed []string
+	cli := test.NewFakeCli(&fakeClient{
+		imagesPruneFunc: func(pruneFilter filters.Args) (image.PruneReport, error) {
+			return image.PruneReport{}, errors.New("fakeClient imagesPruneFunc should not be called")
+		},
+		imageListFunc: func(options client.ImageListOptions) ([]image.Summary, error) {
+			assert.Check(t, !options.Filters.Contains("dangling"))
+			return []image.Summary{
+				{ID: "app-v1", RepoTags: []string{"app:v1"}, Created: 1},
+				{ID: "app-v2", RepoTags: []string{"app:v2"}, Created: 2},
+			}, nil
+		},
+		diskUsageFunc: func(options client.DiskUsageOptions) (system.DiskUsage, error) {
+			return system.DiskUsage{
+				Images: []*image.Summary{
+					{ID: "app-v1", RepoTags: []string{"app:v1"}, Created: 1, Size: 2048},
+					{ID: "app-v2", RepoTags: []string{"app:v2"}, Created: 2, Size: 1024},
+				},
+			}, nil
+		},
+		imageRemoveFunc: func(img string, options client.ImageRemoveOptions) ([]image.DeleteResponse, error) {
+			assert.Check(t, !options.Force)
+			removed = append(removed, img)
+			return []image.DeleteResponse{{Untagged: "app:v1"}, {Deleted: img}}, nil
+		},
+	})
+	cli.SetConfigFile(&configfile.ConfigFile{
+		PruneRetention: &configfile.PruneRetention{KeepLast: 1},
+	})
+	cmd := newPruneCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--all", "--force"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.DeepEqual([]string{"app-v1"}, removed))
+	expected := `Deleted Images:
+untagged: app:v1
+deleted: app-v1
+
+Total reclaimed space: 2.048kB
+`
+	assert.Check(t, is.Equal(expected, cli.OutBuffer().String()))
+	assert.Check(t, is.Equal(`NOTE: applying the "pruneRetention" policy from the configuration file: keeping the 1 most recent images per repository`+"\n", cli.ErrBuffer().String()))
+}
+
+func TestPruneWithRetentionFilters(t *testing.T) {
+	now := time.Now()
+	var removed []string
+	cli := test.NewFakeCli(&fakeClient{
+		imageListFunc: func(options client.ImageListOptions) ([]image.Summary, error) {
+			assert.Check(t, !options.Filters.Contains("label!"))
+			assert.Check(t, !options.Filters.Contains("until"))
+			return []image.Summary{
+				{ID: "old", Created: now.Add(-48 * time.Hour).Unix()},
+				{ID: "keep-label", Created: now.Add(-48 * time.Hour).Unix(), Labels: map[string]string{"keep": "true"}},
+				{ID: "in-use", Created: now.Add(-48 * time.Hour).Unix()},
+				{ID: "new", Created: now.Unix()},
+			}, nil
+		},
+		diskUsageFunc: func(options client.DiskUsageOptions) (system.DiskUsage, error) {
+			return system.DiskUsage{}, nil
+		},
+		imageRemoveFunc: func(img string, options client.ImageRemoveOptions) ([]image.DeleteResponse, error) {
+			assert.Check(t, !options.Force)
+			if img == "in-use" {
+				return nil, errdefs.ErrConflict.WithMessage("image is being used by running container")
+			}
+			removed = append(removed, img)
+			return []image.DeleteResponse{{Deleted: img}}, nil
+		},
+	})
+	cmd := newPruneCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--force", "--keep-last", "1", "--filter", "until=24h", "--filter", "label!=keep"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.DeepEqual([]string{"old"}, removed))
+
+	cmd = newPruneCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--force", "--keep-last", "1", "--filter", "until=yesterday"})
+	assert.ErrorContains(t, cmd.Execute(), "invalid until filter")
+
+	cmd = newPruneCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--force", "--keep-last", "1", "--filter", "reference=app"})
+	assert.ErrorContains(t, cmd.Execute(), "invalid filter 'reference'")
+}
+
+func TestPruneRetentionPrompt(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{})
+	cli.SetConfigFile(&configfile.ConfigFile{
+		PruneRetention: &configfile.PruneRetention{KeepLast: 1, KeepStorage: "1GB"},
+	})
+	cli.SetIn(streams.NewIn(io.NopCloser(strings.NewReader("N\n"))))
+	cmd := newPruneCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"--keep-last", "3"})
+	assert.ErrorContains(t, cmd.Execute(), "image prune has been cancelled")
+	expected := `WARNING! This will remove all dangling images, keeping the 3 most recent images per repository, 1GiB of disk space.
+Are you sure you want to continue? [y/N] `
+	assert.Check(t, is.Equal(expected, cli.OutBuffer().String()))
+}
+
+func TestPruneRetentionInvalidConfig(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{})
+	cli.SetConfigFile(&configfile.ConfigFile{
+		PruneRetention: &configfile.PruneRetention{KeepUsedWithin: "7 days"},
+	})
+	cmd := newPruneCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--force"})
+	assert.ErrorContains(t, cmd.Execute(), "invalid pruneRetention in configuration file: invalid keepUsedWithin")
+}
@@ -13,6 +13,7 @@
 	"github.com/containerd/platforms"
 	"github.com/distribution/reference"
 	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli-plugins/hooks"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/completion"
 	"github.com/docker/cli/cli/streams"
@@ -20,6 +21,7 @@
 	"github.com/docker/cli/internal/registry"
 	"github.com/docker/cli/internal/tui"
 	"github.com/moby/moby/api/pkg/authconfig"
+	"github.com/moby/moby/api/types"
 	"github.com/moby/moby/api/types/auxprogress"
 	"github.com/moby/moby/client"
 	"github.com/morikuni/aec"
@@ -139,23 +141,30 @@ func runPush(ctx context.Context, dockerCli command.Cli, opts pushOptions) error
 	}
 
 	if opts.quiet {
-		err = jsonstream.Display(ctx, responseBody, streams.NewOut(io.Discard), jsonstream.WithAuxCallback(handleAux()))
+		err = jsonstream.Display(ctx, responseBody, streams.NewOut(io.Discard), jsonstream.WithAuxCallback(handleAux(ctx)))
 		if err == nil {
 			_, _ = fmt.Fprintln(dockerCli.Out(), ref.String())
 		}
 		return err
 	}
-	return jsonstream.Display(ctx, responseBody, dockerCli.Out(), jsonstream.WithAuxCallback(handleAux()))
+	return jsonstream.Display(ctx, responseBody, dockerCli.Out(), jsonstream.WithAuxCallback(handleAux(ctx)))
 }
 
 var notes []string
 
-func handleAux() func(jm jsonstream.JSONMessage) {
+func handleAux(ctx context.Context) func(jm jsonstream.JSONMessage) {
 	return func(jm jsonstream.JSONMessage) {
 		b := []byte(*jm.Aux)
 
+		var pushResult types.PushResult
+		err := json.Unmarshal(b, &pushResult)
+		if err == nil && pushResult.Digest != "" {
+			// Pass the digest of the pushed image to plugin hooks.
+			hooks.SetImageDigest(ctx, pushResult.Digest)
+		}
+
 		var stripped auxprogress.ManifestPushedInsteadOfIndex
-		err := json.Unmarshal(b, &stripped)
+		err = json.Unmarshal(b, &stripped)
 		if err == nil && stripped.ManifestPushedInsteadOfIndex {
 			note := fmt.Sprintf("Not all multiplatform-content is present and only the available single-platform image was pushed\n%s -> %s",
 				aec.RedF.Apply(stripped.OriginalIndex.Digest.String()),
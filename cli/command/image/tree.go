@@ -5,28 +5,36 @@
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"slices"
 	"sort"
 	"strings"
+	"text/template"
 
 	"github.com/containerd/platforms"
+	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/formatter"
 	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/internal/tui"
+	"github.com/docker/cli/templates"
 	"github.com/docker/go-units"
 	"github.com/moby/moby/api/types/filters"
 	imagetypes "github.com/moby/moby/api/types/image"
 	"github.com/moby/moby/client"
 	"github.com/morikuni/aec"
 	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 type treeOptions struct {
-	all     bool
-	filters filters.Args
+	all         bool
+	filters     filters.Args
+	interactive bool
+	format      string
 }
 
 type treeView struct {
@@ -37,13 +45,47 @@ type treeView struct {
 }
 
 func runTree(ctx context.Context, dockerCLI command.Cli, opts treeOptions) error {
+	var tmpl *template.Template
+	if opts.format != formatter.TableFormatKey && formatter.Format(opts.format).IsTable() {
+		// The tree is printed with its own layout, which has no columns
+		// that can be selected.
+		return errors.New(`custom "table" formats are not supported with --tree; use "table", "json", or a Go template without the "table" prefix`)
+	}
+	if opts.format != "" && opts.format != formatter.TableFormatKey {
+		format := opts.format
+		if format == formatter.JSONFormatKey {
+			format = formatter.JSONFormat
+		}
+		var err error
+		tmpl, err = templates.Parse(format)
+		if err != nil {
+			return cli.StatusError{StatusCode: 64, Status: "template parsing error: " + err.Error()}
+		}
+	}
+
+	view, err := loadTreeView(ctx, dockerCLI, opts)
+	if err != nil {
+		return err
+	}
+	if tmpl != nil {
+		return writeTreeFormat(dockerCLI.Out(), tmpl, view)
+	}
+	if opts.interactive && !streamRedirected(dockerCLI.Out()) && dockerCLI.In().IsTerminal() {
+		return runInteractiveTree(ctx, dockerCLI, opts, view)
+	}
+	return printImageTree(dockerCLI, view)
+}
+
+// loadTreeView fetches the images and collects the information to present
+// in the tree view.
+func loadTreeView(ctx context.Context, dockerCLI command.Cli, opts treeOptions) (treeView, error) {
 	images, err := dockerCLI.Client().ImageList(ctx, client.ImageListOptions{
 		All:       opts.all,
 		Filters:   opts.filters,
 		Manifests: true,
 	})
 	if err != nil {
-		return err
+		return treeView{}, err
 	}
 	if !opts.all {
 		images = slices.DeleteFunc(images, isDangling)
@@ -59,6 +101,7 @@ func runTree(ctx context.Context, dockerCLI command.Cli, opts treeOptions) error
 			ID:        img.ID,
 			DiskUsage: units.HumanSizeWithPrecision(float64(img.Size), 3),
 			InUse:     img.Containers > 0,
+			diskUsage: img.Size,
 		}
 
 		var totalContent int64
@@ -77,11 +120,15 @@ func runTree(ctx context.Context, dockerCLI command.Cli, opts treeOptions) error
 			sub := subImage{
 				Platform:  platforms.Format(im.ImageData.Platform),
 				Available: im.Available,
+				platform:  im.ImageData.Platform,
 				Details: imageDetails{
-					ID:          im.ID,
-					DiskUsage:   units.HumanSizeWithPrecision(float64(im.Size.Total), 3),
-					InUse:       len(im.ImageData.Containers) > 0,
-					ContentSize: units.HumanSizeWithPrecision(float64(im.Size.Content), 3),
+					ID:           im.ID,
+					DiskUsage:    units.HumanSizeWithPrecision(float64(im.Size.Total), 3),
+					InUse:        len(im.ImageData.Containers) > 0,
+					ContentSize:  units.HumanSizeWithPrecision(float64(im.Size.Content), 3),
+					diskUsage:    im.Size.Total,
+					contentSize:  im.Size.Content,
+					unpackedSize: im.ImageData.Size.Unpacked,
 				},
 			}
 
@@ -90,6 +137,7 @@ func runTree(ctx context.Context, dockerCLI command.Cli, opts treeOptions) error
 				details.InUse = true
 			}
 
+			details.unpackedSize += sub.Details.unpackedSize
 			children = append(children, sub)
 
 			// Add extra spacing between images if there's at least one entry with children.
@@ -97,6 +145,7 @@ func runTree(ctx context.Context, dockerCLI command.Cli, opts treeOptions) error
 		}
 
 		details.ContentSize = units.HumanSizeWithPrecision(float64(totalContent), 3)
+		details.contentSize = totalContent
 
 		view.images = append(view.images, topImage{
 			Names:    img.RepoTags,
@@ -110,7 +159,7 @@ func runTree(ctx context.Context, dockerCLI command.Cli, opts treeOptions) error
 		return view.images[i].created > view.images[j].created
 	})
 
-	return printImageTree(dockerCLI, view)
+	return view, nil
 }
 
 type imageDetails struct {
@@ -118,6 +167,10 @@ type imageDetails struct {
 	DiskUsage   string
 	InUse       bool
 	ContentSize string
+
+	diskUsage    int64
+	contentSize  int64
+	unpackedSize int64
 }
 
 type topImage struct {
@@ -132,6 +185,69 @@ type subImage struct {
 	Platform  string
 	Available bool
 	Details   imageDetails
+
+	platform ocispec.Platform
+}
+
+// treeImageOutput is the representation of an image in the tree view when
+// formatting output using the "--format" option.
+type treeImageOutput struct {
+	ID           string
+	Names        []string
+	Created      int64
+	DiskUsage    int64
+	ContentSize  int64
+	UnpackedSize int64
+	InUse        bool
+	Platforms    []treePlatformOutput
+}
+
+// treePlatformOutput is the representation of a platform variant of an
+// image in the tree view when formatting output using the "--format" option.
+type treePlatformOutput struct {
+	Platform     string
+	ID           string
+	Available    bool
+	DiskUsage    int64
+	ContentSize  int64
+	UnpackedSize int64
+	InUse        bool
+}
+
+// writeTreeFormat writes each image in the view using the given template.
+func writeTreeFormat(out io.Writer, tmpl *template.Template, view treeView) error {
+	for _, img := range view.images {
+		names := img.Names
+		if names == nil {
+			names = []string{}
+		}
+		o := treeImageOutput{
+			ID:           img.Details.ID,
+			Names:        names,
+			Created:      img.created,
+			DiskUsage:    img.Details.diskUsage,
+			ContentSize:  img.Details.contentSize,
+			UnpackedSize: img.Details.unpackedSize,
+			InUse:        img.Details.InUse,
+			Platforms:    make([]treePlatformOutput, 0, len(img.Children)),
+		}
+		for _, sub := range img.Children {
+			o.Platforms = append(o.Platforms, treePlatformOutput{
+				Platform:     sub.Platform,
+				ID:           sub.Details.ID,
+				Available:    sub.Available,
+				DiskUsage:    sub.Details.diskUsage,
+				ContentSize:  sub.Details.contentSize,
+				UnpackedSize: sub.Details.unpackedSize,
+				InUse:        sub.Details.InUse,
+			})
+		}
+		if err := tmpl.Execute(out, o); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(out)
+	}
+	return nil
 }
 
 const columnSpacing = 3
@@ -210,10 +326,33 @@ func printImageTree(dockerCLI command.Cli, view treeView) error {
 	topNameColor := out.Color(aec.NewBuilder(aec.BlueF, aec.Bold).ANSI)
 	normalColor := out.Color(tui.ColorSecondary)
 	untaggedColor := out.Color(tui.ColorTertiary)
-	isTerm := out.IsTerminal()
 
 	out.Println(generateLegend(out, width))
 
+	columns := treeColumns(out, width, view)
+
+	// Print columns
+	_, _ = fmt.Fprintln(out, headerString(columns))
+
+	// Print images
+	for _, img := range view.images {
+		printNames(out, columns, img, topNameColor, untaggedColor)
+		printDetails(out, columns, normalColor, img.Details)
+
+		if len(img.Children) > 0 || view.imageSpacing {
+			_, _ = fmt.Fprintln(out)
+		}
+		printChildren(out, columns, img, normalColor)
+		_, _ = fmt.Fprintln(out)
+	}
+
+	return nil
+}
+
+// treeColumns returns the columns to present for the given view, adjusted
+// to the given width.
+func treeColumns(out tui.Output, width uint, view treeView) []imgColumn {
+	isTerm := out.IsTerminal()
 	possibleChips := getPossibleChips(view)
 	columns := []imgColumn{
 		{
@@ -277,31 +416,19 @@ func printImageTree(dockerCLI command.Cli, view treeView) error {
 		},
 	}
 
-	columns = adjustColumns(width, columns, view.images)
+	return adjustColumns(width, columns, view.images)
+}
 
-	// Print columns
+// headerString returns the column titles.
+func headerString(columns []imgColumn) string {
+	var header string
 	for i, h := range columns {
 		if i > 0 {
-			_, _ = fmt.Fprint(out, strings.Repeat(" ", columnSpacing))
+			header += strings.Repeat(" ", columnSpacing)
 		}
-
-		_, _ = fmt.Fprint(out, h.Print(tui.ColorTitle, strings.ToUpper(h.Title)))
+		header += h.Print(tui.ColorTitle, strings.ToUpper(h.Title))
 	}
-	_, _ = fmt.Fprintln(out)
-
-	// Print images
-	for _, img := range view.images {
-		printNames(out, columns, img, topNameColor, untaggedColor)
-		printDetails(out, columns, normalColor, img.Details)
-
-		if len(img.Children) > 0 || view.imageSpacing {
-			_, _ = fmt.Fprintln(out)
-		}
-		printChildren(out, columns, img, normalColor)
-		_, _ = fmt.Fprintln(out)
-	}
-
-	return nil
+	return header
 }
 
 // adjustColumns adjusts the width of the first column to maximize the space
@@ -354,19 +481,25 @@ func generateLegend(out tui.Output, width uint) string {
 }
 
 func printDetails(out tui.Output, headers []imgColumn, defaultColor aec.ANSI, details imageDetails) {
+	_, _ = fmt.Fprint(out, detailsString(headers, defaultColor, details))
+}
+
+// detailsString returns the details columns for an image or platform.
+func detailsString(headers []imgColumn, defaultColor aec.ANSI, details imageDetails) string {
+	var s string
 	for _, h := range headers {
 		if h.DetailsValue == nil {
 			continue
 		}
 
-		_, _ = fmt.Fprint(out, strings.Repeat(" ", columnSpacing))
+		s += strings.Repeat(" ", columnSpacing)
 		clr := defaultColor
 		if h.Color != nil {
 			clr = *h.Color
 		}
-		val := h.DetailsValue(&details)
-		_, _ = fmt.Fprint(out, h.Print(clr, val))
+		s += h.Print(clr, h.DetailsValue(&details))
 	}
+	return s
 }
 
 func printChildren(out tui.Output, headers []imgColumn, img topImage, normalColor aec.ANSI) {
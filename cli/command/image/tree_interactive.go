@@ -0,0 +1,508 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.23
+
+package image
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/internal/tui"
+	"github.com/moby/moby/client"
+	"github.com/morikuni/aec"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// treeSortOrder is the order in which images are presented in the
+// interactive tree view.
+type treeSortOrder int
+
+const (
+	sortByCreated treeSortOrder = iota
+	sortBySize
+	sortByName
+)
+
+func (o treeSortOrder) String() string {
+	switch o {
+	case sortBySize:
+		return "size"
+	case sortByName:
+		return "name"
+	default:
+		return "created"
+	}
+}
+
+type treeMode int
+
+const (
+	treeModeBrowse treeMode = iota
+	treeModeFilter
+	treeModeConfirm
+)
+
+type keyCode int
+
+const (
+	keyRune keyCode = iota
+	keyUp
+	keyDown
+	keyLeft
+	keyRight
+	keyEnter
+	keyEscape
+	keyBackspace
+	keyInterrupt
+)
+
+type key struct {
+	code keyCode
+	r    rune
+}
+
+// treeRow is a row in the interactive tree view; either an image, or one
+// of its platform variants.
+type treeRow struct {
+	image topImage
+	// child is the index of the platform variant of the image, or -1
+	// if the row represents the image itself.
+	child int
+}
+
+// treeRemoval is a removal (or untag) of an image, or a platform variant
+// of an image, pending confirmation.
+type treeRemoval struct {
+	image    topImage
+	untag    bool
+	platform *subImage
+}
+
+// String returns the confirmation message for the removal.
+func (r treeRemoval) String() string {
+	switch {
+	case r.platform != nil:
+		return fmt.Sprintf("Delete platform %s of image %s?", r.platform.Platform, imageName(r.image))
+	case r.untag:
+		return fmt.Sprintf("Untag %s?", strings.Join(r.image.Names, ", "))
+	default:
+		return fmt.Sprintf("Delete image %s?", imageName(r.image))
+	}
+}
+
+// run performs the removal.
+func (r treeRemoval) run(ctx context.Context, apiClient client.ImageAPIClient) error {
+	switch {
+	case r.platform != nil:
+		_, err := apiClient.ImageRemove(ctx, r.image.Details.ID, client.ImageRemoveOptions{
+			Platforms:     []ocispec.Platform{r.platform.platform},
+			PruneChildren: true,
+		})
+		return err
+	case r.untag:
+		var errs []error
+		for _, name := range r.image.Names {
+			if _, err := apiClient.ImageRemove(ctx, name, client.ImageRemoveOptions{PruneChildren: true}); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	default:
+		_, err := apiClient.ImageRemove(ctx, r.image.Details.ID, client.ImageRemoveOptions{PruneChildren: true})
+		return err
+	}
+}
+
+func imageName(img topImage) string {
+	if len(img.Names) == 0 {
+		return "<untagged>"
+	}
+	return strings.Join(img.Names, ", ")
+}
+
+// interactiveTree holds the state of the interactive tree view.
+type interactiveTree struct {
+	view     treeView
+	expanded map[string]bool
+	sortBy   treeSortOrder
+	filter   string
+	mode     treeMode
+	pending  *treeRemoval
+	status   string
+
+	rows   []treeRow
+	cursor int
+	offset int
+}
+
+func newInteractiveTree(view treeView) *interactiveTree {
+	t := &interactiveTree{expanded: make(map[string]bool)}
+	t.setView(view)
+	return t
+}
+
+// setView replaces the images presented, preserving the selected image
+// where possible.
+func (t *interactiveTree) setView(view treeView) {
+	var selected string
+	if t.cursor < len(t.rows) {
+		selected = t.rows[t.cursor].image.Details.ID
+	}
+	t.view = view
+	t.updateRows()
+	for i, row := range t.rows {
+		if row.child == -1 && row.image.Details.ID == selected {
+			t.cursor = i
+			break
+		}
+	}
+}
+
+// updateRows applies the filter and sort order, and determines the rows
+// to present.
+func (t *interactiveTree) updateRows() {
+	images := make([]topImage, 0, len(t.view.images))
+	for _, img := range t.view.images {
+		if matchesTreeFilter(img, t.filter) {
+			images = append(images, img)
+		}
+	}
+	sort.SliceStable(images, func(i, j int) bool {
+		switch t.sortBy {
+		case sortBySize:
+			return images[i].Details.diskUsage > images[j].Details.diskUsage
+		case sortByName:
+			if len(images[i].Names) == 0 || len(images[j].Names) == 0 {
+				return len(images[i].Names) > len(images[j].Names)
+			}
+			return images[i].Names[0] < images[j].Names[0]
+		default:
+			return images[i].created > images[j].created
+		}
+	})
+
+	t.rows = t.rows[:0]
+	for _, img := range images {
+		t.rows = append(t.rows, treeRow{image: img, child: -1})
+		if t.expanded[img.Details.ID] {
+			for i := range img.Children {
+				t.rows = append(t.rows, treeRow{image: img, child: i})
+			}
+		}
+	}
+	if t.cursor >= len(t.rows) {
+		t.cursor = max(len(t.rows)-1, 0)
+	}
+}
+
+// matchesTreeFilter returns whether any of the image's names contain the
+// filter, or its ID starts with it.
+func matchesTreeFilter(img topImage, filter string) bool {
+	if filter == "" {
+		return true
+	}
+	filter = strings.ToLower(filter)
+	for _, name := range img.Names {
+		if strings.Contains(strings.ToLower(name), filter) {
+			return true
+		}
+	}
+	return strings.HasPrefix(strings.TrimPrefix(img.Details.ID, "sha256:"), filter)
+}
+
+// handleKey updates the state for the given key. It returns whether the
+// view must be closed, or a removal that was confirmed by the user.
+func (t *interactiveTree) handleKey(k key) (quit bool, confirmed *treeRemoval) {
+	if k.code == keyInterrupt {
+		return true, nil
+	}
+	switch t.mode {
+	case treeModeFilter:
+		switch k.code {
+		case keyEnter:
+			t.mode = treeModeBrowse
+		case keyEscape:
+			t.mode = treeModeBrowse
+			t.filter = ""
+			t.updateRows()
+		case keyBackspace:
+			if t.filter != "" {
+				r := []rune(t.filter)
+				t.filter = string(r[:len(r)-1])
+				t.updateRows()
+			}
+		case keyRune:
+			t.filter += string(k.r)
+			t.cursor = 0
+			t.updateRows()
+		}
+		return false, nil
+	case treeModeConfirm:
+		t.mode = treeModeBrowse
+		removal := t.pending
+		t.pending = nil
+		if k.code == keyRune && (k.r == 'y' || k.r == 'Y') {
+			return false, removal
+		}
+		t.status = "Cancelled"
+		return false, nil
+	}
+
+	t.status = ""
+	switch k.code {
+	case keyUp:
+		if t.cursor > 0 {
+			t.cursor--
+		}
+	case keyDown:
+		if t.cursor < len(t.rows)-1 {
+			t.cursor++
+		}
+	case keyRight:
+		t.setExpanded(true)
+	case keyLeft:
+		t.setExpanded(false)
+	case keyEnter:
+		if row, ok := t.selected(); ok {
+			t.setExpanded(!t.expanded[row.image.Details.ID])
+		}
+	case keyEscape:
+		if t.filter != "" {
+			t.filter = ""
+			t.updateRows()
+		}
+	case keyRune:
+		switch k.r {
+		case 'q':
+			return true, nil
+		case 'k':
+			return t.handleKey(key{code: keyUp})
+		case 'j':
+			return t.handleKey(key{code: keyDown})
+		case 's':
+			t.sortBy = (t.sortBy + 1) % 3
+			t.updateRows()
+		case '/':
+			t.mode = treeModeFilter
+		case 'd', 'u':
+			t.confirmRemoval(k.r == 'u')
+		}
+	}
+	return false, nil
+}
+
+func (t *interactiveTree) selected() (treeRow, bool) {
+	if t.cursor >= len(t.rows) {
+		return treeRow{}, false
+	}
+	return t.rows[t.cursor], true
+}
+
+// setExpanded expands or collapses the selected image. Collapsing moves
+// the cursor from a platform variant to its image.
+func (t *interactiveTree) setExpanded(expanded bool) {
+	row, ok := t.selected()
+	if !ok || len(row.image.Children) == 0 {
+		return
+	}
+	t.expanded[row.image.Details.ID] = expanded
+	if !expanded {
+		t.cursor -= row.child + 1
+	}
+	t.updateRows()
+}
+
+func (t *interactiveTree) confirmRemoval(untag bool) {
+	row, ok := t.selected()
+	if !ok {
+		return
+	}
+	removal := &treeRemoval{image: row.image, untag: untag}
+	if row.child >= 0 {
+		if untag {
+			t.status = "Platform variants cannot be untagged"
+			return
+		}
+		removal.platform = &row.image.Children[row.child]
+	} else if untag && len(row.image.Names) == 0 {
+		t.status = "Image has no tags"
+		return
+	}
+	t.pending = removal
+	t.mode = treeModeConfirm
+}
+
+const treeHelp = "↑/↓ select  →/← expand/collapse  s sort  / filter  d delete  u untag  q quit"
+
+// render returns the screen contents for the given terminal size.
+func (t *interactiveTree) render(out tui.Output, width, height uint) string {
+	if width == 0 {
+		width = 80
+	}
+	if height < 5 {
+		height = 5
+	}
+
+	normalColor := out.Color(tui.ColorSecondary)
+	topNameColor := out.Color(aec.NewBuilder(aec.BlueF, aec.Bold).ANSI)
+	untaggedColor := out.Color(tui.ColorTertiary)
+	selectedColor := out.Color(aec.NewBuilder(aec.Inverse).ANSI)
+
+	columns := treeColumns(out, width, t.view)
+	lines := []string{headerString(columns)}
+
+	// Keep the selected row visible.
+	visible := int(height) - 3
+	if t.cursor < t.offset {
+		t.offset = t.cursor
+	} else if t.cursor >= t.offset+visible {
+		t.offset = t.cursor - visible + 1
+	}
+	for i := t.offset; i < len(t.rows) && i < t.offset+visible; i++ {
+		row := t.rows[i]
+		nameColor, clr := topNameColor, normalColor
+		if i == t.cursor {
+			nameColor, clr = selectedColor, selectedColor
+		}
+		if row.child >= 0 {
+			sub := row.image.Children[row.child]
+			if !sub.Available && i != t.cursor {
+				clr = clr.With(aec.Faint)
+			}
+			prefix := "  ├─ "
+			if row.child == len(row.image.Children)-1 {
+				prefix = "  └─ "
+			}
+			lines = append(lines, columns[0].Print(clr, prefix+sub.Platform)+detailsString(columns, clr, sub.Details))
+			continue
+		}
+		marker := "  "
+		if len(row.image.Children) > 0 {
+			marker = "▸ "
+			if t.expanded[row.image.Details.ID] {
+				marker = "▾ "
+			}
+		}
+		name := strings.Join(row.image.Names, ", ")
+		if name == "" {
+			name = "<untagged>"
+			if i != t.cursor {
+				nameColor = untaggedColor
+			}
+		}
+		lines = append(lines, columns[0].Print(nameColor, marker+name)+detailsString(columns, clr, row.image.Details))
+	}
+	if len(t.rows) == 0 {
+		lines = append(lines, untaggedColor.Apply("No images found"))
+	}
+	for len(lines) < int(height)-2 {
+		lines = append(lines, "")
+	}
+
+	status := "Sort: " + t.sortBy.String()
+	if t.filter != "" || t.mode == treeModeFilter {
+		status += " | Filter: " + t.filter
+	}
+	switch {
+	case t.mode == treeModeFilter:
+		status += "█"
+	case t.mode == treeModeConfirm && t.pending != nil:
+		status = out.Color(tui.ColorWarning).Apply(t.pending.String() + " [y/N]")
+	case t.status != "":
+		status += " | " + t.status
+	}
+	lines = append(lines, status, out.Color(tui.ColorTertiary).Apply(tui.Ellipsis(treeHelp, int(width)-1)))
+	return strings.Join(lines, "\r\n")
+}
+
+// readKey reads a key-press from a terminal in raw mode.
+func readKey(r *bufio.Reader) (key, error) {
+	c, _, err := r.ReadRune()
+	if err != nil {
+		return key{}, err
+	}
+	switch c {
+	case 0x03, 0x04:
+		return key{code: keyInterrupt}, nil
+	case '\r', '\n':
+		return key{code: keyEnter}, nil
+	case 0x7f, 0x08:
+		return key{code: keyBackspace}, nil
+	case 0x1b:
+		if r.Buffered() < 2 {
+			return key{code: keyEscape}, nil
+		}
+		seq := make([]byte, 2)
+		if _, err := io.ReadFull(r, seq); err != nil {
+			return key{}, err
+		}
+		if seq[0] == '[' || seq[0] == 'O' {
+			switch seq[1] {
+			case 'A':
+				return key{code: keyUp}, nil
+			case 'B':
+				return key{code: keyDown}, nil
+			case 'C':
+				return key{code: keyRight}, nil
+			case 'D':
+				return key{code: keyLeft}, nil
+			}
+		}
+		return key{code: keyEscape}, nil
+	}
+	return key{code: keyRune, r: c}, nil
+}
+
+// runInteractiveTree presents the tree view in an interactive terminal UI,
+// allowing images to be browsed, filtered, and removed.
+func runInteractiveTree(ctx context.Context, dockerCLI command.Cli, opts treeOptions, view treeView) error {
+	if err := dockerCLI.In().SetRawTerminal(); err != nil {
+		return err
+	}
+	defer dockerCLI.In().RestoreTerminal()
+
+	out := dockerCLI.Out()
+	// Switch to the alternate screen buffer, and hide the cursor.
+	_, _ = fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	defer func() {
+		_, _ = fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+	}()
+
+	t := newInteractiveTree(view)
+	keys := bufio.NewReader(dockerCLI.In())
+	for {
+		height, width := out.GetTtySize()
+		_, _ = fmt.Fprint(out, "\x1b[H\x1b[2J"+t.render(tui.NewOutput(out), width, height))
+
+		k, err := readKey(keys)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		quit, removal := t.handleKey(k)
+		if quit {
+			return nil
+		}
+		if removal == nil {
+			continue
+		}
+		if err := removal.run(ctx, dockerCLI.Client()); err != nil {
+			t.status = "Error: " + err.Error()
+			continue
+		}
+		t.status = "Done"
+		v, err := loadTreeView(ctx, dockerCLI, opts)
+		if err != nil {
+			return err
+		}
+		t.setView(v)
+	}
+}
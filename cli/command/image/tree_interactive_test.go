@@ -0,0 +1,205 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.23
+
+package image
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/cli/internal/tui"
+	"github.com/google/go-cmp/cmp"
+	"github.com/moby/moby/api/types/image"
+	"github.com/moby/moby/client"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func testTreeView() treeView {
+	return treeView{
+		images: []topImage{
+			{
+				Names:   []string{"alpine:latest"},
+				Details: imageDetails{ID: "sha256:aaaaaaaaaaaa", DiskUsage: "10MB", diskUsage: 10},
+				Children: []subImage{
+					{Platform: "linux/amd64", Available: true, Details: imageDetails{ID: "sha256:a1"}, platform: ocispec.Platform{OS: "linux", Architecture: "amd64"}},
+					{Platform: "linux/arm64", Details: imageDetails{ID: "sha256:a2"}, platform: ocispec.Platform{OS: "linux", Architecture: "arm64"}},
+				},
+				created: 300,
+			},
+			{
+				Names:   []string{"busybox:latest", "busybox:1"},
+				Details: imageDetails{ID: "sha256:bbbbbbbbbbbb", DiskUsage: "30MB", diskUsage: 30},
+				created: 200,
+			},
+			{
+				Details: imageDetails{ID: "sha256:cccccccccccc", DiskUsage: "20MB", diskUsage: 20},
+				created: 100,
+			},
+		},
+	}
+}
+
+func rowNames(t *interactiveTree) []string {
+	var names []string
+	for _, row := range t.rows {
+		if row.child >= 0 {
+			names = append(names, "- "+row.image.Children[row.child].Platform)
+		} else {
+			names = append(names, imageName(row.image))
+		}
+	}
+	return names
+}
+
+func runeKey(r rune) key {
+	return key{code: keyRune, r: r}
+}
+
+func TestInteractiveTreeNavigation(t *testing.T) {
+	tree := newInteractiveTree(testTreeView())
+	assert.Check(t, is.DeepEqual(rowNames(tree), []string{"alpine:latest", "busybox:latest, busybox:1", "<untagged>"}))
+
+	tree.handleKey(key{code: keyRight})
+	assert.Check(t, is.DeepEqual(rowNames(tree), []string{"alpine:latest", "- linux/amd64", "- linux/arm64", "busybox:latest, busybox:1", "<untagged>"}))
+
+	tree.handleKey(key{code: keyDown})
+	tree.handleKey(runeKey('j'))
+	assert.Check(t, is.Equal(tree.cursor, 2))
+
+	// Collapsing from a platform variant selects the image.
+	tree.handleKey(key{code: keyLeft})
+	assert.Check(t, is.Equal(tree.cursor, 0))
+	assert.Check(t, is.Len(tree.rows, 3))
+
+	// The cursor does not move past the last row.
+	for range 5 {
+		tree.handleKey(key{code: keyDown})
+	}
+	assert.Check(t, is.Equal(tree.cursor, 2))
+
+	quit, _ := tree.handleKey(runeKey('q'))
+	assert.Check(t, quit)
+}
+
+func TestInteractiveTreeSortAndFilter(t *testing.T) {
+	tree := newInteractiveTree(testTreeView())
+
+	tree.handleKey(runeKey('s'))
+	assert.Check(t, is.Equal(tree.sortBy, sortBySize))
+	assert.Check(t, is.DeepEqual(rowNames(tree), []string{"busybox:latest, busybox:1", "<untagged>", "alpine:latest"}))
+
+	tree.handleKey(runeKey('s'))
+	assert.Check(t, is.Equal(tree.sortBy, sortByName))
+	assert.Check(t, is.DeepEqual(rowNames(tree), []string{"alpine:latest", "busybox:latest, busybox:1", "<untagged>"}))
+
+	tree.handleKey(runeKey('/'))
+	for _, r := range "BUSYX" {
+		tree.handleKey(runeKey(r))
+	}
+	assert.Check(t, is.Len(tree.rows, 0))
+	tree.handleKey(key{code: keyBackspace})
+	tree.handleKey(key{code: keyEnter})
+	assert.Check(t, is.Equal(tree.mode, treeModeBrowse))
+	assert.Check(t, is.Equal(tree.filter, "BUSY"))
+	assert.Check(t, is.DeepEqual(rowNames(tree), []string{"busybox:latest, busybox:1"}))
+
+	// Filter on ID prefix.
+	tree.handleKey(key{code: keyEscape})
+	tree.handleKey(runeKey('/'))
+	for _, r := range "cccc" {
+		tree.handleKey(runeKey(r))
+	}
+	assert.Check(t, is.DeepEqual(rowNames(tree), []string{"<untagged>"}))
+}
+
+func TestInteractiveTreeRemoval(t *testing.T) {
+	tree := newInteractiveTree(testTreeView())
+
+	// Declining the confirmation cancels the removal.
+	tree.handleKey(runeKey('d'))
+	assert.Check(t, is.Equal(tree.mode, treeModeConfirm))
+	assert.Check(t, is.Equal(tree.pending.String(), "Delete image alpine:latest?"))
+	_, removal := tree.handleKey(runeKey('n'))
+	assert.Check(t, is.Nil(removal))
+	assert.Check(t, is.Equal(tree.status, "Cancelled"))
+
+	tree.handleKey(key{code: keyRight})
+	tree.handleKey(key{code: keyDown})
+	tree.handleKey(key{code: keyDown})
+	tree.handleKey(runeKey('u'))
+	assert.Check(t, is.Equal(tree.status, "Platform variants cannot be untagged"))
+	tree.handleKey(runeKey('d'))
+	_, removal = tree.handleKey(runeKey('y'))
+	assert.Assert(t, removal != nil)
+	assert.Check(t, is.Equal(removal.String(), "Delete platform linux/arm64 of image alpine:latest?"))
+
+	var removed []string
+	var platforms []ocispec.Platform
+	apiClient := &fakeClient{
+		imageRemoveFunc: func(img string, options client.ImageRemoveOptions) ([]image.DeleteResponse, error) {
+			removed = append(removed, img)
+			platforms = append(platforms, options.Platforms...)
+			return nil, nil
+		},
+	}
+	assert.NilError(t, removal.run(context.Background(), apiClient))
+	assert.Check(t, is.DeepEqual(removed, []string{"sha256:aaaaaaaaaaaa"}))
+	assert.Check(t, is.DeepEqual(platforms, []ocispec.Platform{{OS: "linux", Architecture: "arm64"}}))
+
+	removed = nil
+	tree.handleKey(key{code: keyDown})
+	tree.handleKey(runeKey('u'))
+	_, removal = tree.handleKey(runeKey('y'))
+	assert.Assert(t, removal != nil)
+	assert.NilError(t, removal.run(context.Background(), apiClient))
+	assert.Check(t, is.DeepEqual(removed, []string{"busybox:latest", "busybox:1"}))
+}
+
+func TestInteractiveTreeRender(t *testing.T) {
+	tree := newInteractiveTree(testTreeView())
+	tree.handleKey(key{code: keyRight})
+
+	out := tui.NewOutput(streams.NewOut(&bytes.Buffer{}))
+	lines := strings.Split(tree.render(out, 100, 6), "\r\n")
+	assert.Assert(t, is.Len(lines, 6))
+	assert.Check(t, is.Contains(lines[0], "IMAGE"))
+	assert.Check(t, is.Contains(lines[1], "▾ alpine:latest"))
+	assert.Check(t, is.Contains(lines[2], "├─ linux/amd64"))
+	assert.Check(t, is.Contains(lines[3], "└─ linux/arm64"))
+	assert.Check(t, is.Equal(lines[4], "Sort: created"))
+	assert.Check(t, is.Equal(lines[5], treeHelp))
+
+	// Scroll to keep the selected row visible.
+	for range 4 {
+		tree.handleKey(key{code: keyDown})
+	}
+	lines = strings.Split(tree.render(out, 100, 6), "\r\n")
+	assert.Check(t, is.Contains(lines[1], "└─ linux/arm64"))
+	assert.Check(t, is.Contains(lines[3], "<untagged>"))
+}
+
+func TestReadKey(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("\x1b[A\x1b[Bx\r\x7f\x03"))
+	var keys []key
+	for {
+		k, err := readKey(r)
+		if err != nil {
+			break
+		}
+		keys = append(keys, k)
+	}
+	assert.Check(t, is.DeepEqual(keys, []key{
+		{code: keyUp},
+		{code: keyDown},
+		{code: keyRune, r: 'x'},
+		{code: keyEnter},
+		{code: keyBackspace},
+		{code: keyInterrupt},
+	}, cmp.AllowUnexported(key{})))
+}
@@ -0,0 +1,135 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.23
+
+package image
+
+import (
+	"io"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/moby/moby/api/types/image"
+	"github.com/moby/moby/client"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/golden"
+)
+
+func treeTestImages() []image.Summary {
+	amd64 := image.ManifestSummary{
+		ID:        "sha256:1111111111111111111111111111111111111111111111111111111111111111",
+		Available: true,
+		Kind:      image.ManifestKindImage,
+		ImageData: &image.ImageProperties{
+			Platform:   ocispec.Platform{OS: "linux", Architecture: "amd64"},
+			Containers: []string{"container-1"},
+		},
+	}
+	amd64.Size.Content = 3000
+	amd64.Size.Total = 10000
+	amd64.ImageData.Size.Unpacked = 7000
+
+	arm64 := image.ManifestSummary{
+		ID:        "sha256:2222222222222222222222222222222222222222222222222222222222222222",
+		Available: false,
+		Kind:      image.ManifestKindImage,
+		ImageData: &image.ImageProperties{
+			Platform: ocispec.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"},
+		},
+	}
+	arm64.Size.Content = 1000
+
+	attestation := image.ManifestSummary{
+		ID:              "sha256:3333333333333333333333333333333333333333333333333333333333333333",
+		Available:       true,
+		Kind:            image.ManifestKindAttestation,
+		AttestationData: &image.AttestationProperties{For: "sha256:1111111111111111111111111111111111111111111111111111111111111111"},
+	}
+	attestation.Size.Content = 500
+
+	return []image.Summary{
+		{
+			ID:        "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+			RepoTags:  []string{"example:latest", "example:1.0"},
+			Created:   1700000000,
+			Size:      10000,
+			Manifests: []image.ManifestSummary{amd64, arm64, attestation},
+		},
+		{
+			ID:       "sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+			RepoTags: []string{"busybox:latest"},
+			Created:  1600000000,
+			Size:     4000,
+		},
+	}
+}
+
+func TestTreeFormat(t *testing.T) {
+	testCases := []struct {
+		name   string
+		format string
+	}{
+		{
+			name:   "json",
+			format: "json",
+		},
+		{
+			name:   "template",
+			format: `{{.ID}} {{join .Names ","}}{{range .Platforms}} {{.Platform}}={{.UnpackedSize}}{{if .InUse}}(in use){{end}}{{end}}`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeClient{
+				imageListFunc: func(options client.ImageListOptions) ([]image.Summary, error) {
+					assert.Check(t, options.Manifests)
+					return treeTestImages(), nil
+				},
+			})
+			cmd := newImagesCommand(cli)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs([]string{"--tree", "--format", tc.format})
+			assert.NilError(t, cmd.Execute())
+			golden.Assert(t, cli.OutBuffer().String(), "tree-format-"+tc.name+".golden")
+		})
+	}
+}
+
+func TestTreeFormatErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			name:          "invalid-template",
+			args:          []string{"--tree", "--format", "{{.Invalid"},
+			expectedError: "template parsing error",
+		},
+		{
+			name:          "custom-table",
+			args:          []string{"--tree", "--format", "table {{.ID}}"},
+			expectedError: `custom "table" formats are not supported with --tree`,
+		},
+		{
+			name:          "interactive",
+			args:          []string{"--tree", "--interactive", "--format", "json"},
+			expectedError: "--interactive can't be used with --format",
+		},
+		{
+			name:          "interactive-without-tree",
+			args:          []string{"--interactive"},
+			expectedError: "--interactive can only be used with --tree",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := newImagesCommand(test.NewFakeCli(&fakeClient{}))
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs(tc.args)
+			assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+		})
+	}
+}
@@ -21,8 +21,9 @@ func init() {
 }
 
 type pruneOptions struct {
-	force  bool
-	filter opts.FilterOpt
+	force     bool
+	filter    opts.FilterOpt
+	onDeleted func(id string)
 }
 
 // newPruneCommand returns a new cobra prune command for networks
@@ -79,6 +80,9 @@ func runPrune(ctx context.Context, dockerCli command.Cli, options pruneOptions)
 		output = "Deleted Networks:\n"
 		for _, id := range report.NetworksDeleted {
 			output += id + "\n"
+			if options.onDeleted != nil {
+				options.onDeleted(id)
+			}
 		}
 	}
 
@@ -98,8 +102,9 @@ func pruneFn(ctx context.Context, dockerCLI command.Cli, options pruner.PruneOpt
 		return 0, confirmMsg, cancelledErr{errors.New("network prune has been cancelled")}
 	}
 	output, err := runPrune(ctx, dockerCLI, pruneOptions{
-		force:  true,
-		filter: options.Filter,
+		force:     true,
+		filter:    options.Filter,
+		onDeleted: options.OnDeleted,
 	})
 	return 0, output, err
 }
@@ -9,13 +9,17 @@
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/containerd/errdefs"
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
 	"github.com/docker/cli/cli/command/formatter"
 	"github.com/docker/cli/cli/command/idresolver"
+	flagsHelper "github.com/docker/cli/cli/flags"
 	"github.com/docker/cli/internal/logdetails"
+	"github.com/docker/cli/internal/logstream"
 	"github.com/moby/moby/api/pkg/stdcopy"
 	"github.com/moby/moby/api/types/swarm"
 	"github.com/moby/moby/client"
@@ -32,10 +36,30 @@ type logsOptions struct {
 	tail       string
 	details    bool
 	raw        bool
+	format     string
+	grep       string
+	level      string
+	context    int
 
 	target string
 }
 
+// streamOptions returns the options for formatting and filtering logs.
+func (opts *logsOptions) streamOptions() logstream.Options {
+	return logstream.Options{
+		Format:     opts.format,
+		Grep:       opts.grep,
+		Level:      opts.level,
+		Context:    opts.context,
+		Timestamps: opts.timestamps,
+		Details:    opts.details,
+
+		// Timestamps are printed before the task name, as when log lines
+		// are printed as-is.
+		TimestampBeforePrefix: true,
+	}
+}
+
 func newLogsCommand(dockerCLI command.Cli) *cobra.Command {
 	var opts logsOptions
 
@@ -66,6 +90,12 @@ func newLogsCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.BoolVar(&opts.details, "details", false, "Show extra details provided to logs")
 	flags.SetAnnotation("details", "version", []string{"1.30"})
 	flags.StringVarP(&opts.tail, "tail", "n", "all", "Number of lines to show from the end of the logs")
+	flags.StringVar(&opts.format, "format", "", flagsHelper.InspectFormatHelp)
+	flags.StringVar(&opts.grep, "grep", "", "Only show lines matching the regular expression")
+	flags.StringVar(&opts.level, "level", "", `Only show lines with at least the given log level ("trace", "debug", "info", "warn", "error", "fatal")`)
+	flags.IntVar(&opts.context, "grep-context", 0, "Number of lines to show before and after lines selected by --grep or --level")
+
+	_ = cmd.RegisterFlagCompletionFunc("level", completion.FromList("trace", "debug", "info", "warn", "error", "fatal"))
 
 	return cmd
 }
@@ -73,6 +103,18 @@ func newLogsCommand(dockerCLI command.Cli) *cobra.Command {
 func runLogs(ctx context.Context, dockerCli command.Cli, opts *logsOptions) error {
 	apiClient := dockerCli.Client()
 
+	var printer *logstream.Printer
+	if streamOpts := opts.streamOptions(); streamOpts.Enabled() {
+		if opts.raw {
+			return errors.New("--raw can't be used with --format, --grep, or --level")
+		}
+		var err error
+		printer, err = logstream.NewPrinter(dockerCli.Out(), dockerCli.Err(), streamOpts)
+		if err != nil {
+			return err
+		}
+	}
+
 	var (
 		maxLength    = 1
 		responseBody io.ReadCloser
@@ -127,7 +169,8 @@ func runLogs(ctx context.Context, dockerCli command.Cli, opts *logsOptions) erro
 		ShowStdout: true,
 		ShowStderr: true,
 		Since:      opts.since,
-		Timestamps: opts.timestamps,
+		// timestamps are always needed to format or filter the logs.
+		Timestamps: opts.timestamps || printer != nil,
 		Follow:     opts.follow,
 		Tail:       opts.tail,
 		// get the details if we request it OR if we're not doing raw mode
@@ -153,8 +196,8 @@ func runLogs(ctx context.Context, dockerCli command.Cli, opts *logsOptions) erro
 	if !opts.raw {
 		taskFormatter := newTaskFormatter(apiClient, opts, maxLength)
 
-		stdout = &logWriter{ctx: ctx, opts: opts, f: taskFormatter, w: stdout}
-		stderr = &logWriter{ctx: ctx, opts: opts, f: taskFormatter, w: stderr}
+		stdout = &logWriter{ctx: ctx, opts: opts, f: taskFormatter, w: stdout, printer: printer, stream: "stdout"}
+		stderr = &logWriter{ctx: ctx, opts: opts, f: taskFormatter, w: stderr, printer: printer, stream: "stderr"}
 	}
 
 	_, err = stdcopy.StdCopy(stdout, stderr, responseBody)
@@ -172,9 +215,21 @@ type taskFormatter struct {
 	padding int
 
 	r *idresolver.IDResolver
-	// cache saves a pre-cooked logContext formatted string based on a
-	// logcontext object, so we don't have to resolve names every time
-	cache map[logContext]string
+	// cache saves a pre-cooked logSource based on a logcontext object,
+	// so we don't have to resolve names every time
+	cache map[logContext]logSource
+}
+
+// logSource is the resolved task and node name of a log line.
+type logSource struct {
+	taskName string
+	nodeName string
+	padding  string
+}
+
+// String returns the formatted task and node name, including padding.
+func (s logSource) String() string {
+	return s.taskName + "@" + s.nodeName + s.padding
 }
 
 func newTaskFormatter(apiClient client.APIClient, opts *logsOptions, padding int) *taskFormatter {
@@ -183,28 +238,28 @@ func newTaskFormatter(apiClient client.APIClient, opts *logsOptions, padding int
 		opts:    opts,
 		padding: padding,
 		r:       idresolver.New(apiClient, opts.noResolve),
-		cache:   make(map[logContext]string),
+		cache:   make(map[logContext]logSource),
 	}
 }
 
-func (f *taskFormatter) format(ctx context.Context, logCtx logContext) (string, error) {
+func (f *taskFormatter) format(ctx context.Context, logCtx logContext) (logSource, error) {
 	if cached, ok := f.cache[logCtx]; ok {
 		return cached, nil
 	}
 
 	nodeName, err := f.r.Resolve(ctx, swarm.Node{}, logCtx.nodeID)
 	if err != nil {
-		return "", err
+		return logSource{}, err
 	}
 
 	serviceName, err := f.r.Resolve(ctx, swarm.Service{}, logCtx.serviceID)
 	if err != nil {
-		return "", err
+		return logSource{}, err
 	}
 
 	task, _, err := f.client.TaskInspectWithRaw(ctx, logCtx.taskID)
 	if err != nil {
-		return "", err
+		return logSource{}, err
 	}
 
 	taskName := fmt.Sprintf("%s.%d", serviceName, task.Slot)
@@ -221,7 +276,7 @@ func (f *taskFormatter) format(ctx context.Context, logCtx logContext) (string,
 	if paddingCount > 0 {
 		padding = strings.Repeat(" ", paddingCount)
 	}
-	formatted := taskName + "@" + nodeName + padding
+	formatted := logSource{taskName: taskName, nodeName: nodeName, padding: padding}
 	f.cache[logCtx] = formatted
 	return formatted, nil
 }
@@ -231,6 +286,10 @@ type logWriter struct {
 	opts *logsOptions
 	f    *taskFormatter
 	w    io.Writer
+
+	// printer formats and filters the log lines, if set.
+	printer *logstream.Printer
+	stream  string
 }
 
 func (lw *logWriter) Write(buf []byte) (int, error) {
@@ -245,7 +304,8 @@ func (lw *logWriter) Write(buf []byte) (int, error) {
 	// spaces. if there is a timestamp, details will be 2nd (`index 1)
 	detailsIndex := 0
 	numParts := 2
-	if lw.opts.timestamps {
+	timestamps := lw.opts.timestamps || lw.printer != nil
+	if timestamps {
 		detailsIndex++
 		numParts++
 	}
@@ -268,18 +328,38 @@ func (lw *logWriter) Write(buf []byte) (int, error) {
 		return 0, err
 	}
 
+	// add the context, nice and formatted
+	formatted, err := lw.f.format(lw.ctx, logCtx)
+	if err != nil {
+		return 0, err
+	}
+
+	if lw.printer != nil {
+		e := logstream.Entry{
+			Container: formatted.taskName,
+			Node:      formatted.nodeName,
+			Stream:    lw.stream,
+			Message:   string(parts[detailsIndex+1]),
+		}
+		if len(details) > 0 {
+			e.Details = details
+		}
+		if ts, err := time.Parse(time.RFC3339Nano, string(parts[0])); err == nil {
+			e.Timestamp = ts
+		}
+		if err := lw.printer.Print(e, formatted.String()+"    | "); err != nil {
+			return 0, err
+		}
+		return len(buf), nil
+	}
+
 	output := []byte{}
 	// if we included timestamps, add them to the front
 	if lw.opts.timestamps {
 		output = append(output, parts[0]...)
 		output = append(output, ' ')
 	}
-	// add the context, nice and formatted
-	formatted, err := lw.f.format(lw.ctx, logCtx)
-	if err != nil {
-		return 0, err
-	}
-	output = append(output, []byte(formatted+"    | ")...)
+	output = append(output, []byte(formatted.String()+"    | ")...)
 	// if the user asked for details, add them to be log message
 	if lw.opts.details {
 		// ugh i hate this it's basically a dupe of api/server/httputils/write_log_stream.go:stringAttrs()
@@ -18,23 +18,33 @@
 type fakeClient struct {
 	client.Client
 
-	version            string
-	containerListFunc  func(context.Context, client.ContainerListOptions) ([]container.Summary, error)
-	containerPruneFunc func(ctx context.Context, pruneFilters filters.Args) (container.PruneReport, error)
-	eventsFn           func(context.Context, client.EventsListOptions) (<-chan events.Message, <-chan error)
-	imageListFunc      func(ctx context.Context, options client.ImageListOptions) ([]image.Summary, error)
-	infoFunc           func(ctx context.Context) (system.Info, error)
-	networkListFunc    func(ctx context.Context, options client.NetworkListOptions) ([]network.Summary, error)
-	networkPruneFunc   func(ctx context.Context, pruneFilter filters.Args) (network.PruneReport, error)
-	nodeListFunc       func(ctx context.Context, options client.NodeListOptions) ([]swarm.Node, error)
-	serverVersion      func(ctx context.Context) (types.Version, error)
-	volumeListFunc     func(ctx context.Context, options client.VolumeListOptions) (volume.ListResponse, error)
+	version              string
+	containerInspectFunc func(ctx context.Context, containerID string) (container.InspectResponse, error)
+	containerListFunc    func(context.Context, client.ContainerListOptions) ([]container.Summary, error)
+	containerPruneFunc   func(ctx context.Context, pruneFilters filters.Args) (container.PruneReport, error)
+	diskUsageFunc        func(ctx context.Context, options client.DiskUsageOptions) (system.DiskUsage, error)
+	eventsFn             func(context.Context, client.EventsListOptions) (<-chan events.Message, <-chan error)
+	imageListFunc        func(ctx context.Context, options client.ImageListOptions) ([]image.Summary, error)
+	imagesPruneFunc      func(ctx context.Context, pruneFilter filters.Args) (image.PruneReport, error)
+	infoFunc             func(ctx context.Context) (system.Info, error)
+	networkListFunc      func(ctx context.Context, options client.NetworkListOptions) ([]network.Summary, error)
+	networkPruneFunc     func(ctx context.Context, pruneFilter filters.Args) (network.PruneReport, error)
+	nodeListFunc         func(ctx context.Context, options client.NodeListOptions) ([]swarm.Node, error)
+	serverVersion        func(ctx context.Context) (types.Version, error)
+	volumeListFunc       func(ctx context.Context, options client.VolumeListOptions) (volume.ListResponse, error)
 }
 
 func (cli *fakeClient) ClientVersion() string {
 	return cli.version
 }
 
+func (cli *fakeClient) ContainerInspect(ctx context.Context, containerID string) (container.InspectResponse, error) {
+	if cli.containerInspectFunc != nil {
+		return cli.containerInspectFunc(ctx, containerID)
+	}
+	return container.InspectResponse{}, nil
+}
+
 func (cli *fakeClient) ContainerList(ctx context.Context, options client.ContainerListOptions) ([]container.Summary, error) {
 	if cli.containerListFunc != nil {
 		return cli.containerListFunc(ctx, options)
@@ -49,6 +59,13 @@ func (cli *fakeClient) ContainersPrune(ctx context.Context, pruneFilters filters
 	return container.PruneReport{}, nil
 }
 
+func (cli *fakeClient) DiskUsage(ctx context.Context, options client.DiskUsageOptions) (system.DiskUsage, error) {
+	if cli.diskUsageFunc != nil {
+		return cli.diskUsageFunc(ctx, options)
+	}
+	return system.DiskUsage{}, nil
+}
+
 func (cli *fakeClient) Events(ctx context.Context, opts client.EventsListOptions) (<-chan events.Message, <-chan error) {
 	return cli.eventsFn(ctx, opts)
 }
@@ -60,6 +77,13 @@ func (cli *fakeClient) ImageList(ctx context.Context, options client.ImageListOp
 	return []image.Summary{}, nil
 }
 
+func (cli *fakeClient) ImagesPrune(ctx context.Context, pruneFilter filters.Args) (image.PruneReport, error) {
+	if cli.imagesPruneFunc != nil {
+		return cli.imagesPruneFunc(ctx, pruneFilter)
+	}
+	return image.PruneReport{}, nil
+}
+
 func (cli *fakeClient) Info(ctx context.Context) (system.Info, error) {
 	if cli.infoFunc != nil {
 		return cli.infoFunc(ctx)
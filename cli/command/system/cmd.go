@@ -30,6 +30,7 @@ func newSystemCommand(dockerCLI command.Cli) *cobra.Command {
 		newInfoCommand(dockerCLI),
 		newDiskUsageCommand(dockerCLI),
 		newPruneCommand(dockerCLI),
+		newDiagnoseCommand(dockerCLI),
 		newDialStdioCommand(dockerCLI),
 	)
 
@@ -2,6 +2,8 @@
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
@@ -14,6 +16,9 @@
 type diskUsageOptions struct {
 	verbose bool
 	format  string
+	save    bool
+	history bool
+	groupBy string
 }
 
 // newDiskUsageCommand creates a new cobra.Command for `docker df`
@@ -36,11 +41,28 @@ func newDiskUsageCommand(dockerCLI command.Cli) *cobra.Command {
 
 	flags.BoolVarP(&opts.verbose, "verbose", "v", false, "Show detailed information on space usage")
 	flags.StringVar(&opts.format, "format", "", flagsHelper.FormatHelp)
+	flags.BoolVar(&opts.save, "save", false, "Save a snapshot of the disk usage to the local history")
+	flags.StringVar(&opts.groupBy, "group-by", "", `Show the disk usage of groups of objects with "--verbose" ("label=<key>" or "repository")`)
+	flags.BoolVar(&opts.history, "history", false, "Show the disk usage of each day in the local history, and the largest growth since the last snapshot")
 
 	return cmd
 }
 
 func runDiskUsage(ctx context.Context, dockerCli command.Cli, opts diskUsageOptions) error {
+	if opts.history && opts.verbose {
+		return errors.New("the --history and --verbose options cannot be used together")
+	}
+	var groupBy *formatter.DiskUsageGroupBy
+	if opts.groupBy != "" {
+		if !opts.verbose {
+			return errors.New("the --group-by option requires --verbose")
+		}
+		var err error
+		if groupBy, err = formatter.ParseDiskUsageGroupBy(opts.groupBy); err != nil {
+			return err
+		}
+	}
+
 	// TODO expose types.DiskUsageOptions.Types as flag on the command-line and/or as separate commands (docker container df / docker container usage)
 	du, err := dockerCli.Client().DiskUsage(ctx, client.DiskUsageOptions{})
 	if err != nil {
@@ -59,10 +81,29 @@ func runDiskUsage(ctx context.Context, dockerCli command.Cli, opts diskUsageOpti
 		}
 	}
 
+	snapshot := newDiskUsageSnapshot(du, bsz, time.Now())
+	if opts.history {
+		history, err := loadDiskUsageHistory(dockerCli.CurrentContext())
+		if err != nil {
+			return err
+		}
+		if err := writeDiskUsageHistory(dockerCli.Out(), opts.format, history, snapshot); err != nil {
+			return err
+		}
+		if opts.save {
+			return saveDiskUsageSnapshot(dockerCli.CurrentContext(), snapshot)
+		}
+		return nil
+	}
+
+	duFormat := formatter.NewDiskUsageFormat(format, opts.verbose)
+	if groupBy != nil {
+		duFormat = formatter.Format(format)
+	}
 	duCtx := formatter.DiskUsageContext{
 		Context: formatter.Context{
 			Output: dockerCli.Out(),
-			Format: formatter.NewDiskUsageFormat(format, opts.verbose),
+			Format: duFormat,
 		},
 		LayersSize:  du.LayersSize,
 		BuilderSize: bsz,
@@ -71,7 +112,14 @@ func runDiskUsage(ctx context.Context, dockerCli command.Cli, opts diskUsageOpti
 		Containers:  du.Containers,
 		Volumes:     du.Volumes,
 		Verbose:     opts.verbose,
+		GroupBy:     groupBy,
 	}
 
-	return duCtx.Write()
+	if err := duCtx.Write(); err != nil {
+		return err
+	}
+	if opts.save {
+		return saveDiskUsageSnapshot(dockerCli.CurrentContext(), snapshot)
+	}
+	return nil
 }
@@ -0,0 +1,393 @@
+package system
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/go-units"
+	"github.com/moby/moby/api/types/system"
+	"github.com/moby/sys/atomicwriter"
+)
+
+const (
+	// diskUsageHistoryDir is the directory in the CLI config directory in
+	// which snapshots of the disk usage are stored, in a file per context.
+	diskUsageHistoryDir = "disk-usage"
+
+	// diskUsageHistoryRetention is how long snapshots are kept for.
+	diskUsageHistoryRetention = 90 * 24 * time.Hour
+
+	// diskUsageGrowersLimit is the number of objects that are shown as the
+	// largest growers since the last snapshot.
+	diskUsageGrowersLimit = 10
+
+	defaultDiskUsageHistoryTableFormat = "table {{.Date}}\t{{.Images}}\t{{.Containers}}\t{{.Volumes}}\t{{.BuildCache}}\t{{.Total}}"
+	diskUsageGrowersTableFormat        = "table {{.Type}}\t{{.Name}}\t{{.Size}}\t{{.Change}}"
+
+	diskUsageHistoryDateFormat = "2006-01-02 15:04"
+
+	duTypeImage      = "Image"
+	duTypeContainer  = "Container"
+	duTypeVolume     = "Local Volume"
+	duTypeBuildCache = "Build Cache"
+)
+
+// diskUsageSnapshot is the disk usage at a point in time, as saved with
+// "docker system df --save". Sizes are in bytes.
+type diskUsageSnapshot struct {
+	Time       time.Time       `json:"time"`
+	Images     int64           `json:"images"`
+	Containers int64           `json:"containers"`
+	Volumes    int64           `json:"volumes"`
+	BuildCache int64           `json:"build_cache"`
+	Objects    []diskUsageItem `json:"objects,omitempty"`
+}
+
+// diskUsageItem is the size of an image, container, or volume. Images are
+// identified by their first tag, if any, so that the size of a tag that's
+// rebuilt can be compared, and their size is the size that's not shared
+// with other images.
+type diskUsageItem struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
+
+func (s diskUsageSnapshot) total() int64 {
+	return s.Images + s.Containers + s.Volumes + s.BuildCache
+}
+
+// newDiskUsageSnapshot returns a snapshot of the given disk usage.
+func newDiskUsageSnapshot(du system.DiskUsage, buildCacheSize int64, now time.Time) diskUsageSnapshot {
+	s := diskUsageSnapshot{
+		Time:       now,
+		Images:     du.LayersSize,
+		BuildCache: buildCacheSize,
+	}
+	for _, img := range du.Images {
+		name := formatter.TruncateID(img.ID)
+		if len(img.RepoTags) > 0 && img.RepoTags[0] != "<none>:<none>" {
+			name = img.RepoTags[0]
+		}
+		size := img.Size
+		if img.SharedSize > 0 {
+			size -= img.SharedSize
+		}
+		s.Objects = append(s.Objects, diskUsageItem{Type: duTypeImage, Name: name, Size: size})
+	}
+	for _, ctr := range du.Containers {
+		s.Containers += ctr.SizeRw
+		name := formatter.TruncateID(ctr.ID)
+		if names := formatter.StripNamePrefix(ctr.Names); len(names) > 0 {
+			name = names[0]
+		}
+		s.Objects = append(s.Objects, diskUsageItem{Type: duTypeContainer, Name: name, Size: ctr.SizeRw})
+	}
+	for _, v := range du.Volumes {
+		if v.UsageData == nil || v.UsageData.Size < 0 {
+			continue
+		}
+		s.Volumes += v.UsageData.Size
+		s.Objects = append(s.Objects, diskUsageItem{Type: duTypeVolume, Name: v.Name, Size: v.UsageData.Size})
+	}
+	return s
+}
+
+// diskUsageHistoryPath returns the path of the file in which snapshots of
+// the disk usage of the daemon of the given context are stored.
+func diskUsageHistoryPath(contextName string) (string, error) {
+	if contextName == "" {
+		contextName = "default"
+	}
+	return config.Path(diskUsageHistoryDir, contextName+".jsonl")
+}
+
+// loadDiskUsageHistory loads the snapshots that are stored for a context,
+// ordered by time.
+func loadDiskUsageHistory(contextName string) ([]diskUsageSnapshot, error) {
+	p, err := diskUsageHistoryPath(contextName)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var history []diskUsageSnapshot
+	dec := json.NewDecoder(bufio.NewReader(f))
+	for {
+		var s diskUsageSnapshot
+		if err := dec.Decode(&s); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("invalid disk usage history %s: %w", p, err)
+		}
+		history = append(history, s)
+	}
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].Time.Before(history[j].Time)
+	})
+	return history, nil
+}
+
+// saveDiskUsageSnapshot adds a snapshot to the history of a context, and
+// removes snapshots that are older than the retention period.
+func saveDiskUsageSnapshot(contextName string, snapshot diskUsageSnapshot) error {
+	history, err := loadDiskUsageHistory(contextName)
+	if err != nil {
+		return err
+	}
+	p, err := diskUsageHistoryPath(contextName)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	cutoff := snapshot.Time.Add(-diskUsageHistoryRetention)
+	for _, s := range append(history, snapshot) {
+		if s.Time.Before(cutoff) {
+			continue
+		}
+		if err := enc.Encode(s); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	return atomicwriter.WriteFile(p, buf.Bytes(), 0o644)
+}
+
+// dailyDiskUsage returns the last snapshot of each day, in the time zone
+// of the snapshots.
+func dailyDiskUsage(history []diskUsageSnapshot) []diskUsageSnapshot {
+	var daily []diskUsageSnapshot
+	for i, s := range history {
+		if i+1 < len(history) && sameDay(s.Time, history[i+1].Time) {
+			continue
+		}
+		daily = append(daily, s)
+	}
+	return daily
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// diskUsageGrowth is the change in size of an object since a snapshot.
+type diskUsageGrowth struct {
+	diskUsageItem
+	Change int64
+}
+
+// diskUsageGrowers returns the objects that grew the most between two
+// snapshots, including the objects that were created since.
+func diskUsageGrowers(previous, current diskUsageSnapshot, limit int) []diskUsageGrowth {
+	prev := make(map[diskUsageItem]int64, len(previous.Objects))
+	for _, o := range previous.Objects {
+		prev[diskUsageItem{Type: o.Type, Name: o.Name}] = o.Size
+	}
+	var growers []diskUsageGrowth
+	for _, o := range current.Objects {
+		change := o.Size - prev[diskUsageItem{Type: o.Type, Name: o.Name}]
+		if change > 0 {
+			growers = append(growers, diskUsageGrowth{diskUsageItem: o, Change: change})
+		}
+	}
+	if change := current.BuildCache - previous.BuildCache; change > 0 {
+		growers = append(growers, diskUsageGrowth{
+			diskUsageItem: diskUsageItem{Type: duTypeBuildCache, Size: current.BuildCache},
+			Change:        change,
+		})
+	}
+	sort.SliceStable(growers, func(i, j int) bool {
+		return growers[i].Change > growers[j].Change
+	})
+	if len(growers) > limit {
+		growers = growers[:limit]
+	}
+	return growers
+}
+
+// writeDiskUsageHistory writes the disk usage of each day in the history,
+// followed by the current disk usage. For table formats, the objects that
+// grew the most since the last snapshot are written after the history.
+func writeDiskUsageHistory(out io.Writer, format string, history []diskUsageSnapshot, current diskUsageSnapshot) error {
+	rows := append(dailyDiskUsage(history), current)
+	fmtCtx := formatter.Context{
+		Output: out,
+		Format: newDiskUsageHistoryFormat(format),
+	}
+	isTable := fmtCtx.Format.IsTable()
+
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for i, s := range rows {
+			c := &diskUsageHistoryContext{s: s, current: i == len(rows)-1}
+			if i > 0 {
+				c.prev = &rows[i-1]
+			}
+			if err := format(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	historyCtx := diskUsageHistoryContext{}
+	historyCtx.Header = formatter.SubHeaderContext{
+		"Date":       "DATE",
+		"Images":     "IMAGES",
+		"Containers": "CONTAINERS",
+		"Volumes":    "LOCAL VOLUMES",
+		"BuildCache": "BUILD CACHE",
+		"Total":      "TOTAL",
+	}
+	if err := fmtCtx.Write(&historyCtx, render); err != nil {
+		return err
+	}
+	if !isTable {
+		return nil
+	}
+
+	if len(history) == 0 {
+		_, _ = fmt.Fprintln(out, "\nNo disk usage history; save snapshots with \"docker system df --save\".")
+		return nil
+	}
+	last := history[len(history)-1]
+	growers := diskUsageGrowers(last, current, diskUsageGrowersLimit)
+	if len(growers) == 0 {
+		_, _ = fmt.Fprintf(out, "\nNo growth since %s.\n", last.Time.Format(diskUsageHistoryDateFormat))
+		return nil
+	}
+	_, _ = fmt.Fprintf(out, "\nLargest growth since %s:\n\n", last.Time.Format(diskUsageHistoryDateFormat))
+	render = func(format func(subContext formatter.SubContext) error) error {
+		for _, g := range growers {
+			if err := format(&diskUsageGrowthContext{g: g}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	growthCtx := diskUsageGrowthContext{}
+	growthCtx.Header = formatter.SubHeaderContext{
+		"Type":   "TYPE",
+		"Name":   "NAME",
+		"Size":   formatter.SizeHeader,
+		"Change": "CHANGE",
+	}
+	fmtCtx = formatter.Context{Output: out, Format: diskUsageGrowersTableFormat}
+	return fmtCtx.Write(&growthCtx, render)
+}
+
+func newDiskUsageHistoryFormat(source string) formatter.Format {
+	if source == "" || source == formatter.TableFormatKey {
+		return defaultDiskUsageHistoryTableFormat
+	}
+	return formatter.Format(source)
+}
+
+type diskUsageHistoryContext struct {
+	formatter.HeaderContext
+	s       diskUsageSnapshot
+	prev    *diskUsageSnapshot
+	current bool
+}
+
+func (c *diskUsageHistoryContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *diskUsageHistoryContext) Date() string {
+	if c.current {
+		return "now"
+	}
+	return c.s.Time.Format(diskUsageHistoryDateFormat)
+}
+
+func (c *diskUsageHistoryContext) Images() string {
+	return c.size(func(s diskUsageSnapshot) int64 { return s.Images })
+}
+
+func (c *diskUsageHistoryContext) Containers() string {
+	return c.size(func(s diskUsageSnapshot) int64 { return s.Containers })
+}
+
+func (c *diskUsageHistoryContext) Volumes() string {
+	return c.size(func(s diskUsageSnapshot) int64 { return s.Volumes })
+}
+
+func (c *diskUsageHistoryContext) BuildCache() string {
+	return c.size(func(s diskUsageSnapshot) int64 { return s.BuildCache })
+}
+
+func (c *diskUsageHistoryContext) Total() string {
+	return c.size(diskUsageSnapshot.total)
+}
+
+// size returns the size, and the change since the previous row if it
+// changed.
+func (c *diskUsageHistoryContext) size(get func(diskUsageSnapshot) int64) string {
+	size := units.HumanSize(float64(get(c.s)))
+	if c.prev == nil {
+		return size
+	}
+	if change := get(c.s) - get(*c.prev); change != 0 {
+		return size + " (" + formatSizeChange(change) + ")"
+	}
+	return size
+}
+
+type diskUsageGrowthContext struct {
+	formatter.HeaderContext
+	g diskUsageGrowth
+}
+
+func (c *diskUsageGrowthContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *diskUsageGrowthContext) Type() string {
+	return c.g.Type
+}
+
+func (c *diskUsageGrowthContext) Name() string {
+	if c.g.Name == "" {
+		return "-"
+	}
+	return c.g.Name
+}
+
+func (c *diskUsageGrowthContext) Size() string {
+	return units.HumanSize(float64(c.g.Size))
+}
+
+func (c *diskUsageGrowthContext) Change() string {
+	return formatSizeChange(c.g.Change)
+}
+
+// formatSizeChange formats a change in size, such as "+1.5GB" or "-300MB".
+func formatSizeChange(change int64) string {
+	if change < 0 {
+		return "-" + units.HumanSize(float64(-change))
+	}
+	return "+" + units.HumanSize(float64(change))
+}
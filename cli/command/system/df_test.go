@@ -0,0 +1,194 @@
+package system
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/internal/test"
+	"github.com/moby/moby/api/types/build"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/image"
+	"github.com/moby/moby/api/types/system"
+	"github.com/moby/moby/api/types/volume"
+	"github.com/moby/moby/client"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func withDiskUsageHistoryDir(t *testing.T) string {
+	t.Helper()
+	orig := config.Dir()
+	dir := t.TempDir()
+	config.SetDir(dir)
+	t.Cleanup(func() { config.SetDir(orig) })
+	return filepath.Join(dir, diskUsageHistoryDir)
+}
+
+func diskUsageTestClient() *fakeClient {
+	return &fakeClient{diskUsageFunc: func(context.Context, client.DiskUsageOptions) (system.DiskUsage, error) {
+		return system.DiskUsage{
+			LayersSize: 1500e6,
+			Images: []*image.Summary{
+				{ID: "sha256:1111111111111111", RepoTags: []string{"app:latest"}, Size: 800e6, SharedSize: 200e6, Containers: 1},
+				{ID: "sha256:2222222222222222", RepoTags: []string{"nginx:alpine"}, Size: 200e6, SharedSize: 200e6},
+			},
+			Containers: []*container.Summary{
+				{ID: "aaaaaaaaaaaaaaaa", Names: []string{"/web"}, SizeRw: 10e6, State: container.StateRunning},
+			},
+			Volumes: []*volume.Volume{
+				{Name: "data", UsageData: &volume.UsageData{Size: 500e6, RefCount: 1}},
+			},
+			BuildCache: []*build.CacheRecord{
+				{ID: "cache1", Size: 2000e6},
+			},
+		}, nil
+	}}
+}
+
+func TestDiskUsageSave(t *testing.T) {
+	historyDir := withDiskUsageHistoryDir(t)
+
+	fakeCLI := test.NewFakeCli(diskUsageTestClient())
+	cmd := newDiskUsageCommand(fakeCLI)
+	cmd.SetArgs([]string{"--save"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Contains(fakeCLI.OutBuffer().String(), "Local Volumes"))
+
+	data, err := os.ReadFile(filepath.Join(historyDir, "default.jsonl"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(strings.Count(string(data), "\n"), 1))
+
+	history, err := loadDiskUsageHistory("")
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(history, 1))
+	s := history[0]
+	assert.Check(t, is.Equal(s.Images, int64(1500e6)))
+	assert.Check(t, is.Equal(s.Containers, int64(10e6)))
+	assert.Check(t, is.Equal(s.Volumes, int64(500e6)))
+	assert.Check(t, is.Equal(s.BuildCache, int64(2000e6)))
+	assert.Check(t, is.DeepEqual(s.Objects, []diskUsageItem{
+		{Type: duTypeImage, Name: "app:latest", Size: 600e6},
+		{Type: duTypeImage, Name: "nginx:alpine", Size: 0},
+		{Type: duTypeContainer, Name: "web", Size: 10e6},
+		{Type: duTypeVolume, Name: "data", Size: 500e6},
+	}))
+}
+
+func TestDiskUsageSaveRetention(t *testing.T) {
+	withDiskUsageHistoryDir(t)
+
+	now := time.Now()
+	assert.NilError(t, saveDiskUsageSnapshot("remote", diskUsageSnapshot{Time: now.Add(-100 * 24 * time.Hour)}))
+	assert.NilError(t, saveDiskUsageSnapshot("remote", diskUsageSnapshot{Time: now.Add(-10 * 24 * time.Hour)}))
+	assert.NilError(t, saveDiskUsageSnapshot("remote", diskUsageSnapshot{Time: now}))
+
+	history, err := loadDiskUsageHistory("remote")
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(history, 2))
+
+	history, err = loadDiskUsageHistory("default")
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(history, 0))
+}
+
+func TestDiskUsageHistory(t *testing.T) {
+	withDiskUsageHistoryDir(t)
+
+	day1 := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	for _, s := range []diskUsageSnapshot{
+		{
+			Time: day1, Images: 1000e6, Volumes: 400e6, BuildCache: 1000e6,
+			Objects: []diskUsageItem{
+				{Type: duTypeImage, Name: "app:latest", Size: 300e6},
+				{Type: duTypeVolume, Name: "data", Size: 400e6},
+			},
+		},
+		{
+			Time: day1.Add(9 * time.Hour), Images: 1200e6, Containers: 5e6, Volumes: 450e6, BuildCache: 1500e6,
+			Objects: []diskUsageItem{
+				{Type: duTypeImage, Name: "app:latest", Size: 400e6},
+				{Type: duTypeContainer, Name: "web", Size: 5e6},
+				{Type: duTypeVolume, Name: "data", Size: 450e6},
+			},
+		},
+		{
+			Time: day1.Add(33 * time.Hour), Images: 1200e6, Containers: 5e6, Volumes: 450e6, BuildCache: 1500e6,
+			Objects: []diskUsageItem{
+				{Type: duTypeImage, Name: "app:latest", Size: 400e6},
+				{Type: duTypeImage, Name: "nginx:alpine", Size: 0},
+				{Type: duTypeContainer, Name: "web", Size: 5e6},
+				{Type: duTypeVolume, Name: "data", Size: 450e6},
+			},
+		},
+	} {
+		assert.NilError(t, saveDiskUsageSnapshot("", s))
+	}
+
+	fakeCLI := test.NewFakeCli(diskUsageTestClient())
+	cmd := newDiskUsageCommand(fakeCLI)
+	cmd.SetArgs([]string{"--history"})
+	assert.NilError(t, cmd.Execute())
+
+	expected := `DATE               IMAGES           CONTAINERS    LOCAL VOLUMES   BUILD CACHE    TOTAL
+2024-05-01 18:00   1.2GB            5MB           450MB           1.5GB          3.155GB
+2024-05-02 18:00   1.2GB            5MB           450MB           1.5GB          3.155GB
+now                1.5GB (+300MB)   10MB (+5MB)   500MB (+50MB)   2GB (+500MB)   4.01GB (+855MB)
+
+Largest growth since 2024-05-02 18:00:
+
+TYPE           NAME         SIZE      CHANGE
+Build Cache    -            2GB       +500MB
+Image          app:latest   600MB     +200MB
+Local Volume   data         500MB     +50MB
+Container      web          10MB      +5MB
+`
+	assert.Check(t, is.Equal(fakeCLI.OutBuffer().String(), expected))
+}
+
+func TestDiskUsageHistoryEmpty(t *testing.T) {
+	withDiskUsageHistoryDir(t)
+
+	fakeCLI := test.NewFakeCli(diskUsageTestClient())
+	cmd := newDiskUsageCommand(fakeCLI)
+	cmd.SetArgs([]string{"--history", "--format", "{{.Date}}: {{.Total}}"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(fakeCLI.OutBuffer().String(), "now: 4.01GB\n"))
+}
+
+func TestDiskUsageHistoryVerbose(t *testing.T) {
+	cmd := newDiskUsageCommand(test.NewFakeCli(diskUsageTestClient()))
+	cmd.SetArgs([]string{"--history", "--verbose"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), "the --history and --verbose options cannot be used together"))
+}
+
+func TestDiskUsageGroupByInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		args        []string
+		expectedErr string
+	}{
+		{args: []string{"--group-by", "repository"}, expectedErr: "the --group-by option requires --verbose"},
+		{args: []string{"-v", "--group-by", "tag"}, expectedErr: `invalid group: must be "label=<key>" or "repository"`},
+	} {
+		cmd := newDiskUsageCommand(test.NewFakeCli(diskUsageTestClient()))
+		cmd.SetArgs(tc.args)
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		assert.Check(t, is.Error(cmd.Execute(), tc.expectedErr), tc.args)
+	}
+}
+
+func TestDiskUsageGroupBy(t *testing.T) {
+	fakeCLI := test.NewFakeCli(diskUsageTestClient())
+	cmd := newDiskUsageCommand(fakeCLI)
+	cmd.SetArgs([]string{"-v", "--group-by", "repository", "--format", "{{.Group}}: {{.TotalSize}}"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(fakeCLI.OutBuffer().String(), "app: 600MB\nnginx: 0B\n<none>: 2.51GB\n"))
+}
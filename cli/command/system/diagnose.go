@@ -0,0 +1,357 @@
+package system
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/docker/cli/cli"
+	pluginmanager "github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/cli/cli/version"
+	"github.com/docker/cli/internal/timestamp"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/events"
+	"github.com/moby/moby/client"
+	"github.com/moby/sys/atomicwriter"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// diagnoseManifestFile is the name of the file in the archive that
+	// describes the contents of the archive.
+	diagnoseManifestFile = "manifest.json"
+
+	// diagnoseEventsTimeout is the maximum time to wait for the daemon to
+	// send the events to include in the archive.
+	diagnoseEventsTimeout = 30 * time.Second
+)
+
+type diagnoseOptions struct {
+	output string
+	since  string
+}
+
+// diagnoseManifest describes the contents of a diagnostic archive.
+type diagnoseManifest struct {
+	Time          time.Time
+	ClientVersion string
+	Context       string
+	EventsSince   time.Time
+	Files         []string
+	Errors        []string `json:",omitempty"`
+}
+
+// diagnoseFile is a file in a diagnostic archive.
+type diagnoseFile struct {
+	name string
+	data []byte
+}
+
+// diagnoseBundle collects the files of a diagnostic archive. Failures to
+// collect information are recorded in the manifest, so that the other
+// information is still collected.
+type diagnoseBundle struct {
+	manifest diagnoseManifest
+	files    []diagnoseFile
+}
+
+func (b *diagnoseBundle) add(name string, data []byte) {
+	b.manifest.Files = append(b.manifest.Files, name)
+	b.files = append(b.files, diagnoseFile{name: name, data: data})
+}
+
+func (b *diagnoseBundle) addJSON(name string, v any) error {
+	redactedValue, err := redactJSON(v)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "    ")
+	if err := enc.Encode(redactedValue); err != nil {
+		return err
+	}
+	b.add(name, buf.Bytes())
+	return nil
+}
+
+func (b *diagnoseBundle) addError(what string, err error) {
+	b.manifest.Errors = append(b.manifest.Errors, fmt.Sprintf("failed to collect %s: %v", what, err))
+}
+
+// newDiagnoseCommand creates a new cobra.Command for `docker system diagnose`
+func newDiagnoseCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts diagnoseOptions
+
+	cmd := &cobra.Command{
+		Use:   "diagnose [OPTIONS]",
+		Short: "Collect diagnostic information into an archive",
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDiagnose(cmd.Context(), cmd, dockerCLI, opts)
+		},
+		ValidArgsFunction:     cobra.NoFileCompletions,
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.output, "output", "o", "", `Write the archive to a file (default "docker-diagnose-<time>.tar.gz"), or "-" for STDOUT`)
+	flags.StringVar(&opts.since, "since", "1h", "Include events created since timestamp")
+
+	cmd.AddCommand(newDiagnoseViewCommand(dockerCLI))
+	return cmd
+}
+
+func runDiagnose(ctx context.Context, cmd *cobra.Command, dockerCLI command.Cli, opts diagnoseOptions) error {
+	now := time.Now()
+	since, err := timestamp.Parse(opts.since, now)
+	if err != nil {
+		return fmt.Errorf("invalid value for --since: %w", err)
+	}
+	if opts.output == "-" && dockerCLI.Out().IsTerminal() {
+		return errors.New("cowardly refusing to write to a terminal. Use the -o flag or redirect")
+	}
+
+	bundle := collectDiagnostics(ctx, cmd, dockerCLI, now, since)
+	for _, e := range bundle.manifest.Errors {
+		_, _ = fmt.Fprintln(dockerCLI.Err(), "WARNING:", e)
+	}
+
+	if opts.output == "-" {
+		return bundle.write(dockerCLI.Out())
+	}
+	output := opts.output
+	if output == "" {
+		output = "docker-diagnose-" + now.Format("20060102-150405") + ".tar.gz"
+	}
+	w, err := atomicwriter.New(output, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to write diagnostic archive: %w", err)
+	}
+	if err := bundle.write(w); err != nil {
+		_ = w.Close()
+		return fmt.Errorf("failed to write diagnostic archive: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to write diagnostic archive: %w", err)
+	}
+	_, _ = fmt.Fprintln(dockerCLI.Out(), "Diagnostic archive written to", output)
+	return nil
+}
+
+// collectDiagnostics collects the client and daemon information to include
+// in a diagnostic archive. Credentials are redacted from all collected
+// information.
+func collectDiagnostics(ctx context.Context, cmd *cobra.Command, dockerCLI command.Cli, now, since time.Time) *diagnoseBundle {
+	bundle := &diagnoseBundle{
+		manifest: diagnoseManifest{
+			Time:          now.UTC(),
+			ClientVersion: version.Version,
+			Context:       dockerCLI.CurrentContext(),
+			EventsSince:   since.UTC(),
+		},
+	}
+
+	// The output of "docker version" and "docker info" is included as is
+	// printed, including any errors, such as the daemon not being reachable.
+	out, err := captureOutput(dockerCLI, func(c command.Cli) error {
+		return runVersion(ctx, c, &versionOptions{})
+	})
+	bundle.add("version.txt", []byte(redactText(out)))
+	if err != nil {
+		bundle.addError("version", err)
+	}
+	out, err = captureOutput(dockerCLI, func(c command.Cli) error {
+		return runInfo(ctx, cmd, c, &infoOptions{})
+	})
+	bundle.add("info.txt", []byte(redactText(out)))
+	if err != nil {
+		bundle.addError("info", err)
+	}
+
+	if plugins, err := pluginmanager.ListPlugins(dockerCLI, cmd.Root()); err != nil {
+		bundle.addError("plugins", err)
+	} else if err := bundle.addJSON("plugins.json", plugins); err != nil {
+		bundle.addError("plugins", err)
+	}
+
+	// Only the metadata of contexts is included; TLS material is stored
+	// separately in the context store, and is never read.
+	if s := dockerCLI.ContextStore(); s != nil {
+		if contexts, err := s.List(); err != nil {
+			bundle.addError("contexts", err)
+		} else if err := bundle.addJSON("contexts.json", contexts); err != nil {
+			bundle.addError("contexts", err)
+		}
+	}
+
+	if err := bundle.addJSON("config.json", dockerCLI.ConfigFile()); err != nil {
+		bundle.addError("configuration", err)
+	}
+	bundle.add("environment.txt", []byte(diagnoseEnvironment(os.Environ())))
+
+	if containers, err := inspectAllContainers(ctx, dockerCLI); err != nil {
+		bundle.addError("containers", err)
+	} else if err := bundle.addJSON("containers.json", containers); err != nil {
+		bundle.addError("containers", err)
+	}
+
+	// Events that were received before an error occurred are included.
+	evts, err := collectEvents(ctx, dockerCLI, since, now)
+	if err != nil {
+		bundle.addError("events", err)
+	}
+	if err == nil || len(evts) > 0 {
+		if err := bundle.addEvents("events.jsonl", evts); err != nil {
+			bundle.addError("events", err)
+		}
+	}
+	return bundle
+}
+
+// captureCli is a CLI that writes its output to a buffer, to include the
+// output of commands in a diagnostic archive.
+type captureCli struct {
+	command.Cli
+	out *streams.Out
+}
+
+func (c *captureCli) Out() *streams.Out { return c.out }
+
+func (c *captureCli) Err() *streams.Out { return c.out }
+
+// captureOutput returns the output that run writes to both the standard
+// output and the standard error of the CLI.
+func captureOutput(dockerCLI command.Cli, run func(command.Cli) error) (string, error) {
+	var buf bytes.Buffer
+	err := run(&captureCli{Cli: dockerCLI, out: streams.NewOut(&buf)})
+	return buf.String(), err
+}
+
+// diagnoseEnvironment returns the environment variables that affect the
+// CLI, such as DOCKER_HOST, and proxy settings.
+func diagnoseEnvironment(environ []string) string {
+	var env []string
+	for _, e := range environ {
+		name, _, _ := strings.Cut(e, "=")
+		upper := strings.ToUpper(name)
+		if strings.HasPrefix(upper, "DOCKER_") || strings.HasSuffix(upper, "_PROXY") {
+			env = append(env, e)
+		}
+	}
+	sort.Strings(env)
+	var buf strings.Builder
+	for _, e := range redactEnv(env) {
+		buf.WriteString(e)
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+// inspectAllContainers returns the details of all containers, including
+// their state and health.
+func inspectAllContainers(ctx context.Context, dockerCLI command.Cli) ([]container.InspectResponse, error) {
+	containers, err := dockerCLI.Client().ContainerList(ctx, client.ContainerListOptions{All: true})
+	if err != nil {
+		return nil, err
+	}
+	details := make([]container.InspectResponse, 0, len(containers))
+	for _, c := range containers {
+		ctr, err := dockerCLI.Client().ContainerInspect(ctx, c.ID)
+		if err != nil {
+			if errdefs.IsNotFound(err) {
+				// the container was removed in the meantime
+				continue
+			}
+			return nil, err
+		}
+		details = append(details, ctr)
+	}
+	return details, nil
+}
+
+// collectEvents returns the events that occurred between since and until.
+func collectEvents(ctx context.Context, dockerCLI command.Cli, since, until time.Time) ([]events.Message, error) {
+	ctx, cancel := context.WithTimeout(ctx, diagnoseEventsTimeout)
+	defer cancel()
+	evts, errs := dockerCLI.Client().Events(ctx, client.EventsListOptions{
+		Since: strconv.FormatInt(since.Unix(), 10),
+		Until: strconv.FormatInt(until.Unix(), 10),
+	})
+	var collected []events.Message
+	for {
+		select {
+		case event := <-evts:
+			collected = append(collected, event)
+		case err := <-errs:
+			if err == io.EOF {
+				return collected, nil
+			}
+			return collected, err
+		}
+	}
+}
+
+// addEvents adds events as JSON lines, so that they can be replayed with
+// "docker events --replay".
+func (b *diagnoseBundle) addEvents(name string, evts []events.Message) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	for _, event := range evts {
+		redactedEvent, err := redactJSON(event)
+		if err != nil {
+			return err
+		}
+		if err := enc.Encode(redactedEvent); err != nil {
+			return err
+		}
+	}
+	b.add(name, buf.Bytes())
+	return nil
+}
+
+// write writes the bundle as a gzip-compressed tar archive, starting with
+// the manifest.
+func (b *diagnoseBundle) write(w io.Writer) error {
+	manifest, err := json.MarshalIndent(b.manifest, "", "    ")
+	if err != nil {
+		return err
+	}
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	files := append([]diagnoseFile{{name: diagnoseManifestFile, data: append(manifest, '\n')}}, b.files...)
+	for _, f := range files {
+		hdr := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     f.name,
+			Mode:     0o600,
+			Size:     int64(len(f.data)),
+			ModTime:  b.manifest.Time,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
@@ -0,0 +1,194 @@
+package system
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter/tabwriter"
+	"github.com/docker/go-units"
+	"github.com/moby/moby/api/types/container"
+	"github.com/spf13/cobra"
+)
+
+// maxDiagnoseFileSize is the maximum size of a file in a diagnostic archive
+// that is read by the viewer.
+const maxDiagnoseFileSize = 256 << 20
+
+type diagnoseViewOptions struct {
+	archive string
+	files   []string
+}
+
+// diagnoseArchive is the contents of a diagnostic archive.
+type diagnoseArchive struct {
+	manifest diagnoseManifest
+	files    []diagnoseFile
+}
+
+func (a *diagnoseArchive) file(name string) (diagnoseFile, bool) {
+	for _, f := range a.files {
+		if f.name == name {
+			return f, true
+		}
+	}
+	return diagnoseFile{}, false
+}
+
+// newDiagnoseViewCommand creates a new cobra.Command for `docker system diagnose view`
+func newDiagnoseViewCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts diagnoseViewOptions
+
+	return &cobra.Command{
+		Use:   "view ARCHIVE [FILE...]",
+		Short: "Display the contents of a diagnostic archive",
+		Args:  cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.archive = args[0]
+			opts.files = args[1:]
+			return runDiagnoseView(dockerCLI, opts)
+		},
+		DisableFlagsInUseLine: true,
+	}
+}
+
+func runDiagnoseView(dockerCLI command.Cli, opts diagnoseViewOptions) error {
+	var in io.Reader = dockerCLI.In()
+	if opts.archive != "-" {
+		f, err := os.Open(opts.archive)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	archive, err := readDiagnoseArchive(in)
+	if err != nil {
+		return err
+	}
+
+	if len(opts.files) == 0 {
+		return printDiagnoseSummary(dockerCLI.Out(), archive)
+	}
+	for _, name := range opts.files {
+		f, ok := archive.file(name)
+		if !ok {
+			return fmt.Errorf("no such file in diagnostic archive: %s", name)
+		}
+		if _, err := dockerCLI.Out().Write(f.data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readDiagnoseArchive reads a diagnostic archive as written by
+// "docker system diagnose".
+func readDiagnoseArchive(r io.Reader) (*diagnoseArchive, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid diagnostic archive: %w", err)
+	}
+	defer gr.Close()
+
+	archive := &diagnoseArchive{}
+	var hasManifest bool
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid diagnostic archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if hdr.Size > maxDiagnoseFileSize {
+			return nil, fmt.Errorf("invalid diagnostic archive: %s is too large", hdr.Name)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid diagnostic archive: %w", err)
+		}
+		if hdr.Name == diagnoseManifestFile {
+			if err := json.Unmarshal(data, &archive.manifest); err != nil {
+				return nil, fmt.Errorf("invalid diagnostic archive: %w", err)
+			}
+			hasManifest = true
+		}
+		archive.files = append(archive.files, diagnoseFile{name: hdr.Name, data: data})
+	}
+	if !hasManifest {
+		return nil, errors.New("invalid diagnostic archive: no " + diagnoseManifestFile + " found")
+	}
+	return archive, nil
+}
+
+// printDiagnoseSummary prints when and where the archive was collected, the
+// files in the archive, the errors that occurred while collecting it, and the
+// state of containers.
+func printDiagnoseSummary(out io.Writer, archive *diagnoseArchive) error {
+	m := archive.manifest
+	w := tabwriter.NewWriter(out, 20, 1, 3, ' ', 0)
+	_, _ = fmt.Fprintf(w, "Collected:\t%s\n", m.Time.Format(time.RFC3339))
+	_, _ = fmt.Fprintf(w, "Client version:\t%s\n", m.ClientVersion)
+	_, _ = fmt.Fprintf(w, "Context:\t%s\n", m.Context)
+	_, _ = fmt.Fprintf(w, "Events since:\t%s\n", m.EventsSince.Format(time.RFC3339))
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintln(out, "\nFiles:")
+	w = tabwriter.NewWriter(out, 0, 1, 3, ' ', 0)
+	for _, f := range archive.files {
+		_, _ = fmt.Fprintf(w, "  %s\t%s\n", f.name, units.HumanSize(float64(len(f.data))))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(m.Errors) > 0 {
+		_, _ = fmt.Fprintln(out, "\nErrors:")
+		for _, e := range m.Errors {
+			_, _ = fmt.Fprintln(out, "  "+e)
+		}
+	}
+
+	f, ok := archive.file("containers.json")
+	if !ok {
+		return nil
+	}
+	var containers []container.InspectResponse
+	if err := json.Unmarshal(f.data, &containers); err != nil {
+		return fmt.Errorf("invalid diagnostic archive: containers.json: %w", err)
+	}
+	_, _ = fmt.Fprintln(out, "\nContainers:")
+	w = tabwriter.NewWriter(out, 0, 1, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "  NAME\tIMAGE\tSTATE\tEXIT CODE\tHEALTH")
+	for _, c := range containers {
+		var image, state, exitCode, health string
+		if c.Config != nil {
+			image = c.Config.Image
+		}
+		if c.State != nil {
+			state = string(c.State.Status)
+			exitCode = strconv.Itoa(c.State.ExitCode)
+			if c.State.Health != nil {
+				health = string(c.State.Health.Status)
+			}
+		}
+		_, _ = fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", strings.TrimPrefix(c.Name, "/"), image, state, exitCode, health)
+	}
+	return w.Flush()
+}
@@ -2,8 +2,11 @@
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"os"
 	"sort"
 	"strings"
 	"text/template"
@@ -21,10 +24,17 @@
 )
 
 type eventsOptions struct {
-	since  string
-	until  string
-	filter opts.FilterOpt
-	format string
+	since   string
+	until   string
+	filter  opts.FilterOpt
+	format  string
+	record  string
+	replay  string
+	summary bool
+
+	exec            string
+	execConcurrency int
+	execDebounce    time.Duration
 }
 
 // newEventsCommand creates a new cobra.Command for `docker events`
@@ -50,6 +60,12 @@ func newEventsCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.StringVar(&options.until, "until", "", "Stream events until this timestamp")
 	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
 	flags.StringVar(&options.format, "format", "", flagsHelper.InspectFormatHelp) // using the same flag description as "inspect" commands for now.
+	flags.StringVar(&options.record, "record", "", "Record events to a file as JSON lines")
+	flags.StringVar(&options.replay, "replay", "", `Replay events recorded with "--record" from a file ("-" for STDIN) instead of the server`)
+	flags.BoolVar(&options.summary, "summary", false, "Print the number of events by type, action, and actor instead of the events")
+	flags.StringVar(&options.exec, "exec", "", "Run a command for each event instead of printing the event; arguments can be templates (e.g. '{{.Actor.ID}}')")
+	flags.IntVar(&options.execConcurrency, "exec-concurrency", 1, "Maximum number of commands to run at the same time with --exec")
+	flags.DurationVar(&options.execDebounce, "exec-debounce", 0, "Run the command once for events of the same type, action, and object within the given duration of each other")
 
 	_ = cmd.RegisterFlagCompletionFunc("filter", completeEventFilters(dockerCLI))
 
@@ -57,13 +73,90 @@ func newEventsCommand(dockerCLI command.Cli) *cobra.Command {
 }
 
 func runEvents(ctx context.Context, dockerCLI command.Cli, options *eventsOptions) error {
-	tmpl, err := makeTemplate(options.format)
+	if options.record != "" && options.replay != "" {
+		return errors.New("the --record and --replay options cannot be used together")
+	}
+
+	if options.exec == "" && (options.execConcurrency != 1 || options.execDebounce != 0) {
+		return errors.New("the --exec-concurrency and --exec-debounce options require a command to be set with --exec")
+	}
+	if options.exec != "" && options.summary {
+		return errors.New("the --exec and --summary options cannot be used together")
+	}
+
+	var summary *eventsSummary
+	var executor *eventExecutor
+	var handle func(events.Message) error
+	switch {
+	case options.exec != "":
+		var err error
+		executor, err = newEventExecutor(ctx, options.exec, options.execConcurrency, options.execDebounce, dockerCLI.Out(), dockerCLI.Err())
+		if err != nil {
+			return err
+		}
+		handle = executor.handle
+	case options.summary:
+		summary = newEventsSummary()
+		handle = summary.add
+	default:
+		tmpl, err := makeTemplate(options.format)
+		if err != nil {
+			return cli.StatusError{
+				StatusCode: 64,
+				Status:     "Error parsing format: " + err.Error(),
+			}
+		}
+		out := dockerCLI.Out()
+		handle = func(event events.Message) error {
+			return handleEvent(out, event, tmpl)
+		}
+	}
+
+	var err error
+	if options.replay != "" {
+		err = runReplay(dockerCLI, options, handle)
+	} else {
+		err = streamEvents(ctx, dockerCLI, options, handle)
+	}
+	if executor != nil {
+		// run the command for events that are debounced if all events
+		// were received, and wait for running commands to finish.
+		executor.wait(err == nil)
+	}
 	if err != nil {
-		return cli.StatusError{
-			StatusCode: 64,
-			Status:     "Error parsing format: " + err.Error(),
+		return err
+	}
+
+	if summary != nil {
+		return eventsSummaryFormatWrite(formatter.Context{
+			Output: dockerCLI.Out(),
+			Format: newEventsSummaryFormat(options.format),
+		}, summary.rows())
+	}
+	return nil
+}
+
+// streamEvents calls handle for each event received from the server, and
+// records the events if --record is set. Events are received until the
+// --until timestamp, or, when summarizing, until interrupted.
+func streamEvents(ctx context.Context, dockerCLI command.Cli, options *eventsOptions, handle func(events.Message) error) error {
+	if options.record != "" {
+		f, err := os.Create(options.record)
+		if err != nil {
+			return fmt.Errorf("failed to create recording: %w", err)
+		}
+		defer f.Close()
+		enc := json.NewEncoder(f)
+		next := handle
+		handle = func(event events.Message) error {
+			if err := enc.Encode(event); err != nil {
+				return fmt.Errorf("failed to write recording: %w", err)
+			}
+			return next(event)
 		}
 	}
+
+	parentCtx := ctx
 	ctx, cancel := context.WithCancel(ctx)
 	evts, errs := dockerCLI.Client().Events(ctx, client.EventsListOptions{
 		Since:   options.since,
@@ -72,23 +165,44 @@ func runEvents(ctx context.Context, dockerCLI command.Cli, options *eventsOption
 	})
 	defer cancel()
 
-	out := dockerCLI.Out()
-
 	for {
 		select {
 		case event := <-evts:
-			if err := handleEvent(out, event, tmpl); err != nil {
+			if err := handle(event); err != nil {
 				return err
 			}
 		case err := <-errs:
 			if err == io.EOF {
 				return nil
 			}
+			if options.summary && parentCtx.Err() != nil {
+				// print the summary of the events received so far
+				return nil
+			}
 			return err
 		}
 	}
 }
 
+// runReplay calls handle for each event recorded in the --replay file that
+// matches the --filter, --since, and --until options.
+func runReplay(dockerCLI command.Cli, options *eventsOptions, handle func(events.Message) error) error {
+	filter, err := newEventFilter(options.filter.Value(), options.since, options.until, time.Now())
+	if err != nil {
+		return err
+	}
+	var in io.Reader = dockerCLI.In()
+	if options.replay != "-" {
+		f, err := os.Open(options.replay)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	return replayEvents(in, filter, handle)
+}
+
 func handleEvent(out io.Writer, event events.Message, tmpl *template.Template) error {
 	if tmpl == nil {
 		return prettyPrintEvent(out, event)
@@ -104,6 +218,11 @@ func makeTemplate(format string) (*template.Template, error) {
 	case formatter.JSONFormatKey:
 		format = formatter.JSONFormat
 	}
+	return parseEventTemplate(format)
+}
+
+// parseEventTemplate parses a template that is executed for events.
+func parseEventTemplate(format string) (*template.Template, error) {
 	tmpl, err := templates.Parse(format)
 	if err != nil {
 		return tmpl, err
@@ -0,0 +1,232 @@
+package system
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/google/shlex"
+	"github.com/moby/moby/api/types/events"
+)
+
+// eventExecutor runs a command for events, as set with the --exec option.
+type eventExecutor struct {
+	ctx      context.Context
+	args     []*template.Template
+	debounce time.Duration
+	out      io.Writer
+	errOut   io.Writer
+
+	// run runs the command with the given arguments and additional
+	// environment variables. It's a field to allow replacing it in tests.
+	run func(ctx context.Context, args, env []string, stdout, stderr io.Writer) error
+
+	// sem limits the number of commands that run at the same time.
+	sem chan struct{}
+	wg  sync.WaitGroup
+
+	mu      sync.Mutex
+	pending map[string]*pendingEvent
+}
+
+// pendingEvent is the last of a series of events for which the command is
+// run once no more events of the series are received for the debounce
+// duration.
+type pendingEvent struct {
+	event events.Message
+	timer *time.Timer
+	gen   int
+}
+
+// newEventExecutor returns an executor for the given command. The command
+// is split into arguments in the same way as a shell would, and each argument
+// is a template that is executed for the event.
+func newEventExecutor(ctx context.Context, command string, concurrency int, debounce time.Duration, out, errOut io.Writer) (*eventExecutor, error) {
+	if concurrency < 1 {
+		return nil, errors.New("invalid concurrency: must be at least 1")
+	}
+	if debounce < 0 {
+		return nil, errors.New("invalid debounce: must be a positive duration")
+	}
+	fields, err := shlex.Split(command)
+	if err != nil {
+		return nil, fmt.Errorf("invalid command: %w", err)
+	}
+	if len(fields) == 0 {
+		return nil, errors.New("invalid command: no command specified")
+	}
+	args := make([]*template.Template, 0, len(fields))
+	for _, f := range fields {
+		tmpl, err := parseEventTemplate(f)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template in command argument %q: %w", f, err)
+		}
+		args = append(args, tmpl)
+	}
+	return &eventExecutor{
+		ctx:      ctx,
+		args:     args,
+		debounce: debounce,
+		out:      out,
+		errOut:   errOut,
+		run:      runEventCommand,
+		sem:      make(chan struct{}, concurrency),
+		pending:  make(map[string]*pendingEvent),
+	}, nil
+}
+
+func runEventCommand(ctx context.Context, args, env []string, stdout, stderr io.Writer) error {
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...) //nolint:gosec // the command is provided by the user
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
+
+// handle runs the command for an event. If a debounce duration is set, the
+// command is only run for the last of the events of the same type, action,
+// and object that are received within the debounce duration of each other.
+func (e *eventExecutor) handle(event events.Message) error {
+	if e.debounce == 0 {
+		e.start(event)
+		return nil
+	}
+	key := string(event.Type) + "\x00" + string(event.Action) + "\x00" + event.Actor.ID
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	p, ok := e.pending[key]
+	if !ok {
+		p = &pendingEvent{}
+		e.pending[key] = p
+		// the pending event is done once the command is started for it
+		e.wg.Add(1)
+	} else {
+		p.timer.Stop()
+	}
+	p.event = event
+	p.gen++
+	gen := p.gen
+	p.timer = time.AfterFunc(e.debounce, func() {
+		e.mu.Lock()
+		if cur, ok := e.pending[key]; !ok || cur.gen != gen {
+			e.mu.Unlock()
+			return
+		}
+		delete(e.pending, key)
+		e.mu.Unlock()
+		e.start(event)
+		e.wg.Done()
+	})
+	return nil
+}
+
+// start starts the command for an event, waiting until fewer commands than
+// the concurrency limit are running. Errors are printed as a warning, so
+// that the command is run for subsequent events.
+func (e *eventExecutor) start(event events.Message) {
+	args := make([]string, 0, len(e.args))
+	for _, tmpl := range e.args {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, event); err != nil {
+			_, _ = fmt.Fprintf(e.errOut, "WARNING: failed to run command for %s event of %s: %v\n", event.Action, event.Actor.ID, err)
+			return
+		}
+		args = append(args, buf.String())
+	}
+	env := eventEnv(event)
+
+	select {
+	case e.sem <- struct{}{}:
+	case <-e.ctx.Done():
+		return
+	}
+	e.wg.Add(1)
+	go func() {
+		defer func() {
+			<-e.sem
+			e.wg.Done()
+		}()
+		if err := e.run(e.ctx, args, env, e.out, e.errOut); err != nil && e.ctx.Err() == nil {
+			_, _ = fmt.Fprintf(e.errOut, "WARNING: command for %s event of %s failed: %v\n", event.Action, event.Actor.ID, err)
+		}
+	}()
+}
+
+// wait waits for the commands that are running to finish. Commands for
+// events that are waiting for the debounce duration to pass are started
+// first if flush is set, and discarded otherwise.
+func (e *eventExecutor) wait(flush bool) {
+	e.mu.Lock()
+	var flushed []events.Message
+	for key, p := range e.pending {
+		if p.timer.Stop() {
+			delete(e.pending, key)
+			if flush {
+				flushed = append(flushed, p.event)
+			}
+			e.wg.Done()
+		}
+	}
+	e.mu.Unlock()
+	// Start the commands in the order in which the events were received.
+	sort.SliceStable(flushed, func(i, j int) bool {
+		return eventTime(flushed[i]).Before(eventTime(flushed[j]))
+	})
+	for _, event := range flushed {
+		e.start(event)
+	}
+	e.wg.Wait()
+}
+
+// eventEnv returns the environment variables that describe an event to the
+// command that is run for it. Attributes of the object are set as variables
+// prefixed with "DOCKER_EVENT_ATTR_", such as "DOCKER_EVENT_ATTR_NAME".
+func eventEnv(event events.Message) []string {
+	env := []string{
+		"DOCKER_EVENT_TYPE=" + string(event.Type),
+		"DOCKER_EVENT_ACTION=" + string(event.Action),
+		"DOCKER_EVENT_ACTOR_ID=" + event.Actor.ID,
+		"DOCKER_EVENT_SCOPE=" + event.Scope,
+		"DOCKER_EVENT_TIME=" + strconv.FormatInt(eventTime(event).Unix(), 10),
+		"DOCKER_EVENT_TIME_NANO=" + strconv.FormatInt(eventTime(event).UnixNano(), 10),
+	}
+	if b, err := json.Marshal(event); err == nil {
+		env = append(env, "DOCKER_EVENT_JSON="+string(b))
+	}
+	keys := make([]string, 0, len(event.Actor.Attributes))
+	for k := range event.Actor.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		env = append(env, "DOCKER_EVENT_ATTR_"+envName(k)+"="+event.Actor.Attributes[k])
+	}
+	return env
+}
+
+// envName converts an attribute name, such as "com.example.some-label", to
+// the name of an environment variable, such as "COM_EXAMPLE_SOME_LABEL".
+func envName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
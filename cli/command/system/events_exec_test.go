@@ -0,0 +1,180 @@
+package system
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/moby/moby/api/types/events"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+type execRecorder struct {
+	mu   sync.Mutex
+	args [][]string
+	env  [][]string
+}
+
+func (r *execRecorder) run(_ context.Context, args, env []string, stdout, _ io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.args = append(r.args, args)
+	r.env = append(r.env, env)
+	if args[0] == "fail" {
+		return errors.New("exit status 1")
+	}
+	_, _ = io.WriteString(stdout, args[0]+"\n")
+	return nil
+}
+
+func execTestEvent(sec int64, action events.Action, id, name string) events.Message {
+	return events.Message{
+		Type:     events.ContainerEventType,
+		Action:   action,
+		Actor:    events.Actor{ID: id, Attributes: map[string]string{"name": name, "com.example.some-label": "yes"}},
+		Scope:    "local",
+		Time:     sec,
+		TimeNano: sec * int64(time.Second),
+	}
+}
+
+func TestEventExecutor(t *testing.T) {
+	var out, errOut bytes.Buffer
+	e, err := newEventExecutor(context.Background(), `notify "{{.Action}} of {{.Actor.Attributes.name}}" --id={{.Actor.ID}}`, 1, 0, &out, &errOut)
+	assert.NilError(t, err)
+	rec := &execRecorder{}
+	e.run = rec.run
+
+	assert.NilError(t, e.handle(execTestEvent(100, "health_status: unhealthy", "aaaa", "web")))
+	e.wait(true)
+
+	assert.Check(t, is.DeepEqual(rec.args, [][]string{{"notify", "health_status: unhealthy of web", "--id=aaaa"}}))
+	assert.Check(t, is.DeepEqual(rec.env[0][:6], []string{
+		"DOCKER_EVENT_TYPE=container",
+		"DOCKER_EVENT_ACTION=health_status: unhealthy",
+		"DOCKER_EVENT_ACTOR_ID=aaaa",
+		"DOCKER_EVENT_SCOPE=local",
+		"DOCKER_EVENT_TIME=100",
+		"DOCKER_EVENT_TIME_NANO=100000000000",
+	}))
+	assert.Check(t, is.DeepEqual(rec.env[0][7:], []string{
+		"DOCKER_EVENT_ATTR_COM_EXAMPLE_SOME_LABEL=yes",
+		"DOCKER_EVENT_ATTR_NAME=web",
+	}))
+	assert.Check(t, is.Contains(rec.env[0][6], `DOCKER_EVENT_JSON={"Type":"container"`))
+	assert.Check(t, is.Equal(out.String(), "notify\n"))
+	assert.Check(t, is.Equal(errOut.String(), ""))
+}
+
+func TestEventExecutorFailure(t *testing.T) {
+	var errOut bytes.Buffer
+	e, err := newEventExecutor(context.Background(), "fail", 1, 0, io.Discard, &errOut)
+	assert.NilError(t, err)
+	rec := &execRecorder{}
+	e.run = rec.run
+
+	assert.NilError(t, e.handle(execTestEvent(100, events.ActionDie, "aaaa", "web")))
+	assert.NilError(t, e.handle(execTestEvent(101, events.ActionDie, "bbbb", "db")))
+	e.wait(true)
+	assert.Check(t, is.Len(rec.args, 2))
+	assert.Check(t, is.Equal(errOut.String(), "WARNING: command for die event of aaaa failed: exit status 1\nWARNING: command for die event of bbbb failed: exit status 1\n"))
+}
+
+func TestEventExecutorDebounce(t *testing.T) {
+	// The debounce duration is long enough for all events to be pending
+	// until they're flushed.
+	e, err := newEventExecutor(context.Background(), "notify {{.Time}} {{.Actor.Attributes.name}}", 1, time.Hour, io.Discard, io.Discard)
+	assert.NilError(t, err)
+	rec := &execRecorder{}
+	e.run = rec.run
+
+	assert.NilError(t, e.handle(execTestEvent(100, events.ActionDie, "aaaa", "web")))
+	assert.NilError(t, e.handle(execTestEvent(101, events.ActionDie, "bbbb", "db")))
+	assert.NilError(t, e.handle(execTestEvent(102, events.ActionDie, "aaaa", "web")))
+	assert.NilError(t, e.handle(execTestEvent(103, events.ActionStart, "aaaa", "web")))
+	e.wait(true)
+	assert.Check(t, is.DeepEqual(rec.args, [][]string{
+		{"notify", "101", "db"},
+		{"notify", "102", "web"},
+		{"notify", "103", "web"},
+	}))
+
+	rec.args = nil
+	assert.NilError(t, e.handle(execTestEvent(104, events.ActionDie, "aaaa", "web")))
+	e.wait(false)
+	assert.Check(t, is.Len(rec.args, 0))
+
+	e, err = newEventExecutor(context.Background(), "notify {{.Time}}", 1, 10*time.Millisecond, io.Discard, io.Discard)
+	assert.NilError(t, err)
+	e.run = rec.run
+	assert.NilError(t, e.handle(execTestEvent(105, events.ActionDie, "aaaa", "web")))
+	assert.NilError(t, e.handle(execTestEvent(106, events.ActionDie, "aaaa", "web")))
+	time.Sleep(50 * time.Millisecond)
+	e.wait(false)
+	assert.Check(t, is.DeepEqual(rec.args, [][]string{{"notify", "106"}}))
+}
+
+func TestEventExecutorConcurrency(t *testing.T) {
+	e, err := newEventExecutor(context.Background(), "notify {{.Time}}", 2, 0, io.Discard, io.Discard)
+	assert.NilError(t, err)
+
+	startedC := make(chan string)
+	release := make(chan struct{})
+	e.run = func(_ context.Context, args, _ []string, _, _ io.Writer) error {
+		startedC <- args[1]
+		<-release
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := int64(0); i < 4; i++ {
+			_ = e.handle(execTestEvent(100+i, events.ActionDie, "aaaa", "web"))
+		}
+		e.wait(true)
+	}()
+
+	started := []string{<-startedC, <-startedC}
+	select {
+	case s := <-startedC:
+		t.Fatalf("command for event %s started while two commands are running", s)
+	case <-time.After(50 * time.Millisecond):
+	}
+	release <- struct{}{}
+	started = append(started, <-startedC)
+	release <- struct{}{}
+	started = append(started, <-startedC)
+	close(release)
+	<-done
+
+	sort.Strings(started)
+	assert.Check(t, is.DeepEqual(started, []string{"100", "101", "102", "103"}))
+}
+
+func TestEventsExecInvalid(t *testing.T) {
+	tests := []struct {
+		args        []string
+		expectedErr string
+	}{
+		{args: []string{"--exec-debounce", "1s"}, expectedErr: "the --exec-concurrency and --exec-debounce options require a command to be set with --exec"},
+		{args: []string{"--exec", "notify", "--summary"}, expectedErr: "the --exec and --summary options cannot be used together"},
+		{args: []string{"--exec", "notify", "--exec-concurrency", "0"}, expectedErr: "invalid concurrency: must be at least 1"},
+		{args: []string{"--exec", "notify {{.NoSuchField}}"}, expectedErr: `invalid template in command argument "{{.NoSuchField}}"`},
+		{args: []string{"--exec", " "}, expectedErr: "invalid command: no command specified"},
+	}
+	for _, tc := range tests {
+		cmd := newEventsCommand(test.NewFakeCli(&fakeClient{}))
+		cmd.SetArgs(tc.args)
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		assert.Check(t, is.ErrorContains(cmd.Execute(), tc.expectedErr), tc.args)
+	}
+}
@@ -0,0 +1,305 @@
+package system
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/internal/timestamp"
+	"github.com/moby/moby/api/types/events"
+	"github.com/moby/moby/api/types/filters"
+)
+
+const defaultEventsSummaryFormat = "table {{.Type}}\t{{.Action}}\t{{.Actor}}\t{{.Count}}"
+
+// acceptedReplayFilters are the filters that can be applied to replayed
+// events; these are the same filters as accepted by the daemon.
+var acceptedReplayFilters = map[string]bool{
+	"config":    true,
+	"container": true,
+	"daemon":    true,
+	"event":     true,
+	"image":     true,
+	"label":     true,
+	"network":   true,
+	"node":      true,
+	"plugin":    true,
+	"scope":     true,
+	"secret":    true,
+	"service":   true,
+	"type":      true,
+	"volume":    true,
+}
+
+// eventTime returns the time at which an event occurred.
+func eventTime(event events.Message) time.Time {
+	if event.TimeNano != 0 {
+		return time.Unix(0, event.TimeNano)
+	}
+	return time.Unix(event.Time, 0)
+}
+
+// replayEvents reads events recorded as JSON lines from r, and calls handle
+// for each event that is included by the filter.
+func replayEvents(r io.Reader, filter eventFilter, handle func(events.Message) error) error {
+	dec := json.NewDecoder(r)
+	for {
+		var event events.Message
+		if err := dec.Decode(&event); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("failed to read recording: %w", err)
+		}
+		if !filter.include(event) {
+			continue
+		}
+		if err := handle(event); err != nil {
+			return err
+		}
+	}
+}
+
+// eventFilter filters replayed events in the same way as the daemon filters
+// the events it sends.
+type eventFilter struct {
+	args filters.Args
+
+	// since and until are the zero time if not set.
+	since, until time.Time
+}
+
+func newEventFilter(args filters.Args, since, until string, now time.Time) (eventFilter, error) {
+	if err := args.Validate(acceptedReplayFilters); err != nil {
+		return eventFilter{}, err
+	}
+	f := eventFilter{args: args}
+	var err error
+	if since != "" {
+		if f.since, err = timestamp.Parse(since, now); err != nil {
+			return eventFilter{}, fmt.Errorf("invalid value for --since: %w", err)
+		}
+	}
+	if until != "" {
+		if f.until, err = timestamp.Parse(until, now); err != nil {
+			return eventFilter{}, fmt.Errorf("invalid value for --until: %w", err)
+		}
+	}
+	return f, nil
+}
+
+func (f eventFilter) include(event events.Message) bool {
+	t := eventTime(event)
+	if !f.since.IsZero() && t.Before(f.since) {
+		return false
+	}
+	if !f.until.IsZero() && t.After(f.until) {
+		return false
+	}
+	for _, typ := range []events.Type{
+		events.ConfigEventType,
+		events.ContainerEventType,
+		events.DaemonEventType,
+		events.NetworkEventType,
+		events.NodeEventType,
+		events.PluginEventType,
+		events.SecretEventType,
+		events.ServiceEventType,
+		events.VolumeEventType,
+	} {
+		if !f.matchName(event, typ) {
+			return false
+		}
+	}
+	return f.matchAction(event) &&
+		f.args.ExactMatch("type", string(event.Type)) &&
+		f.args.ExactMatch("scope", event.Scope) &&
+		f.matchImage(event) &&
+		f.args.MatchKVList("label", event.Actor.Attributes)
+}
+
+// matchAction matches the action of an event. Actions that include details,
+// such as "exec_start: sh" or "health_status: healthy", also match the
+// action without details.
+func (f eventFilter) matchAction(event events.Message) bool {
+	for _, action := range []string{"exec_create", "exec_start", "health_status"} {
+		if f.args.Contains("event") && f.args.ExactMatch("event", action) {
+			return f.args.FuzzyMatch("event", string(event.Action))
+		}
+	}
+	return f.args.ExactMatch("event", string(event.Action))
+}
+
+// matchName matches the ID or name of the actor of an event against the
+// filter for objects of the given type, such as "container=web".
+func (f eventFilter) matchName(event events.Message, typ events.Type) bool {
+	return f.args.FuzzyMatch(string(typ), event.Actor.ID) || f.args.FuzzyMatch(string(typ), event.Actor.Attributes["name"])
+}
+
+// matchImage matches both the events of images, and the events of
+// containers created from the image, with or without its tag.
+func (f eventFilter) matchImage(event events.Message) bool {
+	nameAttr := "image"
+	if event.Type == events.ImageEventType {
+		nameAttr = "name"
+	}
+	name := event.Actor.Attributes[nameAttr]
+	return f.args.ExactMatch("image", event.Actor.ID) ||
+		f.args.ExactMatch("image", name) ||
+		f.args.ExactMatch("image", stripTag(event.Actor.ID)) ||
+		f.args.ExactMatch("image", stripTag(name))
+}
+
+func stripTag(image string) string {
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return image
+	}
+	return reference.FamiliarName(ref)
+}
+
+type eventsSummaryKey struct {
+	typ    events.Type
+	action events.Action
+	actor  string
+}
+
+// eventsSummary counts events by type, action, and actor.
+type eventsSummary struct {
+	counts map[eventsSummaryKey]*eventsSummaryRow
+}
+
+// eventsSummaryRow is the number of events of a type and action for an
+// actor, and the time of the first and last event.
+type eventsSummaryRow struct {
+	eventsSummaryKey
+	count       int
+	first, last time.Time
+}
+
+func newEventsSummary() *eventsSummary {
+	return &eventsSummary{counts: make(map[eventsSummaryKey]*eventsSummaryRow)}
+}
+
+// add counts an event. Exec events are counted without the command that is
+// part of their action, and actors are identified by name if they have one.
+func (s *eventsSummary) add(event events.Message) error {
+	action := event.Action
+	if strings.HasPrefix(string(action), "exec_") {
+		a, _, _ := strings.Cut(string(action), ":")
+		action = events.Action(a)
+	}
+	actor := event.Actor.Attributes["name"]
+	if actor == "" {
+		actor = event.Actor.ID
+	}
+	key := eventsSummaryKey{typ: event.Type, action: action, actor: actor}
+	t := eventTime(event)
+	row, ok := s.counts[key]
+	if !ok {
+		row = &eventsSummaryRow{eventsSummaryKey: key, first: t, last: t}
+		s.counts[key] = row
+	}
+	row.count++
+	if t.Before(row.first) {
+		row.first = t
+	}
+	if t.After(row.last) {
+		row.last = t
+	}
+	return nil
+}
+
+// rows returns the counts, ordered by the number of events, and then by
+// type, action, and actor.
+func (s *eventsSummary) rows() []eventsSummaryRow {
+	rows := make([]eventsSummaryRow, 0, len(s.counts))
+	for _, row := range s.counts {
+		rows = append(rows, *row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		switch {
+		case a.count != b.count:
+			return a.count > b.count
+		case a.typ != b.typ:
+			return a.typ < b.typ
+		case a.action != b.action:
+			return a.action < b.action
+		default:
+			return a.actor < b.actor
+		}
+	})
+	return rows
+}
+
+// newEventsSummaryFormat returns the format for the summary of events; the
+// default table format if no format is set.
+func newEventsSummaryFormat(source string) formatter.Format {
+	if source == "" || source == formatter.TableFormatKey {
+		return defaultEventsSummaryFormat
+	}
+	return formatter.Format(source)
+}
+
+// eventsSummaryFormatWrite renders the number of events by type, action,
+// and actor.
+func eventsSummaryFormatWrite(ctx formatter.Context, rows []eventsSummaryRow) error {
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for _, row := range rows {
+			if err := format(&eventsSummaryContext{row: row}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	summaryCtx := eventsSummaryContext{}
+	summaryCtx.Header = formatter.SubHeaderContext{
+		"Type":   "TYPE",
+		"Action": "ACTION",
+		"Actor":  "ACTOR",
+		"Count":  "COUNT",
+		"First":  "FIRST",
+		"Last":   "LAST",
+	}
+	return ctx.Write(&summaryCtx, render)
+}
+
+type eventsSummaryContext struct {
+	formatter.HeaderContext
+	row eventsSummaryRow
+}
+
+func (c *eventsSummaryContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *eventsSummaryContext) Type() string {
+	return string(c.row.typ)
+}
+
+func (c *eventsSummaryContext) Action() string {
+	return string(c.row.action)
+}
+
+func (c *eventsSummaryContext) Actor() string {
+	return c.row.actor
+}
+
+func (c *eventsSummaryContext) Count() int {
+	return c.row.count
+}
+
+func (c *eventsSummaryContext) First() string {
+	return c.row.first.Format(time.RFC3339)
+}
+
+func (c *eventsSummaryContext) Last() string {
+	return c.row.last.Format(time.RFC3339)
+}
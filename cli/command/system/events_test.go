@@ -1,17 +1,23 @@
 package system
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/internal/test"
 	"github.com/moby/moby/api/types/events"
+	"github.com/moby/moby/api/types/filters"
 	"github.com/moby/moby/client"
 	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 	"gotest.tools/v3/golden"
 )
 
@@ -79,3 +85,156 @@ func TestEventsFormat(t *testing.T) {
 		})
 	}
 }
+
+func eventsTestClient(evts []events.Message) *fakeClient {
+	return &fakeClient{eventsFn: func(context.Context, client.EventsListOptions) (<-chan events.Message, <-chan error) {
+		messages := make(chan events.Message)
+		errs := make(chan error, 1)
+		go func() {
+			for _, msg := range evts {
+				messages <- msg
+			}
+			errs <- io.EOF
+		}()
+		return messages, errs
+	}}
+}
+
+func recordTestEvents() []events.Message {
+	newEvent := func(sec int64, typ events.Type, action events.Action, id string, attrs map[string]string) events.Message {
+		return events.Message{
+			Type:     typ,
+			Action:   action,
+			Actor:    events.Actor{ID: id, Attributes: attrs},
+			Scope:    "local",
+			Time:     sec,
+			TimeNano: sec * int64(time.Second),
+		}
+	}
+	web := map[string]string{"name": "web", "image": "nginx:alpine", "tier": "frontend"}
+	db := map[string]string{"name": "db", "image": "postgres:16"}
+	return []events.Message{
+		newEvent(100, events.ImageEventType, events.ActionPull, "nginx:alpine", map[string]string{"name": "nginx"}),
+		newEvent(101, events.ContainerEventType, events.ActionCreate, "aaaa", web),
+		newEvent(102, events.ContainerEventType, events.ActionStart, "aaaa", web),
+		newEvent(103, events.ContainerEventType, events.ActionStart, "bbbb", db),
+		newEvent(104, events.ContainerEventType, "exec_start: sh -c date", "aaaa", web),
+		newEvent(105, events.ContainerEventType, events.ActionDie, "aaaa", web),
+		newEvent(106, events.ContainerEventType, events.ActionStart, "aaaa", web),
+		newEvent(107, events.ContainerEventType, "exec_start: nginx -s reload", "aaaa", web),
+	}
+}
+
+func TestEventsRecordReplay(t *testing.T) {
+	t.Setenv("TZ", "UTC")
+	recording := filepath.Join(t.TempDir(), "events.jsonl")
+
+	fakeCLI := test.NewFakeCli(eventsTestClient(recordTestEvents()))
+	cmd := newEventsCommand(fakeCLI)
+	cmd.SetArgs([]string{"--record", recording, "--format", "{{.Action}}"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(strings.Count(fakeCLI.OutBuffer().String(), "\n"), 8))
+
+	// replaying does not use the API client
+	fakeCLI = test.NewFakeCli(&fakeClient{})
+	cmd = newEventsCommand(fakeCLI)
+	cmd.SetArgs([]string{
+		"--replay", recording,
+		"--filter", "container=web",
+		"--filter", "event=start",
+		"--filter", "event=exec_start",
+		"--since", "102",
+		"--until", "1970-01-01T00:01:46Z",
+		"--format", "{{.Time}} {{.Action}} {{.Actor.Attributes.name}}",
+	})
+	assert.NilError(t, cmd.Execute())
+	expected := `102 start web
+104 exec_start: sh -c date web
+106 start web
+`
+	assert.Check(t, is.Equal(fakeCLI.OutBuffer().String(), expected))
+}
+
+func TestEventsSummary(t *testing.T) {
+	evts := recordTestEvents()
+	var recording bytes.Buffer
+	enc := json.NewEncoder(&recording)
+	for _, e := range evts {
+		assert.NilError(t, enc.Encode(e))
+	}
+
+	fakeCLI := test.NewFakeCli(&fakeClient{})
+	fakeCLI.SetIn(streams.NewIn(io.NopCloser(&recording)))
+	cmd := newEventsCommand(fakeCLI)
+	cmd.SetArgs([]string{"--replay", "-", "--summary", "--filter", "type=container"})
+	assert.NilError(t, cmd.Execute())
+	expected := `TYPE        ACTION       ACTOR     COUNT
+container   exec_start   web       2
+container   start        web       2
+container   create       web       1
+container   die          web       1
+container   start        db        1
+`
+	assert.Check(t, is.Equal(fakeCLI.OutBuffer().String(), expected))
+
+	fakeCLI = test.NewFakeCli(eventsTestClient(evts))
+	cmd = newEventsCommand(fakeCLI)
+	cmd.SetArgs([]string{"--summary", "--format", "{{.Actor}}: {{.Count}}"})
+	assert.NilError(t, cmd.Execute())
+	expected = `web: 2
+web: 2
+web: 1
+web: 1
+db: 1
+nginx: 1
+`
+	assert.Check(t, is.Equal(fakeCLI.OutBuffer().String(), expected))
+}
+
+func TestEventsRecordReplayConflict(t *testing.T) {
+	cmd := newEventsCommand(test.NewFakeCli(&fakeClient{}))
+	cmd.SetArgs([]string{"--record", "a.jsonl", "--replay", "b.jsonl"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), "the --record and --replay options cannot be used together"))
+}
+
+func TestEventFilter(t *testing.T) {
+	ctr := events.Message{
+		Type:   events.ContainerEventType,
+		Action: "health_status: healthy",
+		Actor:  events.Actor{ID: "abc123", Attributes: map[string]string{"name": "web", "image": "nginx:alpine", "tier": "frontend"}},
+		Scope:  "local",
+	}
+	tests := []struct {
+		filters  []string
+		expected bool
+	}{
+		{filters: nil, expected: true},
+		{filters: []string{"container=abc"}, expected: true},
+		{filters: []string{"container=web"}, expected: true},
+		{filters: []string{"container=db"}, expected: false},
+		{filters: []string{"image=nginx"}, expected: true},
+		{filters: []string{"image=nginx:alpine"}, expected: true},
+		{filters: []string{"image=postgres"}, expected: false},
+		{filters: []string{"event=health_status"}, expected: true},
+		{filters: []string{"event=start"}, expected: false},
+		{filters: []string{"label=tier=frontend"}, expected: true},
+		{filters: []string{"label=tier=backend"}, expected: false},
+		{filters: []string{"type=container", "scope=local"}, expected: true},
+		{filters: []string{"volume=data"}, expected: false},
+	}
+	for _, tc := range tests {
+		args := filters.NewArgs()
+		for _, f := range tc.filters {
+			k, v, _ := strings.Cut(f, "=")
+			args.Add(k, v)
+		}
+		filter, err := newEventFilter(args, "", "", time.Now())
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(filter.include(ctr), tc.expected), tc.filters)
+	}
+
+	_, err := newEventFilter(filters.NewArgs(filters.Arg("nosuchfilter", "x")), "", "", time.Now())
+	assert.Check(t, is.ErrorContains(err, "nosuchfilter"))
+}
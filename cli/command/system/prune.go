@@ -1,3 +1,6 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.23
+
 package system
 
 import (
@@ -5,15 +8,21 @@
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"iter"
 	"sort"
 	"text/template"
 
 	"github.com/containerd/errdefs"
 	"github.com/docker/cli/cli"
+	pluginmanager "github.com/docker/cli/cli-plugins/manager"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
 	"github.com/docker/cli/cli/command/system/pruner"
+	flagsHelper "github.com/docker/cli/cli/flags"
 	"github.com/docker/cli/internal/prompt"
 	"github.com/docker/cli/opts"
+	"github.com/docker/cli/templates"
 	"github.com/docker/go-units"
 	"github.com/fvbommel/sortorder"
 	"github.com/spf13/cobra"
@@ -23,7 +32,10 @@ type pruneOptions struct {
 	force        bool
 	all          bool
 	pruneVolumes bool
+	dryRun       bool
+	format       string
 	filter       opts.FilterOpt
+	retention    pruner.RetentionOptions
 }
 
 // newPruneCommand creates a new cobra.Command for `docker prune`
@@ -35,7 +47,7 @@ func newPruneCommand(dockerCLI command.Cli) *cobra.Command {
 		Short: "Remove unused data",
 		Args:  cli.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runPrune(cmd.Context(), dockerCLI, options)
+			return runPrune(cmd.Context(), dockerCLI, cmd.Root(), options)
 		},
 		Annotations:           map[string]string{"version": "1.25"},
 		ValidArgsFunction:     cobra.NoFileCompletions,
@@ -46,14 +58,17 @@ func newPruneCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.BoolVarP(&options.force, "force", "f", false, "Do not prompt for confirmation")
 	flags.BoolVarP(&options.all, "all", "a", false, "Remove all unused images not just dangling ones")
 	flags.BoolVar(&options.pruneVolumes, "volumes", false, "Prune anonymous volumes")
+	flags.BoolVar(&options.dryRun, "dry-run", false, "Show what would be removed without removing anything")
+	flags.StringVar(&options.format, "format", "", flagsHelper.InspectFormatHelp)
 	flags.Var(&options.filter, "filter", `Provide filter values (e.g. "label=<key>=<value>")`)
 	// "filter" flag is available in 1.28 (docker 17.04) and up
 	flags.SetAnnotation("filter", "version", []string{"1.28"})
+	options.retention.InstallFlags(flags)
 
 	return cmd
 }
 
-const confirmationTemplate = `WARNING! This will remove:
+const confirmationTemplate = `{{if .dryRun}}The following would be removed:{{else}}WARNING! This will remove:{{end}}
 {{- range $_, $warning := .warnings }}
   - {{ $warning }}
 {{- end }}
@@ -63,20 +78,80 @@ func newPruneCommand(dockerCLI command.Cli) *cobra.Command {
   - {{ $filters }}
 {{- end }}
 {{end}}
-Are you sure you want to continue?`
+{{- if not .dryRun}}
+Are you sure you want to continue?
+{{- end}}`
+
+// pruneReport is the report of a "docker system prune", and used when
+// formatting the output using the "--format" option.
+type pruneReport struct {
+	DryRun         bool
+	Results        []pruneResult
+	SpaceReclaimed uint64
+}
+
+// pruneResult is the result of pruning a single [pruner.ContentType].
+type pruneResult struct {
+	ContentType pruner.ContentType
+	// Deleted and SpaceReclaimed are always empty when running in "dry-run"
+	// mode, as the daemon does not report what it would remove.
+	Deleted        []string
+	SpaceReclaimed uint64
+	// Details is the description of the content to be pruned when
+	// running in "dry-run" mode.
+	Details string `json:",omitempty"`
+	Error   string `json:",omitempty"`
+}
 
-func runPrune(ctx context.Context, dockerCli command.Cli, options pruneOptions) error {
+func runPrune(ctx context.Context, dockerCli command.Cli, rootCmd *cobra.Command, options pruneOptions) error {
 	// prune requires either force, or a user to confirm after prompting.
 	confirmed := options.force
 
-	// Validate the given options for each pruner and construct a confirmation-message.
-	confirmationMessage, err := dryRun(ctx, dockerCli, options)
+	var tmpl *template.Template
+	if options.format != "" {
+		format := options.format
+		if format == formatter.JSONFormatKey {
+			format = formatter.JSONFormat
+		}
+		var err error
+		tmpl, err = templates.Parse(format)
+		if err != nil {
+			return cli.StatusError{StatusCode: 64, Status: "template parsing error: " + err.Error()}
+		}
+	}
+
+	retention, err := options.retention.Policy(dockerCli.ConfigFile())
+	if err != nil {
+		return err
+	}
+	if notice := retention.ConfigNotice(); notice != "" {
+		_, _ = fmt.Fprintln(dockerCli.Err(), notice)
+	}
+
+	pruners := listPruners(dockerCli, rootCmd)
+
+	// Validate the given options for each pruner and collect a description
+	// of the content that will be pruned.
+	planned, err := dryRun(ctx, dockerCli, pruners, options, retention)
 	if err != nil {
 		return err
 	}
+	if options.dryRun {
+		if tmpl != nil {
+			return writePruneReport(dockerCli.Out(), tmpl, pruneReport{DryRun: true, Results: planned})
+		}
+		_, _ = fmt.Fprint(dockerCli.Out(), confirmationMessage(dockerCli, planned, options))
+		return nil
+	}
 	if !confirmed {
+		// Print the prompt to stderr when formatting the output, so that
+		// it doesn't end up in the formatted output.
+		var promptOut io.Writer = dockerCli.Out()
+		if tmpl != nil {
+			promptOut = dockerCli.Err()
+		}
 		var err error
-		confirmed, err = prompt.Confirm(ctx, dockerCli.In(), dockerCli.Out(), confirmationMessage)
+		confirmed, err = prompt.Confirm(ctx, dockerCli.In(), promptOut, confirmationMessage(dockerCli, planned, options))
 		if err != nil {
 			return err
 		}
@@ -85,8 +160,11 @@ func runPrune(ctx context.Context, dockerCli command.Cli, options pruneOptions)
 		}
 	}
 
-	var spaceReclaimed uint64
-	for contentType, pruneFn := range pruner.List() {
+	var (
+		report pruneReport
+		errs   []error
+	)
+	for contentType, pruneFn := range pruners {
 		switch contentType {
 		case pruner.TypeVolume:
 			if !options.pruneVolumes {
@@ -98,22 +176,53 @@ func runPrune(ctx context.Context, dockerCli command.Cli, options pruneOptions)
 			// other pruners; no special handling; keeping the "exhaustive" linter happy.
 		}
 
+		result := pruneResult{ContentType: contentType, Deleted: []string{}}
 		spc, output, err := pruneFn(ctx, dockerCli, pruner.PruneOptions{
 			Confirmed: confirmed,
 			All:       options.all,
 			Filter:    options.filter,
+			Retention: retention,
+			OnDeleted: func(id string) {
+				result.Deleted = append(result.Deleted, id)
+			},
 		})
-		if err != nil && !errdefs.IsNotImplemented(err) {
-			return err
+		if err != nil {
+			if errdefs.IsNotImplemented(err) {
+				continue
+			}
+			if tmpl == nil {
+				return err
+			}
+			// Continue with other content-types when formatting output,
+			// so that the report includes all results.
+			result.Error = err.Error()
+			errs = append(errs, err)
 		}
-		spaceReclaimed += spc
-		if output != "" {
+		result.SpaceReclaimed = spc
+		report.SpaceReclaimed += spc
+		report.Results = append(report.Results, result)
+		if output != "" && tmpl == nil {
 			_, _ = fmt.Fprintln(dockerCli.Out(), output)
 		}
 	}
 
-	_, _ = fmt.Fprintln(dockerCli.Out(), "Total reclaimed space:", units.HumanSize(float64(spaceReclaimed)))
+	if tmpl != nil {
+		if err := writePruneReport(dockerCli.Out(), tmpl, report); err != nil {
+			return err
+		}
+		return errors.Join(errs...)
+	}
+
+	_, _ = fmt.Fprintln(dockerCli.Out(), "Total reclaimed space:", units.HumanSize(float64(report.SpaceReclaimed)))
+
+	return nil
+}
 
+func writePruneReport(out io.Writer, tmpl *template.Template, report pruneReport) error {
+	if err := tmpl.Execute(out, report); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(out)
 	return nil
 }
 
@@ -121,14 +230,14 @@ type cancelledErr struct{
 
 func (cancelledErr) Cancelled() {}
 
-// dryRun validates the given options for each prune-function and constructs
-// a confirmation message that depends on the cli options.
-func dryRun(ctx context.Context, dockerCli command.Cli, options pruneOptions) (string, error) {
+// dryRun validates the given options for each prune-function and returns
+// a description of the content that will be pruned for each content-type.
+func dryRun(ctx context.Context, dockerCli command.Cli, pruners iter.Seq2[pruner.ContentType, pruner.PruneFunc], options pruneOptions, retention pruner.RetentionPolicy) ([]pruneResult, error) {
 	var (
-		errs     []error
-		warnings []string
+		errs    []error
+		results []pruneResult
 	)
-	for contentType, pruneFn := range pruner.List() {
+	for contentType, pruneFn := range pruners {
 		switch contentType {
 		case pruner.TypeVolume:
 			if !options.pruneVolumes {
@@ -143,20 +252,38 @@ func dryRun(ctx context.Context, dockerCli command.Cli, options pruneOptions) (s
 		// to perform validation of the given options and produce
 		// a confirmation message for the pruner.
 		_, confirmMsg, err := pruneFn(ctx, dockerCli, pruner.PruneOptions{
-			All:    options.all,
-			Filter: options.filter,
+			All:       options.all,
+			Filter:    options.filter,
+			Retention: retention,
 		})
+		if errdefs.IsNotImplemented(err) {
+			continue
+		}
 		// A "canceled" error is expected in dry-run mode; any other error
 		// must be returned as a "fatal" error.
-		if err != nil && !errdefs.IsCanceled(err) && !errdefs.IsNotImplemented(err) {
+		if err != nil && !errdefs.IsCanceled(err) {
 			errs = append(errs, err)
 		}
 		if confirmMsg != "" {
-			warnings = append(warnings, confirmMsg)
+			results = append(results, pruneResult{
+				ContentType: contentType,
+				Deleted:     []string{},
+				Details:     confirmMsg,
+			})
 		}
 	}
 	if len(errs) > 0 {
-		return "", errors.Join(errs...)
+		return nil, errors.Join(errs...)
+	}
+	return results, nil
+}
+
+// confirmationMessage constructs a confirmation message that depends on
+// the cli options from the results of a [dryRun].
+func confirmationMessage(dockerCli command.Cli, planned []pruneResult, options pruneOptions) string {
+	warnings := make([]string, 0, len(planned))
+	for _, r := range planned {
+		warnings = append(warnings, r.Details)
 	}
 
 	var filters []string
@@ -176,6 +303,86 @@ func dryRun(ctx context.Context, dockerCli command.Cli, options pruneOptions) (s
 
 	var buffer bytes.Buffer
 	t := template.Must(template.New("confirmation message").Parse(confirmationTemplate))
-	_ = t.Execute(&buffer, map[string][]string{"warnings": warnings, "filters": filters})
-	return buffer.String(), nil
+	_ = t.Execute(&buffer, map[string]any{"warnings": warnings, "filters": filters, "dryRun": options.dryRun})
+	return buffer.String()
+}
+
+// listPruners returns all registered pruners, followed by pruners for CLI
+// plugins that declare support for pruning in their metadata. Plugins are
+// pruned after the registered content-types, and are identified by their
+// name; plugins that conflict with a registered content-type are ignored.
+//
+// Plugins must not prevent pruning the registered content-types, so a
+// failure to list plugins is printed as a warning, and plugins are skipped.
+func listPruners(dockerCLI command.Cli, rootCmd *cobra.Command) iter.Seq2[pruner.ContentType, pruner.PruneFunc] {
+	plugins, err := pluginmanager.ListPlugins(dockerCLI, rootCmd)
+	if err != nil {
+		_, _ = fmt.Fprintln(dockerCLI.Err(), "WARNING: skipping pruning of CLI plugins: failed to list plugins:", err)
+	}
+
+	// The prune-functions of plugins are created once, so that a plugin that
+	// is skipped when validating the options is also skipped when pruning.
+	pluginPruners := make(map[pruner.ContentType]pruner.PruneFunc)
+	var pluginTypes []pruner.ContentType
+	for _, p := range plugins {
+		if p.Err != nil || !p.Prune {
+			continue
+		}
+		contentType := pruner.ContentType(p.Name)
+		pluginPruners[contentType] = pluginPruneFunc(p)
+		pluginTypes = append(pluginTypes, contentType)
+	}
+
+	return func(yield func(pruner.ContentType, pruner.PruneFunc) bool) {
+		seen := make(map[pruner.ContentType]struct{})
+		for contentType, pruneFn := range pruner.List() {
+			seen[contentType] = struct{}{}
+			if !yield(contentType, pruneFn) {
+				return
+			}
+		}
+		for _, contentType := range pluginTypes {
+			if _, exists := seen[contentType]; exists {
+				continue
+			}
+			if !yield(contentType, pluginPruners[contentType]) {
+				return
+			}
+		}
+	}
+}
+
+// pluginPruneFunc returns a [pruner.PruneFunc] that invokes the prune
+// subcommand of the given plugin.
+//
+// A plugin that fails when validating the options is skipped, and not
+// invoked again to prune.
+func pluginPruneFunc(p pluginmanager.Plugin) pruner.PruneFunc {
+	var skipped bool
+	return func(ctx context.Context, dockerCLI command.Cli, pruneOpts pruner.PruneOptions) (uint64, string, error) {
+		if skipped {
+			return 0, "", errdefs.ErrNotImplemented
+		}
+		result, err := p.RunPrune(ctx, pluginmanager.PrunePluginData{
+			Confirmed: pruneOpts.Confirmed,
+			All:       pruneOpts.All,
+			Filters:   command.PruneFilters(dockerCLI, pruneOpts.Filter.Value()),
+		})
+		if err != nil {
+			if !pruneOpts.Confirmed {
+				// A plugin that fails when validating the options must not
+				// prevent pruning other content; skip it.
+				skipped = true
+				_, _ = fmt.Fprintf(dockerCLI.Err(), "WARNING: skipping pruning of CLI plugin %s: %v\n", p.Name, err)
+				return 0, "", errdefs.ErrNotImplemented
+			}
+			return 0, "", fmt.Errorf("plugin %s: %w", p.Name, err)
+		}
+		if pruneOpts.OnDeleted != nil {
+			for _, id := range result.Deleted {
+				pruneOpts.OnDeleted(id)
+			}
+		}
+		return result.SpaceReclaimed, result.Details, nil
+	}
 }
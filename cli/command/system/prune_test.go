@@ -4,15 +4,23 @@
 	"context"
 	"errors"
 	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 
+	"github.com/docker/cli/cli/config"
 	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/internal/test"
 	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/api/types/filters"
+	"github.com/moby/moby/api/types/image"
 	"github.com/moby/moby/api/types/network"
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/skip"
 
 	// Make sure pruners are registered for tests (they're included automatically when building).
 	_ "github.com/docker/cli/cli/command/builder"
@@ -67,6 +75,158 @@ func TestPrunePromptFilters(t *testing.T) {
 	assert.Check(t, is.Equal(expected, cli.OutBuffer().String()))
 }
 
+func TestPrunePromptIncludesPlugins(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "requires a shell-script plugin")
+	config.SetDir(t.TempDir())
+
+	pluginDir := t.TempDir()
+	const pluginScript = `#!/bin/sh
+if [ "$1" = "docker-cli-plugin-metadata" ]; then
+	echo '{"SchemaVersion":"0.1.0","Vendor":"Example","Prune":true}'
+	exit 0
+fi
+echo '{"Details":"all unused caches"}'
+`
+	err := os.WriteFile(filepath.Join(pluginDir, "docker-cleaner"), []byte(pluginScript), 0o755)
+	assert.NilError(t, err)
+
+	cli := test.NewFakeCli(&fakeClient{version: "1.30"})
+	cli.SetConfigFile(&configfile.ConfigFile{
+		CLIPluginsExtraDirs: []string{pluginDir},
+	})
+	cmd := newPruneCommand(cli)
+	cmd.SetArgs([]string{})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.ErrorContains(t, cmd.Execute(), "system prune has been cancelled")
+	expected := `WARNING! This will remove:
+  - all stopped containers
+  - all networks not used by at least one container
+  - all dangling images
+  - all unused caches
+
+Are you sure you want to continue? [y/N] `
+	assert.Check(t, is.Equal(expected, cli.OutBuffer().String()))
+}
+
+func TestPruneFailingPlugin(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "requires a shell-script plugin")
+	config.SetDir(t.TempDir())
+
+	pluginDir := t.TempDir()
+	const pluginScript = `#!/bin/sh
+if [ "$1" = "docker-cli-plugin-metadata" ]; then
+	echo '{"SchemaVersion":"0.1.0","Vendor":"Example","Prune":true}'
+	exit 0
+fi
+exit 1
+`
+	err := os.WriteFile(filepath.Join(pluginDir, "docker-cleaner"), []byte(pluginScript), 0o755)
+	assert.NilError(t, err)
+
+	var containersPruned bool
+	cli := test.NewFakeCli(&fakeClient{
+		version: "1.30",
+		containerPruneFunc: func(context.Context, filters.Args) (container.PruneReport, error) {
+			containersPruned = true
+			return container.PruneReport{}, nil
+		},
+	})
+	cli.SetConfigFile(&configfile.ConfigFile{
+		CLIPluginsExtraDirs: []string{pluginDir},
+	})
+	cmd := newPruneCommand(cli)
+	cmd.SetArgs([]string{"--force"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, containersPruned)
+	assert.Check(t, is.Contains(cli.ErrBuffer().String(), "WARNING: skipping pruning of CLI plugin cleaner: "))
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), "Total reclaimed space: 0B"))
+}
+
+func TestPruneDryRun(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		version: "1.30",
+		containerPruneFunc: func(ctx context.Context, pruneFilters filters.Args) (container.PruneReport, error) {
+			return container.PruneReport{}, errors.New("fakeClient containerPruneFunc should not be called")
+		},
+	})
+	cmd := newPruneCommand(cli)
+	cmd.SetArgs([]string{"--dry-run", "--filter", "until=24h"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.NilError(t, cmd.Execute())
+	expected := `The following would be removed:
+  - all stopped containers
+  - all networks not used by at least one container
+  - all dangling images
+
+  Items to be pruned will be filtered with:
+  - until=24h
+`
+	assert.Check(t, is.Equal(expected, cli.OutBuffer().String()))
+}
+
+func TestPruneDryRunJSON(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{version: "1.30"})
+	cmd := newPruneCommand(cli)
+	cmd.SetArgs([]string{"--dry-run", "--format", "json"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.NilError(t, cmd.Execute())
+	expected := `{"DryRun":true,"Results":[` +
+		`{"ContentType":"container","Deleted":[],"SpaceReclaimed":0,"Details":"all stopped containers"},` +
+		`{"ContentType":"network","Deleted":[],"SpaceReclaimed":0,"Details":"all networks not used by at least one container"},` +
+		`{"ContentType":"image","Deleted":[],"SpaceReclaimed":0,"Details":"all dangling images"}` +
+		`],"SpaceReclaimed":0}` + "\n"
+	assert.Check(t, is.Equal(expected, cli.OutBuffer().String()))
+}
+
+func TestPruneFormatJSON(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		version: "1.30",
+		containerPruneFunc: func(ctx context.Context, pruneFilters filters.Args) (container.PruneReport, error) {
+			return container.PruneReport{ContainersDeleted: []string{"container1", "container2"}, SpaceReclaimed: 10}, nil
+		},
+		networkPruneFunc: func(ctx context.Context, pruneFilters filters.Args) (network.PruneReport, error) {
+			return network.PruneReport{}, errors.New("network prune failed")
+		},
+		imagesPruneFunc: func(ctx context.Context, pruneFilter filters.Args) (image.PruneReport, error) {
+			return image.PruneReport{
+				ImagesDeleted: []image.DeleteResponse{
+					{Untagged: "example:latest"},
+					{Deleted: "sha256:1234"},
+				},
+				SpaceReclaimed: 32,
+			}, nil
+		},
+	})
+	cmd := newPruneCommand(cli)
+	cmd.SetArgs([]string{"--force", "--format", "json"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.ErrorContains(t, cmd.Execute(), "network prune failed")
+	expected := `{"DryRun":false,"Results":[` +
+		`{"ContentType":"container","Deleted":["container1","container2"],"SpaceReclaimed":10},` +
+		`{"ContentType":"network","Deleted":[],"SpaceReclaimed":0,"Error":"network prune failed"},` +
+		`{"ContentType":"image","Deleted":["sha256:1234"],"SpaceReclaimed":32}` +
+		`],"SpaceReclaimed":42}` + "\n"
+	assert.Check(t, is.Equal(expected, cli.OutBuffer().String()))
+}
+
+func TestPruneFormatPrompt(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{version: "1.30"})
+	cli.SetIn(streams.NewIn(io.NopCloser(strings.NewReader("y\n"))))
+	cmd := newPruneCommand(cli)
+	cmd.SetArgs([]string{"--format", "json"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Contains(cli.ErrBuffer().String(), "Are you sure you want to continue? [y/N] "))
+	assert.Check(t, strings.HasPrefix(cli.OutBuffer().String(), `{"DryRun":false,"Results":[`))
+}
+
 func TestSystemPrunePromptTermination(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	t.Cleanup(cancel)
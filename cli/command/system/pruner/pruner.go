@@ -77,6 +77,16 @@ type PruneOptions struct {
 	Confirmed bool
 	All       bool // Remove all unused content not just dangling (exact meaning differs per content-type).
 	Filter    opts.FilterOpt
+
+	// Retention defines rules for content to keep when pruning (currently
+	// only used for images).
+	Retention RetentionPolicy
+
+	// OnDeleted is an optional callback that is called with the ID (or
+	// reference) of each object removed when executing the prune. It
+	// allows callers to collect the content pruned in a structured form,
+	// for example to produce machine-readable output.
+	OnDeleted func(id string)
 }
 
 // registered holds a map of PruneFunc functions registered through [Register].
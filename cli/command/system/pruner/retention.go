@@ -0,0 +1,138 @@
+package pruner
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/opts"
+	"github.com/docker/go-units"
+	"github.com/spf13/pflag"
+)
+
+// RetentionPolicy defines rules for content to keep when pruning. Retention
+// policies are applied client-side, and are currently only supported for
+// images.
+type RetentionPolicy struct {
+	// KeepLast is the number of most recently created images to keep
+	// for each repository.
+	KeepLast int
+	// KeepUsedWithin keeps tagged images that were created, or used to
+	// create a container, within the given duration. The daemon does not
+	// record when an image was last used, so the creation time of the most
+	// recent container that uses the image is taken as the time the image
+	// was last used.
+	KeepUsedWithin time.Duration
+	// KeepStorage is the amount of disk space (in bytes) to keep. Content
+	// is pruned oldest-first until disk usage is below this amount.
+	KeepStorage int64
+	// FromConfig is set if rules are set in the CLI's configuration file.
+	FromConfig bool
+}
+
+// IsSet returns whether any retention rule is set.
+func (p RetentionPolicy) IsSet() bool {
+	return p.KeepLast > 0 || p.KeepUsedWithin > 0 || p.KeepStorage > 0
+}
+
+// String returns a short description of the policy for use in confirmation
+// messages (for example, "keeping the 3 most recent images per repository").
+func (p RetentionPolicy) String() string {
+	var rules []string
+	if p.KeepLast > 0 {
+		rules = append(rules, "the "+strconv.Itoa(p.KeepLast)+" most recent images per repository")
+	}
+	if p.KeepUsedWithin > 0 {
+		rules = append(rules, "tagged images used within "+p.KeepUsedWithin.String())
+	}
+	if p.KeepStorage > 0 {
+		rules = append(rules, units.BytesSize(float64(p.KeepStorage))+" of disk space")
+	}
+	if len(rules) == 0 {
+		return ""
+	}
+	return "keeping " + strings.Join(rules, ", ")
+}
+
+// ConfigNotice returns a notice that the policy is applied because rules are
+// set in the CLI's configuration file, or an empty string otherwise. Images
+// are pruned differently when a policy is set, so the notice is printed to
+// make users aware of the policy when no option is set.
+func (p RetentionPolicy) ConfigNotice() string {
+	if !p.FromConfig || !p.IsSet() {
+		return ""
+	}
+	return `NOTE: applying the "pruneRetention" policy from the configuration file: ` + p.String()
+}
+
+// RetentionOptions holds the command-line options to configure a
+// [RetentionPolicy].
+type RetentionOptions struct {
+	keepLast       int
+	keepUsedWithin time.Duration
+	keepStorage    opts.MemBytes
+	flags          *pflag.FlagSet
+}
+
+// InstallFlags adds the flags to configure a [RetentionPolicy] to the
+// given flag-set.
+func (o *RetentionOptions) InstallFlags(flags *pflag.FlagSet) {
+	o.flags = flags
+	flags.IntVar(&o.keepLast, "keep-last", 0, "Number of most recent images to keep per repository")
+	flags.DurationVar(&o.keepUsedWithin, "keep-used-within", 0, `Keep tagged images created, or used to create a container, within this duration (e.g. "168h")`)
+	flags.Var(&o.keepStorage, "keep-storage", "Amount of disk space to keep for images")
+}
+
+// Policy returns the [RetentionPolicy] for the given options. Rules defined
+// in the CLI's configuration file are used as defaults, and are overridden
+// by rules that are set through flags.
+func (o *RetentionOptions) Policy(cfg *configfile.ConfigFile) (RetentionPolicy, error) {
+	var policy RetentionPolicy
+	if cfg != nil && cfg.PruneRetention != nil {
+		var err error
+		policy, err = parseRetention(cfg.PruneRetention)
+		if err != nil {
+			return RetentionPolicy{}, fmt.Errorf("invalid pruneRetention in configuration file: %w", err)
+		}
+		policy.FromConfig = policy.IsSet()
+	}
+	if o.flags != nil {
+		if o.flags.Changed("keep-last") {
+			policy.KeepLast = o.keepLast
+		}
+		if o.flags.Changed("keep-used-within") {
+			policy.KeepUsedWithin = o.keepUsedWithin
+		}
+		if o.flags.Changed("keep-storage") {
+			policy.KeepStorage = o.keepStorage.Value()
+		}
+	}
+	if policy.KeepLast < 0 {
+		return RetentionPolicy{}, fmt.Errorf("invalid keep-last: %d: must be a positive number", policy.KeepLast)
+	}
+	if policy.KeepUsedWithin < 0 {
+		return RetentionPolicy{}, fmt.Errorf("invalid keep-used-within: %s: must be a positive duration", policy.KeepUsedWithin)
+	}
+	return policy, nil
+}
+
+func parseRetention(cfg *configfile.PruneRetention) (RetentionPolicy, error) {
+	policy := RetentionPolicy{KeepLast: cfg.KeepLast}
+	if cfg.KeepUsedWithin != "" {
+		d, err := time.ParseDuration(cfg.KeepUsedWithin)
+		if err != nil {
+			return RetentionPolicy{}, fmt.Errorf("invalid keepUsedWithin: %w", err)
+		}
+		policy.KeepUsedWithin = d
+	}
+	if cfg.KeepStorage != "" {
+		var keepStorage opts.MemBytes
+		if err := keepStorage.Set(cfg.KeepStorage); err != nil {
+			return RetentionPolicy{}, fmt.Errorf("invalid keepStorage: %w", err)
+		}
+		policy.KeepStorage = keepStorage.Value()
+	}
+	return policy, nil
+}
@@ -23,9 +23,10 @@ func init() {
 }
 
 type pruneOptions struct {
-	all    bool
-	force  bool
-	filter opts.FilterOpt
+	all       bool
+	force     bool
+	filter    opts.FilterOpt
+	onDeleted func(id string)
 }
 
 // newPruneCommand returns a new cobra prune command for volumes
@@ -103,6 +104,9 @@ func runPrune(ctx context.Context, dockerCli command.Cli, options pruneOptions)
 		output = "Deleted Volumes:\n"
 		for _, id := range report.VolumesDeleted {
 			output += id + "\n"
+			if options.onDeleted != nil {
+				options.onDeleted(id)
+			}
 		}
 		spaceReclaimed = report.SpaceReclaimed
 	}
@@ -133,7 +137,8 @@ func pruneFn(ctx context.Context, dockerCli command.Cli, options pruner.PruneOpt
 		return 0, confirmMsg, cancelledErr{errors.New("volume prune has been cancelled")}
 	}
 	return runPrune(ctx, dockerCli, pruneOptions{
-		force:  true,
-		filter: options.Filter,
+		force:     true,
+		filter:    options.Filter,
+		onDeleted: options.OnDeleted,
 	})
 }
@@ -37,6 +37,7 @@ type ConfigFile struct {
 	ConfigFormat         string                       `json:"configFormat,omitempty"`
 	NodesFormat          string                       `json:"nodesFormat,omitempty"`
 	PruneFilters         []string                     `json:"pruneFilters,omitempty"`
+	PruneRetention       *PruneRetention              `json:"pruneRetention,omitempty"`
 	Proxies              map[string]ProxyConfig       `json:"proxies,omitempty"`
 	CurrentContext       string                       `json:"currentContext,omitempty"`
 	CLIPluginsExtraDirs  []string                     `json:"cliPluginsExtraDirs,omitempty"`
@@ -73,6 +74,20 @@ type configEnv struct {
 //	}
 const DockerEnvConfigKey = "DOCKER_AUTH_CONFIG"
 
+// PruneRetention contains the default retention rules that are applied
+// when pruning images with "docker image prune" and "docker system prune".
+type PruneRetention struct {
+	// KeepLast is the number of most recently created images to keep
+	// for each repository.
+	KeepLast int `json:"keepLast,omitempty"`
+	// KeepUsedWithin keeps tagged images that were created, or used by
+	// a container, within the given duration (for example, "168h").
+	KeepUsedWithin string `json:"keepUsedWithin,omitempty"`
+	// KeepStorage is the amount of disk space to keep for images (for
+	// example, "20GB").
+	KeepStorage string `json:"keepStorage,omitempty"`
+}
+
 // ProxyConfig contains proxy configuration settings
 type ProxyConfig struct {
 	HTTPProxy  string `json:"httpProxy,omitempty"`
@@ -1,54 +1,288 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"syscall"
 
+	"github.com/docker/cli/cli"
 	pluginmanager "github.com/docker/cli/cli-plugins/manager"
 	"github.com/docker/cli/cli/command"
+	"github.com/google/shlex"
 	"github.com/spf13/cobra"
 )
 
 const (
 	keyBuilderAlias = "builder"
+
+	// aliasAnnotation is the annotation set on command stubs for
+	// user-defined aliases. Its value is the alias' definition.
+	aliasAnnotation = "user-alias"
 )
 
-var allowedAliases = map[string]struct{}{
-	keyBuilderAlias: {},
+// aliasPlaceholder matches argument placeholders in an alias definition:
+// "$1", "$2", etc. refer to individual arguments, "$@" to all arguments,
+// and "$$" is a literal "$". Shell aliases are not expanded, but passed to
+// the shell as is, so "$$" is the process ID of the shell in shell aliases.
+var aliasPlaceholder = regexp.MustCompile(`\$([1-9][0-9]*|@|\$)`)
+
+// shellAlias is a user-defined alias that runs a shell command. Shell
+// aliases are defined with a "!" prefix.
+type shellAlias struct {
+	name    string
+	command string
+	args    []string
 }
 
+// processAliases handles the "builder" alias, which allows a plugin to be
+// used as builder instead of the builtin "docker build" command.
 func processAliases(dockerCli command.Cli, cmd *cobra.Command, args, osArgs []string) ([]string, []string, []string, error) {
 	var err error
 	var envs []string
 	aliasMap := dockerCli.ConfigFile().Aliases
-	aliases := make([][2][]string, 0, len(aliasMap))
 
-	for k, v := range aliasMap {
-		if _, ok := allowedAliases[k]; !ok {
-			return args, osArgs, envs, fmt.Errorf("not allowed to alias %q (allowed: %#v)", k, allowedAliases)
-		}
+	var builderAlias []string
+	if v, ok := aliasMap[keyBuilderAlias]; ok {
 		if c, _, err := cmd.Find(strings.Split(v, " ")); err == nil {
 			if !pluginmanager.IsPluginCommand(c) {
 				return args, osArgs, envs, fmt.Errorf("not allowed to alias with builtin %q as target", v)
 			}
 		}
-		aliases = append(aliases, [2][]string{{k}, {v}})
+		builderAlias = []string{v}
 	}
 
-	args, osArgs, envs, err = processBuilder(dockerCli, cmd, args, os.Args)
+	args, osArgs, envs, err = processBuilder(dockerCli, cmd, args, osArgs)
 	if err != nil {
-		return args, os.Args, envs, err
+		return args, osArgs, envs, err
 	}
 
-	for _, al := range aliases {
+	if builderAlias != nil {
 		var didChange bool
-		args, didChange = stringSliceReplaceAt(args, al[0], al[1], 0)
+		args, didChange = stringSliceReplaceAt(args, []string{keyBuilderAlias}, builderAlias, 0)
 		if didChange {
-			osArgs, _ = stringSliceReplaceAt(osArgs, al[0], al[1], -1)
-			break
+			osArgs, _ = stringSliceReplaceAt(osArgs, []string{keyBuilderAlias}, builderAlias, -1)
 		}
 	}
 
 	return args, osArgs, envs, nil
 }
+
+// userAliases returns the user-defined aliases from the configuration file,
+// excluding the "builder" alias. An error is returned if an alias has an
+// invalid name, or shadows a builtin command.
+func userAliases(dockerCli command.Cli, rootCmd *cobra.Command) (map[string]string, error) {
+	aliases := make(map[string]string)
+	for name, definition := range dockerCli.ConfigFile().Aliases {
+		if name == keyBuilderAlias {
+			continue
+		}
+		if name == "" || strings.HasPrefix(name, "-") || strings.ContainsAny(name, " \t\n") {
+			return nil, fmt.Errorf("invalid alias name %q", name)
+		}
+		if c := builtinCommand(rootCmd, name); c != nil {
+			return nil, fmt.Errorf("not allowed to alias %q: alias shadows builtin command %q", name, c.Name())
+		}
+		if strings.TrimSpace(strings.TrimPrefix(definition, "!")) == "" {
+			return nil, fmt.Errorf("invalid alias %q: alias definition is empty", name)
+		}
+		aliases[name] = definition
+	}
+	return aliases, nil
+}
+
+// builtinCommand returns the builtin top-level command with the given name
+// or alias, or nil if there is no such command.
+func builtinCommand(rootCmd *cobra.Command, name string) *cobra.Command {
+	for _, c := range rootCmd.Commands() {
+		if pluginmanager.IsPluginCommand(c) || c.Annotations[aliasAnnotation] != "" {
+			continue
+		}
+		if c.Name() == name || c.HasAlias(name) {
+			return c
+		}
+	}
+	return nil
+}
+
+// expandAliases expands the user-defined alias in args[0], if any. Aliases
+// can refer to other aliases; an error is returned if the expansion is
+// recursive, or if an alias has the same name as a plugin, as reported by
+// isPlugin. If the alias (or an alias it refers to) is a shell alias, a
+// shellAlias is returned to be executed instead.
+func expandAliases(aliases map[string]string, isPlugin func(name string) bool, args []string) ([]string, *shellAlias, error) {
+	var chain []string
+	for len(args) > 0 {
+		name := args[0]
+		definition, ok := aliases[name]
+		if !ok {
+			break
+		}
+		// Plugins are only known after aliases are expanded, so they're
+		// only looked up for aliases that are used.
+		if isPlugin(name) {
+			return nil, nil, fmt.Errorf("not allowed to alias %q: alias shadows plugin %q", name, name)
+		}
+		for _, n := range chain {
+			if n == name {
+				return nil, nil, fmt.Errorf("alias %q is recursive: %s", chain[0], strings.Join(append(chain, name), " -> "))
+			}
+		}
+		chain = append(chain, name)
+
+		if script, ok := strings.CutPrefix(definition, "!"); ok {
+			return nil, &shellAlias{name: name, command: script, args: args[1:]}, nil
+		}
+		expanded, err := expandAlias(name, definition, args[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		args = expanded
+	}
+	return args, nil, nil
+}
+
+// expandAlias expands a (non-shell) alias definition with the given
+// arguments. Arguments are appended to the expanded command, unless the
+// definition contains placeholders to refer to them.
+func expandAlias(name, definition string, args []string) ([]string, error) {
+	words, err := shlex.Split(definition)
+	if err != nil {
+		return nil, fmt.Errorf("invalid alias %q: %w", name, err)
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("invalid alias %q: alias definition is empty", name)
+	}
+	if !hasPlaceholders(definition) {
+		return append(words, args...), nil
+	}
+
+	expanded := make([]string, 0, len(words)+len(args))
+	for _, word := range words {
+		if word == "$@" {
+			expanded = append(expanded, args...)
+			continue
+		}
+		var missing int
+		word = aliasPlaceholder.ReplaceAllStringFunc(word, func(p string) string {
+			switch p {
+			case "$$":
+				return "$"
+			case "$@":
+				return strings.Join(args, " ")
+			}
+			n, _ := strconv.Atoi(p[1:])
+			if n > len(args) {
+				if n > missing {
+					missing = n
+				}
+				return ""
+			}
+			return args[n-1]
+		})
+		if missing > 0 {
+			return nil, fmt.Errorf("alias %q requires at least %d argument(s)", name, missing)
+		}
+		expanded = append(expanded, word)
+	}
+	return expanded, nil
+}
+
+// hasPlaceholders returns whether the alias definition refers to its
+// arguments through placeholders.
+func hasPlaceholders(definition string) bool {
+	for _, m := range aliasPlaceholder.FindAllString(definition, -1) {
+		if m != "$$" {
+			return true
+		}
+	}
+	return false
+}
+
+// isInstalledPlugin returns whether a CLI plugin with the given name is
+// installed.
+func isInstalledPlugin(dockerCli command.Cli, rootCmd *cobra.Command) func(string) bool {
+	return func(name string) bool {
+		_, err := pluginmanager.GetPlugin(name, dockerCli, rootCmd)
+		return err == nil
+	}
+}
+
+// run executes the shell alias, passing its arguments as positional
+// parameters. Arguments are appended to the command, unless it refers
+// to them through placeholders.
+func (a *shellAlias) run(ctx context.Context, dockerCli command.Cli) error {
+	script := a.command
+	if !hasPlaceholders(script) {
+		script += ` "$@"`
+	}
+	c := exec.CommandContext(ctx, "sh", "-c", script, a.name) // #nosec G204 -- the alias is defined by the user
+	c.Args = append(c.Args, a.args...)
+	c.Stdin = dockerCli.In()
+	c.Stdout = dockerCli.Out()
+	c.Stderr = dockerCli.Err()
+	if err := c.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return fmt.Errorf("failed to run alias %q: %w", a.name, err)
+		}
+		statusCode := 1
+		if ws, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			statusCode = ws.ExitStatus()
+		}
+		return cli.StatusError{StatusCode: statusCode}
+	}
+	return nil
+}
+
+// replaceArgs replaces args at the end of osArgs with the expanded
+// arguments.
+func replaceArgs(osArgs, args, expanded []string) []string {
+	idx := len(osArgs) - len(args)
+	if idx < 0 || stringSliceIndex(osArgs[idx:], args) != 0 {
+		return osArgs
+	}
+	return append(append([]string{}, osArgs[:idx]...), expanded...)
+}
+
+// addAliasCommandStubs adds a command stub for each user-defined alias, so
+// that aliases are shown in the CLI's usage output, and can be completed.
+func addAliasCommandStubs(dockerCli command.Cli, rootCmd *cobra.Command) error {
+	aliases, err := userAliases(dockerCli, rootCmd)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	existing := make(map[string]bool)
+	for _, c := range rootCmd.Commands() {
+		existing[c.Name()] = true
+	}
+	for _, name := range names {
+		if existing[name] {
+			// already added, or a plugin with the same name.
+			continue
+		}
+		definition := aliases[name]
+		rootCmd.AddCommand(&cobra.Command{
+			Use:                name,
+			Short:              `Alias for "` + definition + `"`,
+			Annotations:        map[string]string{aliasAnnotation: definition},
+			DisableFlagParsing: true,
+			RunE: func(cmd *cobra.Command, _ []string) error {
+				return fmt.Errorf("docker: unknown command: docker %s\n\nRun 'docker --help' for more information", cmd.Name())
+			},
+			ValidArgsFunction: completeAlias(rootCmd, definition),
+		})
+	}
+	return nil
+}
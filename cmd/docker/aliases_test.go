@@ -0,0 +1,263 @@
+package main
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/internal/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/skip"
+)
+
+func newAliasTestRootCmd() *cobra.Command {
+	rootCmd := &cobra.Command{Use: "docker"}
+	cli.SetupRootCommand(rootCmd)
+	psCmd := &cobra.Command{
+		Use:   "ps",
+		Short: "List containers",
+		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return append(args, toComplete), cobra.ShellCompDirectiveNoFileComp
+		},
+		Run: func(*cobra.Command, []string) {},
+	}
+	psCmd.Flags().BoolP("all", "a", false, "Show all containers")
+	rootCmd.AddCommand(
+		psCmd,
+		&cobra.Command{Use: "container", Aliases: []string{"c"}, Run: func(*cobra.Command, []string) {}},
+	)
+	return rootCmd
+}
+
+func TestUserAliases(t *testing.T) {
+	tests := []struct {
+		doc         string
+		aliases     map[string]string
+		expected    map[string]string
+		expectedErr string
+	}{
+		{
+			doc:      "builder alias is excluded",
+			aliases:  map[string]string{"builder": "buildx", "dps": "ps -a"},
+			expected: map[string]string{"dps": "ps -a"},
+		},
+		{
+			doc:         "shadows builtin command",
+			aliases:     map[string]string{"ps": "ps -a"},
+			expectedErr: `not allowed to alias "ps": alias shadows builtin command "ps"`,
+		},
+		{
+			doc:         "shadows builtin command alias",
+			aliases:     map[string]string{"c": "container ls"},
+			expectedErr: `not allowed to alias "c": alias shadows builtin command "container"`,
+		},
+		{
+			doc:         "invalid name",
+			aliases:     map[string]string{"--all": "ps -a"},
+			expectedErr: `invalid alias name "--all"`,
+		},
+		{
+			doc:         "empty definition",
+			aliases:     map[string]string{"empty": "!"},
+			expectedErr: `invalid alias "empty": alias definition is empty`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			dockerCli := test.NewFakeCli(nil)
+			dockerCli.SetConfigFile(&configfile.ConfigFile{Aliases: tc.aliases})
+			aliases, err := userAliases(dockerCli, newAliasTestRootCmd())
+			if tc.expectedErr != "" {
+				assert.Check(t, is.Error(err, tc.expectedErr))
+				return
+			}
+			assert.NilError(t, err)
+			assert.Check(t, is.DeepEqual(aliases, tc.expected))
+		})
+	}
+}
+
+func TestExpandAliases(t *testing.T) {
+	aliases := map[string]string{
+		"dps":     `ps --format 'table {{.Names}}\t{{.Status}}'`,
+		"running": "dps --filter status=running",
+		"logsf":   "logs --follow --tail $2 $1",
+		"rmall":   "rm -f $@ --volumes",
+		"price":   "run --env PRICE=$$5 $1",
+		"hello":   "!echo hello",
+		"greet":   "dps --filter name=$1",
+		"loop1":   "loop2 foo",
+		"loop2":   "loop1 bar",
+		"scout":   "image ls",
+		"sc":      "scout",
+		"pid":     "!echo $$",
+	}
+	isPlugin := func(name string) bool { return name == "scout" }
+
+	tests := []struct {
+		doc           string
+		args          []string
+		expected      []string
+		expectedShell *shellAlias
+		expectedErr   string
+	}{
+		{
+			doc:      "not an alias",
+			args:     []string{"ps", "-a"},
+			expected: []string{"ps", "-a"},
+		},
+		{
+			doc:      "arguments are appended",
+			args:     []string{"dps", "-a"},
+			expected: []string{"ps", "--format", `table {{.Names}}\t{{.Status}}`, "-a"},
+		},
+		{
+			doc:      "alias of alias",
+			args:     []string{"running", "-n", "2"},
+			expected: []string{"ps", "--format", `table {{.Names}}\t{{.Status}}`, "--filter", "status=running", "-n", "2"},
+		},
+		{
+			doc:      "positional placeholders",
+			args:     []string{"logsf", "web", "10"},
+			expected: []string{"logs", "--follow", "--tail", "10", "web"},
+		},
+		{
+			doc:         "missing arguments",
+			args:        []string{"logsf", "web"},
+			expectedErr: `alias "logsf" requires at least 2 argument(s)`,
+		},
+		{
+			doc:      "all arguments placeholder",
+			args:     []string{"rmall", "one", "two"},
+			expected: []string{"rm", "-f", "one", "two", "--volumes"},
+		},
+		{
+			doc:      "escaped dollar",
+			args:     []string{"price", "alpine"},
+			expected: []string{"run", "--env", "PRICE=$5", "alpine"},
+		},
+		{
+			doc:      "placeholder in alias of alias",
+			args:     []string{"greet", "web"},
+			expected: []string{"ps", "--format", `table {{.Names}}\t{{.Status}}`, "--filter", "name=web"},
+		},
+		{
+			doc:           "shell alias",
+			args:          []string{"hello", "world"},
+			expectedShell: &shellAlias{name: "hello", command: "echo hello", args: []string{"world"}},
+		},
+		{
+			doc:         "shadows plugin",
+			args:        []string{"scout", "quickview"},
+			expectedErr: `not allowed to alias "scout": alias shadows plugin "scout"`,
+		},
+		{
+			doc:         "alias of alias shadows plugin",
+			args:        []string{"sc"},
+			expectedErr: `not allowed to alias "scout": alias shadows plugin "scout"`,
+		},
+		{
+			doc:           "dollar is not expanded in shell alias",
+			args:          []string{"pid"},
+			expectedShell: &shellAlias{name: "pid", command: "echo $$", args: []string{}},
+		},
+		{
+			doc:         "recursive alias",
+			args:        []string{"loop1"},
+			expectedErr: `alias "loop1" is recursive: loop1 -> loop2 -> loop1`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			expanded, shellCmd, err := expandAliases(aliases, isPlugin, tc.args)
+			if tc.expectedErr != "" {
+				assert.Check(t, is.Error(err, tc.expectedErr))
+				return
+			}
+			assert.NilError(t, err)
+			assert.Check(t, is.DeepEqual(expanded, tc.expected))
+			assert.Check(t, is.DeepEqual(shellCmd, tc.expectedShell, cmp.AllowUnexported(shellAlias{})))
+		})
+	}
+}
+
+func TestReplaceArgs(t *testing.T) {
+	osArgs := []string{"docker", "--context", "foo", "dps", "-a"}
+	actual := replaceArgs(osArgs, []string{"dps", "-a"}, []string{"ps", "--format", "{{.ID}}", "-a"})
+	assert.Check(t, is.DeepEqual(actual, []string{"docker", "--context", "foo", "ps", "--format", "{{.ID}}", "-a"}))
+}
+
+func TestShellAlias(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "requires a POSIX shell")
+
+	tests := []struct {
+		doc        string
+		alias      shellAlias
+		expected   string
+		statusCode int
+	}{
+		{
+			doc:      "arguments are appended",
+			alias:    shellAlias{name: "hello", command: "echo hello", args: []string{"big", "world"}},
+			expected: "hello big world\n",
+		},
+		{
+			doc:      "placeholders",
+			alias:    shellAlias{name: "hello", command: `echo "$2, $1"`, args: []string{"world", "hello"}},
+			expected: "hello, world\n",
+		},
+		{
+			doc:        "exit code",
+			alias:      shellAlias{name: "fail", command: "exit 3"},
+			statusCode: 3,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			dockerCli := test.NewFakeCli(nil)
+			err := tc.alias.run(context.Background(), dockerCli)
+			if tc.statusCode != 0 {
+				assert.Check(t, is.DeepEqual(err, cli.StatusError{StatusCode: tc.statusCode}))
+				return
+			}
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(dockerCli.OutBuffer().String(), tc.expected))
+		})
+	}
+}
+
+func TestAliasCommandStubs(t *testing.T) {
+	dockerCli := test.NewFakeCli(nil)
+	dockerCli.SetConfigFile(&configfile.ConfigFile{Aliases: map[string]string{
+		"builder": "buildx",
+		"dps":     "ps --all",
+		"hello":   "!echo hello",
+	}})
+	rootCmd := newAliasTestRootCmd()
+	assert.NilError(t, addAliasCommandStubs(dockerCli, rootCmd))
+	// Adding stubs is idempotent.
+	assert.NilError(t, addAliasCommandStubs(dockerCli, rootCmd))
+
+	usage := rootCmd.UsageString()
+	assert.Check(t, is.Contains(usage, "\nUser Aliases:\n  dps         Alias for \"ps --all\"\n  hello       Alias for \"!echo hello\"\n"))
+	assert.Check(t, strings.Count(usage, "dps") == 1, "alias should only be listed once:\n%s", usage)
+
+	t.Run("completion", func(t *testing.T) {
+		c, _, err := rootCmd.Find([]string{"dps"})
+		assert.NilError(t, err)
+		values, directive := c.ValidArgsFunction(c, []string{"foo"}, "ba")
+		assert.Check(t, is.DeepEqual(values, []string{"foo", "ba"}))
+		assert.Check(t, is.Equal(directive, cobra.ShellCompDirectiveNoFileComp))
+
+		c, _, err = rootCmd.Find([]string{"hello"})
+		assert.NilError(t, err)
+		values, _ = c.ValidArgsFunction(c, nil, "")
+		assert.Check(t, is.Len(values, 0))
+	})
+}
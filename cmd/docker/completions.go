@@ -1,7 +1,10 @@
 package main
 
 import (
+	"strings"
+
 	"github.com/docker/cli/cli/context/store"
+	"github.com/google/shlex"
 	"github.com/spf13/cobra"
 )
 
@@ -21,3 +24,32 @@ func completeContextNames(dockerCLI contextStoreProvider) func(*cobra.Command, [
 func completeLogLevels(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
 	return cobra.FixedCompletions(logLevels, cobra.ShellCompDirectiveNoFileComp)(nil, nil, "")
 }
+
+// completeAlias provides completion for a user-defined alias by delegating
+// to the command it expands to. Shell aliases are not completed.
+func completeAlias(rootCmd *cobra.Command, definition string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if strings.HasPrefix(definition, "!") {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		words, err := shlex.Split(definition)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		// Only use the part of the alias before the first placeholder.
+		for i, word := range words {
+			if aliasPlaceholder.MatchString(word) {
+				words = words[:i]
+				break
+			}
+		}
+		target, targetArgs, err := rootCmd.Find(append(words, args...))
+		if err != nil || target == rootCmd || target.ValidArgsFunction == nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		if err := target.ParseFlags(targetArgs); err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return target.ValidArgsFunction(target, target.Flags().Args(), toComplete)
+	}
+}
@@ -9,9 +9,11 @@
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/containerd/errdefs"
 	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli-plugins/hooks"
 	pluginmanager "github.com/docker/cli/cli-plugins/manager"
 	"github.com/docker/cli/cli-plugins/socket"
 	"github.com/docker/cli/cli/command"
@@ -134,7 +136,13 @@ func newDockerCommand(dockerCli *command.DockerCli) *cli.TopLevelCommand {
 			return fmt.Errorf("docker: unknown command: docker %s\n\nRun 'docker --help' for more information", args[0])
 		},
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-			return isSupported(cmd, dockerCli)
+			if err := isSupported(cmd, dockerCli); err != nil {
+				return err
+			}
+			if dockerCli.HooksEnabled() {
+				return pluginmanager.RunCLICommandPreHooks(cmd.Context(), dockerCli, cmd.Root(), cmd, args)
+			}
+			return nil
 		},
 		Version:               fmt.Sprintf("%s, build %s", version.Version, version.GitCommit),
 		DisableFlagsInUseLine: true,
@@ -238,6 +246,10 @@ func setHelpFunc(dockerCli command.Cli, cmd *cobra.Command) {
 			ccmd.Println(err)
 			return
 		}
+		if err := addAliasCommandStubs(dockerCli, ccmd.Root()); err != nil {
+			ccmd.Println(err)
+			return
+		}
 
 		if len(args) >= 1 {
 			err := tryRunPluginHelp(dockerCli, ccmd, args)
@@ -464,6 +476,20 @@ func runDocker(ctx context.Context, dockerCli *command.DockerCli) error {
 
 	dockerCli.InstrumentCobraCommands(ctx, cmd)
 
+	aliases, err := userAliases(dockerCli, cmd)
+	if err != nil {
+		return err
+	}
+	expanded, shellCmd, err := expandAliases(aliases, isInstalledPlugin(dockerCli, cmd), args)
+	if err != nil {
+		return err
+	}
+	if shellCmd != nil {
+		return shellCmd.run(ctx, dockerCli)
+	}
+	os.Args = replaceArgs(os.Args, args, expanded)
+	args = expanded
+
 	var envs []string
 	args, os.Args, envs, err = processAliases(dockerCli, cmd, args, os.Args)
 	if err != nil {
@@ -477,17 +503,32 @@ func runDocker(ctx context.Context, dockerCli *command.DockerCli) error {
 		if err := pluginmanager.AddPluginCommandStubs(dockerCli, cmd); err != nil {
 			return err
 		}
+		if err := addAliasCommandStubs(dockerCli, cmd); err != nil {
+			return err
+		}
 	}
 
+	// Record structured results of the command to pass to plugin hooks.
+	ctx, recorder := hooks.WithRecorder(ctx)
+
 	var subCommand *cobra.Command
 	if len(args) > 0 {
 		ccmd, _, err := cmd.Find(args)
 		subCommand = ccmd
 		if err != nil || pluginmanager.IsPluginCommand(ccmd) {
+			if ccmd != nil && dockerCli.HooksEnabled() {
+				if err := pluginmanager.RunPluginPreHooks(ctx, dockerCli, cmd, ccmd, args); err != nil {
+					return err
+				}
+			}
+			start := time.Now()
 			err := tryPluginRun(ctx, dockerCli, cmd, args[0], envs)
 			if err == nil {
 				if ccmd != nil && dockerCli.Out().IsTerminal() && dockerCli.HooksEnabled() {
-					pluginmanager.RunPluginHooks(ctx, dockerCli, cmd, ccmd, args)
+					pluginmanager.RunPluginHooks(ctx, dockerCli, cmd, ccmd, args, pluginmanager.CommandResult{
+						Duration:    time.Since(start),
+						ImageDigest: recorder.ImageDigest(),
+					})
 				}
 				return nil
 			}
@@ -507,6 +548,7 @@ func runDocker(ctx context.Context, dockerCli *command.DockerCli) error {
 	// We've parsed global args already, so reset args to those
 	// which remain.
 	cmd.SetArgs(args)
+	start := time.Now()
 	err = cmd.ExecuteContext(ctx)
 
 	// If the command is being executed in an interactive terminal
@@ -516,7 +558,11 @@ func runDocker(ctx context.Context, dockerCli *command.DockerCli) error {
 		if err != nil {
 			errMessage = err.Error()
 		}
-		pluginmanager.RunCLICommandHooks(ctx, dockerCli, cmd, subCommand, errMessage)
+		pluginmanager.RunCLICommandHooks(ctx, dockerCli, cmd, subCommand, pluginmanager.CommandResult{
+			ExitCode:    getExitCode(err),
+			Duration:    time.Since(start),
+			ImageDigest: recorder.ImageDigest(),
+		}, errMessage)
 	}
 
 	return err
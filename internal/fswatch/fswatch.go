@@ -0,0 +1,97 @@
+// Package fswatch reports changes to the files in a local directory tree.
+//
+// On Linux, changes are detected using inotify. On other platforms, the
+// directory tree is polled for changes.
+package fswatch
+
+import (
+	"sync"
+)
+
+// Root is reported instead of individual paths if changes may have been
+// missed, and the whole directory tree must be considered changed.
+const Root = "."
+
+// ExcludeFunc reports whether a path, relative to the root of the watched
+// directory, is excluded. Changes to excluded files are not reported, and
+// excluded directories are not watched.
+type ExcludeFunc func(rel string, isDir bool) bool
+
+// Watcher reports the paths of files and directories that are created,
+// modified, or removed in a directory tree. Paths are slash-separated, and
+// relative to the root of the directory tree.
+//
+// When a directory is created, only the path of the directory itself may be
+// reported, and not the paths of the files in it.
+type Watcher struct {
+	backend
+
+	root    string
+	exclude ExcludeFunc
+	events  chan string
+	errors  chan error
+
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// New starts watching the directory tree at root for changes.
+func New(root string, exclude ExcludeFunc) (*Watcher, error) {
+	w := &Watcher{
+		root:    root,
+		exclude: exclude,
+		events:  make(chan string, 128),
+		errors:  make(chan error, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	if err := w.start(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Events returns the channel on which the paths of changed files are
+// reported.
+func (w *Watcher) Events() <-chan string {
+	return w.events
+}
+
+// Errors returns the channel on which an error is reported if watching fails.
+// No changes are reported after an error.
+func (w *Watcher) Errors() <-chan error {
+	return w.errors
+}
+
+// Close stops watching for changes.
+func (w *Watcher) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.done)
+		w.closeErr = w.stop()
+	})
+	return w.closeErr
+}
+
+// send reports a changed path. It returns false if the watcher is closed.
+func (w *Watcher) send(rel string) bool {
+	select {
+	case w.events <- rel:
+		return true
+	case <-w.done:
+		return false
+	}
+}
+
+// fail reports an error that stops the watcher.
+func (w *Watcher) fail(err error) {
+	select {
+	case w.errors <- err:
+	default:
+	}
+}
+
+func (w *Watcher) excluded(rel string, isDir bool) bool {
+	return rel != Root && w.exclude != nil && w.exclude(rel, isDir)
+}
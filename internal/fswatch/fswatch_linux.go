@@ -0,0 +1,195 @@
+package fswatch
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const watchMask = unix.IN_ATTRIB | unix.IN_CLOSE_WRITE | unix.IN_CREATE | unix.IN_DELETE |
+	unix.IN_MODIFY | unix.IN_MOVED_FROM | unix.IN_MOVED_TO |
+	unix.IN_ONLYDIR | unix.IN_DONT_FOLLOW | unix.IN_EXCL_UNLINK
+
+// backend watches directories using inotify. Inotify watches are not
+// recursive, so a watch is added for each directory in the tree.
+type backend struct {
+	fd int
+
+	// wake is a pipe that is written to when the watcher is closed, to
+	// interrupt polling for events.
+	wake [2]int
+
+	// watches maps watch descriptors to the path of the watched directory.
+	watches map[int32]string
+}
+
+func (w *Watcher) start() error {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return fmt.Errorf("failed to initialize inotify: %w", err)
+	}
+	if err := unix.Pipe2(w.wake[:], unix.O_CLOEXEC|unix.O_NONBLOCK); err != nil {
+		_ = unix.Close(fd)
+		return err
+	}
+	w.fd = fd
+	w.watches = make(map[int32]string)
+	if err := w.addTree(Root); err != nil {
+		w.closeFDs()
+		return err
+	}
+	go w.loop()
+	return nil
+}
+
+func (w *Watcher) stop() error {
+	_, _ = unix.Write(w.wake[1], []byte{0})
+	<-w.stopped
+	w.closeFDs()
+	return nil
+}
+
+func (w *Watcher) closeFDs() {
+	_ = unix.Close(w.fd)
+	_ = unix.Close(w.wake[0])
+	_ = unix.Close(w.wake[1])
+}
+
+// addTree adds watches for a directory and its subdirectories. Directories
+// that are removed while adding watches are ignored.
+func (w *Watcher) addTree(rel string) error {
+	return filepath.WalkDir(filepath.Join(w.root, filepath.FromSlash(rel)), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		r, err := filepath.Rel(w.root, p)
+		if err != nil {
+			return err
+		}
+		r = filepath.ToSlash(r)
+		if w.excluded(r, true) {
+			return filepath.SkipDir
+		}
+		wd, err := unix.InotifyAddWatch(w.fd, p, watchMask)
+		switch {
+		case errors.Is(err, unix.ENOENT), errors.Is(err, unix.ENOTDIR):
+			return filepath.SkipDir
+		case errors.Is(err, unix.ENOSPC):
+			return fmt.Errorf("failed to watch %s: the maximum number of inotify watches is reached; consider increasing fs.inotify.max_user_watches", p)
+		case err != nil:
+			return fmt.Errorf("failed to watch %s: %w", p, err)
+		}
+		w.watches[int32(wd)] = r
+		return nil
+	})
+}
+
+// removeTree removes the watches for a directory and its subdirectories,
+// for example, because the directory was moved.
+func (w *Watcher) removeTree(rel string) {
+	for wd, dir := range w.watches {
+		if dir == rel || strings.HasPrefix(dir, rel+"/") {
+			_, _ = unix.InotifyRmWatch(w.fd, uint32(wd))
+			delete(w.watches, wd)
+		}
+	}
+}
+
+func (w *Watcher) loop() {
+	defer close(w.stopped)
+
+	buf := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+	fds := []unix.PollFd{
+		{Fd: int32(w.fd), Events: unix.POLLIN},
+		{Fd: int32(w.wake[0]), Events: unix.POLLIN},
+	}
+	for {
+		if _, err := unix.Poll(fds, -1); err != nil {
+			if errors.Is(err, unix.EINTR) {
+				continue
+			}
+			w.fail(err)
+			return
+		}
+		if fds[1].Revents != 0 {
+			return
+		}
+		n, err := unix.Read(w.fd, buf)
+		if err != nil {
+			if errors.Is(err, unix.EAGAIN) || errors.Is(err, unix.EINTR) {
+				continue
+			}
+			w.fail(err)
+			return
+		}
+		if err := w.handle(buf[:n]); err != nil {
+			if !errors.Is(err, errClosed) {
+				w.fail(err)
+			}
+			return
+		}
+	}
+}
+
+var errClosed = errors.New("watcher closed")
+
+// handle reports the changes in a buffer of inotify events.
+func (w *Watcher) handle(buf []byte) error {
+	for off := 0; off+unix.SizeofInotifyEvent <= len(buf); {
+		ev := (*unix.InotifyEvent)(unsafe.Pointer(&buf[off]))
+		nameStart := off + unix.SizeofInotifyEvent
+		off = nameStart + int(ev.Len)
+		if off > len(buf) {
+			return errors.New("invalid inotify event")
+		}
+		name := strings.TrimRight(string(buf[nameStart:off]), "\x00")
+
+		if ev.Mask&unix.IN_Q_OVERFLOW != 0 {
+			if !w.send(Root) {
+				return errClosed
+			}
+			continue
+		}
+		if ev.Mask&unix.IN_IGNORED != 0 {
+			delete(w.watches, ev.Wd)
+			continue
+		}
+		dir, ok := w.watches[ev.Wd]
+		if !ok || name == "" {
+			// Events for the watched directory itself are also reported
+			// for its parent directory.
+			continue
+		}
+		rel := path.Join(dir, name)
+		isDir := ev.Mask&unix.IN_ISDIR != 0
+		if w.excluded(rel, isDir) {
+			continue
+		}
+		if isDir {
+			switch {
+			case ev.Mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0:
+				if err := w.addTree(rel); err != nil {
+					return err
+				}
+			case ev.Mask&unix.IN_MOVED_FROM != 0:
+				w.removeTree(rel)
+			}
+		}
+		if !w.send(rel) {
+			return errClosed
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+//go:build !linux
+
+package fswatch
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// pollInterval is the interval at which the directory tree is scanned for
+// changes.
+const pollInterval = 500 * time.Millisecond
+
+// backend watches directories by periodically scanning the directory tree,
+// and comparing the modification time, size, and mode of files.
+type backend struct {
+	files map[string]fileState
+}
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+	mode    fs.FileMode
+}
+
+func (w *Watcher) start() error {
+	files, err := w.scan()
+	if err != nil {
+		return err
+	}
+	w.files = files
+	go w.poll()
+	return nil
+}
+
+func (w *Watcher) stop() error {
+	<-w.stopped
+	return nil
+}
+
+// scan returns the state of the files in the directory tree. Files that are
+// removed while scanning are ignored.
+func (w *Watcher) scan() (map[string]fileState, error) {
+	files := make(map[string]fileState)
+	err := filepath.WalkDir(w.root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		rel, err := filepath.Rel(w.root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == Root {
+			return nil
+		}
+		if w.excluded(rel, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		files[rel] = fileState{modTime: fi.ModTime(), size: fi.Size(), mode: fi.Mode()}
+		return nil
+	})
+	return files, err
+}
+
+func (w *Watcher) poll() {
+	defer close(w.stopped)
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+		}
+		files, err := w.scan()
+		if err != nil {
+			w.fail(err)
+			return
+		}
+		var changed []string
+		for p, s := range files {
+			if prev, ok := w.files[p]; !ok || prev != s {
+				changed = append(changed, p)
+			}
+		}
+		for p := range w.files {
+			if _, ok := files[p]; !ok {
+				changed = append(changed, p)
+			}
+		}
+		w.files = files
+		sort.Strings(changed)
+		for _, p := range changed {
+			if !w.send(p) {
+				return
+			}
+		}
+	}
+}
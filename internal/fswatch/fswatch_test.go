@@ -0,0 +1,66 @@
+package fswatch
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// waitFor waits for a path to be reported, and returns the other paths that
+// were reported before it.
+func waitFor(t *testing.T, w *Watcher, expected string) []string {
+	t.Helper()
+	var seen []string
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case p := <-w.Events():
+			if p == expected {
+				return seen
+			}
+			seen = append(seen, p)
+		case err := <-w.Errors():
+			t.Fatalf("unexpected error: %v", err)
+		case <-timeout:
+			t.Fatalf("timeout waiting for %q; got %v", expected, seen)
+		}
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	root := t.TempDir()
+	assert.NilError(t, os.MkdirAll(filepath.Join(root, "node_modules", "pkg"), 0o755))
+	assert.NilError(t, os.WriteFile(filepath.Join(root, "existing.txt"), []byte("hello"), 0o644))
+
+	w, err := New(root, func(rel string, _ bool) bool {
+		return rel == "node_modules" || strings.HasSuffix(rel, ".tmp")
+	})
+	assert.NilError(t, err)
+	defer w.Close()
+
+	assert.NilError(t, os.WriteFile(filepath.Join(root, "node_modules", "pkg", "index.js"), []byte("x"), 0o644))
+	assert.NilError(t, os.WriteFile(filepath.Join(root, "file.tmp"), []byte("x"), 0o644))
+	assert.NilError(t, os.WriteFile(filepath.Join(root, "existing.txt"), []byte("hello world"), 0o644))
+	seen := waitFor(t, w, "existing.txt")
+	assert.Check(t, is.Len(seen, 0))
+
+	// Files in new directories are reported once the directory is watched.
+	assert.NilError(t, os.Mkdir(filepath.Join(root, "sub"), 0o755))
+	waitFor(t, w, "sub")
+	assert.NilError(t, os.WriteFile(filepath.Join(root, "sub", "new.txt"), []byte("x"), 0o644))
+	seen = waitFor(t, w, "sub/new.txt")
+	for _, p := range seen {
+		assert.Check(t, !strings.HasPrefix(p, "node_modules") && !strings.HasSuffix(p, ".tmp"), p)
+	}
+
+	assert.NilError(t, os.RemoveAll(filepath.Join(root, "sub")))
+	waitFor(t, w, "sub")
+
+	assert.NilError(t, w.Close())
+	assert.NilError(t, w.Close())
+}
@@ -0,0 +1,54 @@
+package logstream
+
+import (
+	"strings"
+
+	"github.com/docker/cli/internal/lazyregexp"
+)
+
+// levelNames are the log levels accepted by the --level option, ordered
+// by severity.
+var levelNames = []string{"trace", "debug", "info", "warn", "error", "fatal"}
+
+// levels maps log levels, and their common aliases, to their severity.
+var levels = map[string]int{
+	"trace":    0,
+	"debug":    1,
+	"info":     2,
+	"notice":   2,
+	"warn":     3,
+	"warning":  3,
+	"error":    4,
+	"err":      4,
+	"fatal":    5,
+	"panic":    5,
+	"crit":     5,
+	"critical": 5,
+}
+
+var (
+	// levelField matches log levels in structured log lines, such as
+	// `level=error` (logfmt), or `"level":"error"` (JSON).
+	levelField = lazyregexp.New(`(?i)\b(?:level|lvl|severity)"?\s*[:=]\s*"?([a-z]+)`)
+
+	// levelWord matches log levels in unstructured log lines, such as
+	// `ERROR: something failed`, or `[error] something failed`.
+	levelWord = lazyregexp.New(`\b(TRACE|DEBUG|INFO|NOTICE|WARN|WARNING|ERROR|ERR|FATAL|PANIC|CRIT|CRITICAL)\b|\[(?i:(trace|debug|info|notice|warn|warning|error|err|fatal|panic|crit|critical))\]`)
+)
+
+// DetectLevel returns the log level of a log message, or an empty string if
+// no log level is found. The returned level is one of "trace", "debug",
+// "info", "warn", "error", or "fatal".
+func DetectLevel(message string) string {
+	var name string
+	if m := levelField.FindStringSubmatch(message); m != nil {
+		name = m[1]
+	} else if m := levelWord.FindStringSubmatch(message); m != nil {
+		name = m[1] + m[2]
+	}
+	lvl, ok := levels[strings.ToLower(name)]
+	if !ok {
+		return ""
+	}
+	return levelNames[lvl]
+}
@@ -0,0 +1,219 @@
+// Package logstream formats and filters the log output of containers and
+// services.
+package logstream
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/docker/cli/templates"
+)
+
+// timestampFormat is the format used by the daemon for log timestamps.
+const timestampFormat = "2006-01-02T15:04:05.000000000Z07:00"
+
+// Entry is a single line of log output.
+type Entry struct {
+	// Container is the name of the container, or task that produced the
+	// log line.
+	Container string `json:"container,omitempty"`
+	// Node is the name of the node the task is running on (service logs).
+	Node      string            `json:"node,omitempty"`
+	Stream    string            `json:"stream"`
+	Timestamp time.Time         `json:"timestamp"`
+	Level     string            `json:"level,omitempty"`
+	Message   string            `json:"message"`
+	Details   map[string]string `json:"details,omitempty"`
+}
+
+// Options are the options for formatting and filtering log output.
+type Options struct {
+	// Format is the format to print log lines in; "json" prints a JSON
+	// object per line, any other non-empty value is used as Go template.
+	// Log lines are printed as-is if Format is empty.
+	Format string
+	// Grep is a regular expression to select log lines to print.
+	Grep string
+	// Level is the minimum log level of log lines to print.
+	Level string
+	// Context is the number of lines to print before and after lines
+	// selected through Grep or Level.
+	Context int
+	// Timestamps and Details define whether timestamps and details are
+	// printed if no Format is set.
+	Timestamps bool
+	Details    bool
+	// TimestampBeforePrefix defines whether the timestamp is printed before
+	// the prefix of a log line, instead of after it.
+	TimestampBeforePrefix bool
+}
+
+// Enabled returns whether log lines must be processed by a [Printer],
+// instead of being printed as-is.
+func (o Options) Enabled() bool {
+	return o.Format != "" || o.Grep != "" || o.Level != ""
+}
+
+// Printer prints log entries that are selected by the options.
+type Printer struct {
+	opts    Options
+	out     io.Writer
+	err     io.Writer
+	grep    *regexp.Regexp
+	level   int
+	tmpl    *template.Template
+	sources map[string]*sourceState
+}
+
+type pendingEntry struct {
+	entry  Entry
+	prefix string
+}
+
+// sourceState tracks the context lines of a container or task.
+type sourceState struct {
+	before []pendingEntry
+	after  int
+}
+
+// NewPrinter returns a Printer that prints entries of the "stdout" stream
+// to out, and entries of the "stderr" stream to err. All entries are
+// printed to out if a format is set.
+func NewPrinter(out, err io.Writer, opts Options) (*Printer, error) {
+	p := &Printer{
+		opts:    opts,
+		out:     out,
+		err:     err,
+		level:   -1,
+		sources: make(map[string]*sourceState),
+	}
+	if opts.Context < 0 {
+		return nil, errors.New("the number of context lines must be positive")
+	}
+	if opts.Grep != "" {
+		re, err := regexp.Compile(opts.Grep)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --grep expression: %w", err)
+		}
+		p.grep = re
+	}
+	if opts.Level != "" {
+		lvl, ok := levels[strings.ToLower(opts.Level)]
+		if !ok {
+			return nil, fmt.Errorf("invalid --level %q: must be one of %s", opts.Level, strings.Join(levelNames, ", "))
+		}
+		p.level = lvl
+	}
+	if opts.Format != "" && opts.Format != "json" {
+		tmpl, err := templates.Parse(opts.Format)
+		if err != nil {
+			return nil, fmt.Errorf("template parsing error: %w", err)
+		}
+		p.tmpl = tmpl
+	}
+	return p, nil
+}
+
+// Print prints the entry if it is selected by the options, or if it is a
+// context line of a selected entry. The prefix is printed before the entry
+// if no format is set. Entries are tracked per container for context lines.
+func (p *Printer) Print(e Entry, prefix string) error {
+	e.Message = strings.TrimRight(e.Message, "\r\n")
+	if e.Level == "" {
+		e.Level = DetectLevel(e.Message)
+	}
+
+	state := p.sources[e.Container]
+	if state == nil {
+		state = &sourceState{}
+		p.sources[e.Container] = state
+	}
+	switch {
+	case p.selected(e):
+		for _, pe := range state.before {
+			if err := p.write(pe.entry, pe.prefix); err != nil {
+				return err
+			}
+		}
+		state.before = state.before[:0]
+		state.after = p.opts.Context
+		return p.write(e, prefix)
+	case state.after > 0:
+		state.after--
+		return p.write(e, prefix)
+	case p.opts.Context > 0:
+		state.before = append(state.before, pendingEntry{entry: e, prefix: prefix})
+		if len(state.before) > p.opts.Context {
+			state.before = state.before[1:]
+		}
+	}
+	return nil
+}
+
+func (p *Printer) selected(e Entry) bool {
+	if p.grep != nil && !p.grep.MatchString(e.Message) {
+		return false
+	}
+	if p.level >= 0 {
+		lvl, ok := levels[e.Level]
+		if !ok || lvl < p.level {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *Printer) write(e Entry, prefix string) error {
+	switch {
+	case p.opts.Format == "json":
+		b, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(p.out, string(b))
+		return err
+	case p.tmpl != nil:
+		var b strings.Builder
+		if err := p.tmpl.Execute(&b, e); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintln(p.out, b.String())
+		return err
+	}
+
+	var b strings.Builder
+	if !p.opts.TimestampBeforePrefix {
+		b.WriteString(prefix)
+	}
+	if p.opts.Timestamps && !e.Timestamp.IsZero() {
+		b.WriteString(e.Timestamp.Format(timestampFormat))
+		b.WriteByte(' ')
+	}
+	if p.opts.TimestampBeforePrefix {
+		b.WriteString(prefix)
+	}
+	if p.opts.Details && len(e.Details) > 0 {
+		d := make([]string, 0, len(e.Details))
+		for k, v := range e.Details {
+			d = append(d, k+"="+v)
+		}
+		sort.Strings(d)
+		b.WriteString(strings.Join(d, ","))
+		b.WriteByte(' ')
+	}
+	b.WriteString(e.Message)
+	b.WriteByte('\n')
+	w := p.out
+	if e.Stream == "stderr" {
+		w = p.err
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,108 @@
+package logstream
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestDetectLevel(t *testing.T) {
+	tests := []struct {
+		message  string
+		expected string
+	}{
+		{message: `time="2024-01-01T00:00:00Z" level=warning msg="disk almost full"`, expected: "warn"},
+		{message: `{"level":"error","msg":"failed"}`, expected: "error"},
+		{message: `{"severity": "DEBUG", "message": "hello"}`, expected: "debug"},
+		{message: `2024/01/01 00:00:00 [notice] 1#1: start worker processes`, expected: "info"},
+		{message: `ERROR: connection refused`, expected: "error"},
+		{message: `FATAL something went wrong`, expected: "fatal"},
+		{message: `no error occurred`, expected: ""},
+		{message: `level=verbose`, expected: ""},
+		{message: `hello world`, expected: ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.message, func(t *testing.T) {
+			assert.Check(t, is.Equal(DetectLevel(tc.message), tc.expected))
+		})
+	}
+}
+
+func TestPrinterGrep(t *testing.T) {
+	var out, errOut bytes.Buffer
+	p, err := NewPrinter(&out, &errOut, Options{Grep: "fail(ed|ure)", Context: 1})
+	assert.NilError(t, err)
+
+	for _, e := range []Entry{
+		{Container: "web", Stream: "stdout", Message: "one\n"},
+		{Container: "web", Stream: "stdout", Message: "two\n"},
+		{Container: "db", Stream: "stdout", Message: "db one\n"},
+		{Container: "web", Stream: "stderr", Message: "request failed\n"},
+		{Container: "web", Stream: "stdout", Message: "three\n"},
+		{Container: "web", Stream: "stdout", Message: "four\n"},
+		{Container: "db", Stream: "stderr", Message: "failure\n"},
+	} {
+		assert.NilError(t, p.Print(e, e.Container+" | "))
+	}
+	assert.Check(t, is.Equal(out.String(), "web | two\nweb | three\ndb | db one\n"))
+	assert.Check(t, is.Equal(errOut.String(), "web | request failed\ndb | failure\n"))
+}
+
+func TestPrinterLevel(t *testing.T) {
+	var out bytes.Buffer
+	p, err := NewPrinter(&out, &out, Options{Level: "WARN"})
+	assert.NilError(t, err)
+
+	for _, msg := range []string{"level=info msg=hello", "level=warn msg=careful", "unknown", "[error] broken"} {
+		assert.NilError(t, p.Print(Entry{Stream: "stdout", Message: msg}, ""))
+	}
+	assert.Check(t, is.Equal(out.String(), "level=warn msg=careful\n[error] broken\n"))
+}
+
+func TestPrinterFormat(t *testing.T) {
+	e := Entry{
+		Container: "web",
+		Stream:    "stderr",
+		Timestamp: time.Date(2024, 1, 1, 0, 0, 1, 0, time.UTC),
+		Message:   "level=error msg=failed\n",
+		Details:   map[string]string{"env": "production"},
+	}
+
+	var out bytes.Buffer
+	p, err := NewPrinter(&out, nil, Options{Format: "json"})
+	assert.NilError(t, err)
+	assert.NilError(t, p.Print(e, "ignored"))
+	assert.Check(t, is.Equal(out.String(), `{"container":"web","stream":"stderr","timestamp":"2024-01-01T00:00:01Z","level":"error","message":"level=error msg=failed","details":{"env":"production"}}`+"\n"))
+
+	out.Reset()
+	p, err = NewPrinter(&out, nil, Options{Format: "{{.Container}} [{{.Level}}] {{.Message}}"})
+	assert.NilError(t, err)
+	assert.NilError(t, p.Print(e, "ignored"))
+	assert.Check(t, is.Equal(out.String(), "web [error] level=error msg=failed\n"))
+
+	out.Reset()
+	p, err = NewPrinter(&out, &out, Options{Grep: ".", Timestamps: true, Details: true})
+	assert.NilError(t, err)
+	assert.NilError(t, p.Print(e, "web | "))
+	assert.Check(t, is.Equal(out.String(), "web | 2024-01-01T00:00:01.000000000Z env=production level=error msg=failed\n"))
+
+	out.Reset()
+	p, err = NewPrinter(&out, &out, Options{Grep: ".", Timestamps: true, TimestampBeforePrefix: true})
+	assert.NilError(t, err)
+	assert.NilError(t, p.Print(e, "web | "))
+	assert.Check(t, is.Equal(out.String(), "2024-01-01T00:00:01.000000000Z web | level=error msg=failed\n"))
+}
+
+func TestNewPrinterErrors(t *testing.T) {
+	_, err := NewPrinter(nil, nil, Options{Grep: "("})
+	assert.Check(t, is.ErrorContains(err, "invalid --grep expression"))
+
+	_, err = NewPrinter(nil, nil, Options{Level: "loud"})
+	assert.Check(t, is.Error(err, `invalid --level "loud": must be one of trace, debug, info, warn, error, fatal`))
+
+	_, err = NewPrinter(nil, nil, Options{Format: "{{.Message"})
+	assert.Check(t, is.ErrorContains(err, "template parsing error"))
+}
@@ -0,0 +1,180 @@
+package prompt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+)
+
+// ErrNoSelection is returned by [Select] if the user did not select an option.
+const ErrNoSelection cancelledErr = "no option selected"
+
+// Option is an option that can be selected with [Select].
+type Option struct {
+	// Value is the value that is returned if the option is selected.
+	Value string
+
+	// Description is additional information that is shown for the option.
+	// It can contain tabs to align columns.
+	Description string
+}
+
+// Select requests the user to select one of the provided options, or one or
+// more of the options if multiple is true, and returns their values.
+//
+// The options are listed with a number, after the message, which should not
+// end with a colon. The user selects options by entering their numbers, such
+// as "2", "1,3", or "2-4", or all options with "*". Other input is used to
+// search the options; options that do not match the search are hidden, and
+// the option is selected if only one matches and multiple is false. Input
+// that starts with "/" is always used to search, so that options can be
+// searched for numbers, such as "/2048". Entering nothing clears the search,
+// or returns [ErrNoSelection] if no search is active.
+//
+// It returns an [ErrTerminated] if the user terminates the CLI with SIGINT or
+// SIGTERM while the prompt is active. Each line of input is read with
+// [ReadInput]; refer to its documentation for handling errors.
+func Select(ctx context.Context, in io.Reader, out io.Writer, message string, options []Option, multiple bool) ([]string, error) {
+	if len(options) == 0 {
+		return nil, ErrNoSelection
+	}
+	hint := "Enter a number, or text to search: "
+	if multiple {
+		hint = "Enter numbers (e.g. 1,3 or 2-4, * for all), or text to search: "
+	}
+
+	var search string
+	shown := options
+	for {
+		if search == "" {
+			_, _ = fmt.Fprintf(out, "%s:\n", message)
+		} else {
+			_, _ = fmt.Fprintf(out, "%s (matching %q):\n", message, search)
+		}
+		tw := tabwriter.NewWriter(out, 0, 1, 2, ' ', 0)
+		for i, o := range shown {
+			_, _ = fmt.Fprintf(tw, "%4d  %s\t%s\n", i+1, o.Value, o.Description)
+		}
+		_ = tw.Flush()
+
+		input, err := ReadInput(ctx, in, out, hint)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case input == "" && search == "":
+			return nil, ErrNoSelection
+		case input == "":
+			search, shown = "", options
+			continue
+		case input == "*" && multiple:
+			return optionValues(shown), nil
+		}
+
+		query, isSearch := strings.CutPrefix(input, "/")
+		if query == "" {
+			search, shown = "", options
+			continue
+		}
+		if selected, ok := parseSelection(input, len(shown)); ok && !isSearch {
+			switch {
+			case selected == nil:
+				_, _ = fmt.Fprintf(out, "Invalid selection: %s\n", input)
+			case len(selected) > 1 && !multiple:
+				_, _ = fmt.Fprintln(out, "Select a single option")
+			default:
+				values := make([]string, 0, len(selected))
+				for _, i := range selected {
+					values = append(values, shown[i].Value)
+				}
+				return values, nil
+			}
+			continue
+		}
+
+		var matches []Option
+		for _, o := range options {
+			if fuzzyMatch(query, o.Value+" "+o.Description) {
+				matches = append(matches, o)
+			}
+		}
+		switch {
+		case len(matches) == 0:
+			_, _ = fmt.Fprintf(out, "No options match %q\n", query)
+		case len(matches) == 1 && !multiple:
+			return optionValues(matches), nil
+		default:
+			search, shown = query, matches
+		}
+	}
+}
+
+func optionValues(options []Option) []string {
+	values := make([]string, 0, len(options))
+	for _, o := range options {
+		values = append(values, o.Value)
+	}
+	return values
+}
+
+// parseSelection parses a selection of numbers and ranges of numbers,
+// separated by commas or spaces, and returns the zero-based indexes of the
+// selected options. It returns false if the input is not a selection, and a
+// nil slice if the selection is out of range.
+func parseSelection(input string, count int) ([]int, bool) {
+	fields := strings.FieldsFunc(input, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+	var selected []int
+	seen := make(map[int]struct{})
+	valid := true
+	for _, f := range fields {
+		from, to, isRange := strings.Cut(f, "-")
+		if !isRange {
+			to = from
+		}
+		start, err := strconv.Atoi(from)
+		if err != nil {
+			return nil, false
+		}
+		end, err := strconv.Atoi(to)
+		if err != nil {
+			return nil, false
+		}
+		if start < 1 || end > count || start > end {
+			valid = false
+			continue
+		}
+		for i := start - 1; i < end; i++ {
+			if _, ok := seen[i]; !ok {
+				seen[i] = struct{}{}
+				selected = append(selected, i)
+			}
+		}
+	}
+	if !valid {
+		return nil, true
+	}
+	return selected, true
+}
+
+// fuzzyMatch returns whether the characters of the search, ignoring spaces,
+// occur in s in the same order, ignoring case.
+func fuzzyMatch(search, s string) bool {
+	s = strings.ToLower(s)
+	for _, r := range strings.ToLower(search) {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		i := strings.IndexRune(s, r)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(string(r)):]
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+package prompt_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/internal/prompt"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// selectWithInput runs [prompt.Select], writing each line of input when the
+// previous line is read.
+func selectWithInput(t *testing.T, multiple bool, lines ...string) ([]string, string, error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	reader, writer := io.Pipe()
+	t.Cleanup(func() { _ = reader.Close() })
+	go func() {
+		for _, l := range lines {
+			if _, err := writer.Write([]byte(l + "\n")); err != nil {
+				return
+			}
+		}
+	}()
+
+	options := []prompt.Option{
+		{Value: "web", Description: "nginx:alpine\tUp 2 hours"},
+		{Value: "db", Description: "postgres:16\tUp 2 hours"},
+		{Value: "worker", Description: "busybox\tExited (0) 3 minutes ago"},
+	}
+	out := new(bytes.Buffer)
+	values, err := prompt.Select(ctx, reader, out, "Select a container", options, multiple)
+	return values, out.String(), err
+}
+
+func TestSelect(t *testing.T) {
+	t.Run("number", func(t *testing.T) {
+		values, out, err := selectWithInput(t, false, "2")
+		assert.NilError(t, err)
+		assert.Check(t, is.DeepEqual(values, []string{"db"}))
+		expected := `Select a container:
+   1  web     nginx:alpine  Up 2 hours
+   2  db      postgres:16   Up 2 hours
+   3  worker  busybox       Exited (0) 3 minutes ago
+Enter a number, or text to search: `
+		assert.Check(t, is.Equal(out, expected))
+	})
+
+	t.Run("search with single match", func(t *testing.T) {
+		values, _, err := selectWithInput(t, false, "wrk")
+		assert.NilError(t, err)
+		assert.Check(t, is.DeepEqual(values, []string{"worker"}))
+	})
+
+	t.Run("search then number", func(t *testing.T) {
+		values, out, err := selectWithInput(t, false, "up", "2")
+		assert.NilError(t, err)
+		assert.Check(t, is.DeepEqual(values, []string{"db"}))
+		assert.Check(t, is.Contains(out, `Select a container (matching "up"):`))
+	})
+
+	t.Run("invalid selections", func(t *testing.T) {
+		values, out, err := selectWithInput(t, false, "4", "1,3", "nomatch", "3")
+		assert.NilError(t, err)
+		assert.Check(t, is.DeepEqual(values, []string{"worker"}))
+		assert.Check(t, is.Contains(out, "Invalid selection: 4\n"))
+		assert.Check(t, is.Contains(out, "Select a single option\n"))
+		assert.Check(t, is.Contains(out, `No options match "nomatch"`))
+	})
+
+	t.Run("search for number", func(t *testing.T) {
+		values, out, err := selectWithInput(t, false, "/16")
+		assert.NilError(t, err)
+		assert.Check(t, is.DeepEqual(values, []string{"db"}))
+		assert.Check(t, !strings.Contains(out, "Invalid selection"))
+	})
+
+	t.Run("multiple", func(t *testing.T) {
+		values, _, err := selectWithInput(t, true, "3, 1-2")
+		assert.NilError(t, err)
+		assert.Check(t, is.DeepEqual(values, []string{"worker", "web", "db"}))
+	})
+
+	t.Run("all matching", func(t *testing.T) {
+		values, _, err := selectWithInput(t, true, "up", "*")
+		assert.NilError(t, err)
+		assert.Check(t, is.DeepEqual(values, []string{"web", "db"}))
+	})
+
+	t.Run("clear search and cancel", func(t *testing.T) {
+		_, _, err := selectWithInput(t, true, "up", "", "")
+		assert.Check(t, is.ErrorIs(err, prompt.ErrNoSelection))
+	})
+}
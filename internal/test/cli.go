@@ -38,6 +38,7 @@ type FakeCli struct {
 	contentTrust     bool
 	contextStore     store.Store
 	currentContext   string
+	contextSource    command.ContextSource
 	dockerEndpoint   docker.Endpoint
 }
 
@@ -56,6 +57,7 @@ func NewFakeCli(apiClient client.APIClient, opts ...func(*FakeCli)) *FakeCli {
 		// Set cli.ConfigFile().Filename to a tempfile to support Save.
 		configfile:     configfile.New(""),
 		currentContext: command.DefaultContextName,
+		contextSource:  command.ContextSource{Kind: command.ContextSourceDefault},
 	}
 	for _, opt := range opts {
 		opt(c)
@@ -138,6 +140,16 @@ func (c *FakeCli) CurrentContext() string {
 	return c.currentContext
 }
 
+// SetCurrentContextSource sets the "fake" source of the current context
+func (c *FakeCli) SetCurrentContextSource(source command.ContextSource) {
+	c.contextSource = source
+}
+
+// CurrentContextSource returns the source of the current context
+func (c *FakeCli) CurrentContextSource() command.ContextSource {
+	return c.contextSource
+}
+
 // DockerEndpoint returns the current DockerEndpoint
 func (c *FakeCli) DockerEndpoint() docker.Endpoint {
 	return c.dockerEndpoint
@@ -0,0 +1,56 @@
+// Package timestamp parses timestamps in the formats that the daemon accepts
+// for filters and options such as "--since" and "--until".
+package timestamp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// layouts are the layouts of timestamps accepted by [Parse], in addition to
+// durations and Unix timestamps. Timestamps without a time zone are in the
+// local time zone.
+var layouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04",
+	"2006-01-02T15Z07:00",
+	"2006-01-02T15",
+	"2006-01-02Z07:00",
+	"2006-01-02",
+}
+
+// Parse parses a timestamp, such as the value of a "--since" or "--until"
+// option, in the same formats as the daemon; a duration relative to now,
+// such as "10m", a Unix timestamp, or an RFC 3339 date or date and time.
+func Parse(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); value != "0" && err == nil {
+		return now.Add(-d), nil
+	}
+	if sec, nsec, ok := strings.Cut(value, "."); !strings.Contains(value, "-") {
+		s, err := strconv.ParseInt(sec, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to parse value as time or duration: %q", value)
+		}
+		var ns int64
+		if ok {
+			if len(nsec) > 9 {
+				nsec = nsec[:9]
+			}
+			ns, err = strconv.ParseInt(nsec+strings.Repeat("0", 9-len(nsec)), 10, 64)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("failed to parse value as time or duration: %q", value)
+			}
+		}
+		return time.Unix(s, ns), nil
+	}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("failed to parse value as time or duration: %q", value)
+}
@@ -0,0 +1,32 @@
+package timestamp
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestParse(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value    string
+		expected time.Time
+	}{
+		{value: "10m", expected: now.Add(-10 * time.Minute)},
+		{value: "1714564800", expected: time.Unix(1714564800, 0)},
+		{value: "1714564800.5", expected: time.Unix(1714564800, 500000000)},
+		{value: "2024-05-01T11:30:00Z", expected: time.Date(2024, 5, 1, 11, 30, 0, 0, time.UTC)},
+		{value: "2024-05-01T11:30", expected: time.Date(2024, 5, 1, 11, 30, 0, 0, time.UTC)},
+		{value: "2024-04-30", expected: time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range tests {
+		actual, err := Parse(tc.value, now)
+		assert.NilError(t, err, tc.value)
+		assert.Check(t, actual.Equal(tc.expected), "%s: %s", tc.value, actual)
+	}
+
+	_, err := Parse("yesterday", now)
+	assert.Check(t, is.ErrorContains(err, "failed to parse value as time or duration"))
+}